@@ -20,6 +20,7 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -31,32 +32,51 @@ import (
 
 var configFormats = []string{"yaml", "yml", "properties"}
 
-type configLoaderFunc func(string, string) (yamlLoadResult, error)
+type configLoaderFunc func(string, string, includeChain) (yamlLoadResult, error)
 
-var configLoaders = map[string]configLoaderFunc{
-	"yaml":       loadYamlFile,
-	"yml":        loadYamlFile,
-	"properties": loadPropertiesFile,
+var configLoaders map[string]configLoaderFunc
+
+func init() {
+	// assigned in init() because loaders refer configLoaders while loading include fragments
+	configLoaders = map[string]configLoaderFunc{
+		"yaml":       loadYamlFile,
+		"yml":        loadYamlFile,
+		"properties": loadPropertiesFile,
+	}
 }
 
-func loadPropertiesFile(path string, _ string) (yamlLoadResult, error) {
-	values, err := readProperties(path)
+func loadPropertiesFile(path string, profile string, chain includeChain) (yamlLoadResult, error) {
+	chain, err := chain.enter(path)
+	if err != nil {
+		return yamlLoadResult{}, err
+	}
+
+	values, includes, err := parseProperties(path)
 	if err != nil {
 		return yamlLoadResult{}, err
 	}
-	return yamlLoadResult{
+	own := yamlLoadResult{
 		Values:      values,
 		ListKeys:    make(map[string]bool),
 		SkippedKeys: make(map[string]bool),
-	}, nil
+	}
+	return mergeWithIncludes(own, includes, profile, chain, filepath.Base(path))
 }
 
 // LoadedApplicationConfig is the result of LoadApplicationConfig.
 type LoadedApplicationConfig struct {
 	Values          map[string]string
-	Format          string          // "yaml" | "yml" | "properties" | "" (no file found)
-	YamlListKeys    map[string]bool // keys whose original yaml value was a scalar list
-	YamlSkippedKeys map[string]bool // keys skipped due to complex yaml types
+	Format          string            // "yaml" | "yml" | "properties" | "" (no file found)
+	YamlListKeys    map[string]bool   // keys whose original yaml value was a scalar list
+	YamlSkippedKeys map[string]bool   // keys skipped due to complex yaml types
+	ResolveErrors   []error           // predefine expression errors (reference cycle, unresolved placeholder in strict mode)
+	LoadErrors      []error           // include errors (cycle, missing fragment). process should not start with partial config
+	Templates       map[string]string // raw values referring dynamic builtin (e.g. date). resolved on every lookup
+}
+
+// dynamicExpressionChecker predefines which can tell whether value should be resolved on every lookup
+type dynamicExpressionChecker interface {
+	IsDynamicExpression(value string) bool
 }
 
 // LoadApplicationConfig loads merged application config from appDir.
@@ -64,9 +84,14 @@ type LoadedApplicationConfig struct {
 // If the base file contains multiple YAML documents (separated by ---), multi-doc mode is used:
 // documents without fatima.profile are treated as base; the document matching the given profile
 // is merged on top. Separate profile override files are ignored in multi-doc mode.
+// Shared fragments can be pulled in with a top level 'include' (or 'fatima.import') key in yaml
+// or an '@include <file>' line in properties. Relative include paths are resolved from $FATIMA_HOME/conf.
+// Included values have lower precedence than the including file; later includes override earlier ones.
 // If the base file is a single document, the original behaviour applies: base file is loaded first,
 // then the profile override file (application.<profile>.<ext>) is merged on top.
 // predefines may be nil; if provided, ${var.*} placeholders are resolved after loading.
+// If predefines implements PredefineExpressionResolver, resolve errors are reported in ResolveErrors.
+// Include errors are reported in LoadErrors.
 func LoadApplicationConfig(appDir string, profile string, predefines fatima.Predefines) LoadedApplicationConfig {
 	chosenExt := resolveConfigFormat(appDir, profile)
 	if chosenExt == "" {
@@ -80,12 +105,17 @@ func LoadApplicationConfig(appDir string, profile string, predefines fatima.Pred
 
 	loader := configLoaders[chosenExt]
 	merged := newYamlLoadResult()
+	loadErrors := make([]error, 0)
+	var includeErr *configIncludeError
 
 	basePath := filepath.Join(appDir, "application."+chosenExt)
 	if checkFileAvailable(basePath) {
 		log.Info("loading base config: %s", filepath.Base(basePath))
-		if r, err := loader(basePath, profile); err != nil {
+		if r, err := loader(basePath, profile, nil); err != nil {
 			log.Warn("cannot load base config %s: %s", filepath.Base(basePath), err.Error())
+			if errors.As(err, &includeErr) {
+				loadErrors = append(loadErrors, fmt.Errorf("base config %s : %w", filepath.Base(basePath), err))
+			}
 		} else {
 			merged.IsMultiDoc = r.IsMultiDoc
 			mergeFlattened(&merged, r, "")
@@ -98,21 +128,40 @@ func LoadApplicationConfig(appDir string, profile string, predefines fatima.Pred
 		overridePath := filepath.Join(appDir, fmt.Sprintf("application.%s.%s", profile, chosenExt))
 		if checkFileAvailable(overridePath) {
 			log.Info("applying profile override: %s", filepath.Base(overridePath))
-			if r, err := loader(overridePath, profile); err != nil {
+			if r, err := loader(overridePath, profile, nil); err != nil {
 				log.Warn("cannot load profile config %s: %s", filepath.Base(overridePath), err.Error())
+				if errors.As(err, &includeErr) {
+					loadErrors = append(loadErrors, fmt.Errorf("profile config %s : %w", filepath.Base(overridePath), err))
+				}
 			} else {
 				mergeFlattened(&merged, r, "profile file "+filepath.Base(overridePath))
 			}
 		}
 	}
 
+	resolveErrors := make([]error, 0)
+	templates := make(map[string]string)
+	checker, _ := predefines.(dynamicExpressionChecker)
 	for k, v := range merged.Values {
-		if predefines != nil {
+		if checker != nil && !strings.HasSuffix(k, SecretKeySuffix) && checker.IsDynamicExpression(v) {
+			templates[k] = v
+		}
+		if resolver, ok := predefines.(PredefineExpressionResolver); ok {
+			resolved, err := resolver.ResolveExpression(v)
+			if err != nil {
+				resolveErrors = append(resolveErrors, fmt.Errorf("config key %s : %w", k, err))
+			}
+			v = resolved
+		} else if predefines != nil {
 			v = predefines.ResolvePredefine(v)
 		}
 		if strings.HasSuffix(k, SecretKeySuffix) {
 			v = crypt.ResolveSecret(v)
 		}
+		if crypt.IsSecretKey(k) {
+			// plaintext secret (without scheme) is redacted too
+			crypt.RegisterSecretValue(v)
+		}
 		merged.Values[k] = v
 	}
 
@@ -121,7 +170,18 @@ func LoadApplicationConfig(appDir string, profile string, predefines fatima.Pred
 		Format:          chosenExt,
 		YamlListKeys:    merged.ListKeys,
 		YamlSkippedKeys: merged.SkippedKeys,
+		ResolveErrors:   resolveErrors,
+		LoadErrors:      loadErrors,
+		Templates:       templates,
+	}
+}
+
+// Err return joined include and predefine resolve errors or nil
+func (c LoadedApplicationConfig) Err() error {
+	if len(c.LoadErrors) == 0 && len(c.ResolveErrors) == 0 {
+		return nil
 	}
+	return errors.Join(append(append([]error(nil), c.LoadErrors...), c.ResolveErrors...)...)
 }
 
 // resolveConfigFormat determines which config format to use by checking base files first,
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	fatima "github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/crypt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -353,6 +354,16 @@ func TestLoadApplicationConfig(t *testing.T) {
 			profile: "",
 			want:    map[string]string{"foo": "second", "bar": "baz"},
 		},
+		{
+			name: "plaintext_secret_registered",
+			setup: func(dir string) {
+				writeTestFile(t, dir, "application.properties", "db.password.secret=plaintext-pass\n")
+			},
+			check: func(t *testing.T, result map[string]string) {
+				assert.Equal(t, "plaintext-pass", result["db.password.secret"])
+				assert.Equal(t, "password is "+crypt.SecretMask, crypt.Redact("password is plaintext-pass"))
+			},
+		},
 	}
 
 	for _, tt := range tests {
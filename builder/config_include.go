@@ -0,0 +1,167 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 19. 오전 10:20
+ */
+
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	fatima "github.com/fatima-go/fatima-core"
+	log "github.com/fatima-go/fatima-log"
+)
+
+const (
+	yamlKeyInclude         = "include" // top level include key
+	yamlKeyFatimaImport    = "import"  // fatima.import
+	propertiesIncludeToken = "@include"
+)
+
+// includeChain is the list of (absolute) config files currently being loaded.
+// used for include cycle detection
+type includeChain []string
+
+func (c includeChain) enter(path string) (includeChain, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	for _, v := range c {
+		if v == abs {
+			names := make([]string, 0, len(c)+1)
+			for _, p := range c {
+				names = append(names, filepath.Base(p))
+			}
+			names = append(names, filepath.Base(abs))
+			return c, fmt.Errorf("config include cycle detected : %s", strings.Join(names, " -> "))
+		}
+	}
+	next := make(includeChain, len(c), len(c)+1)
+	copy(next, c)
+	return append(next, abs), nil
+}
+
+// configIncludeError include fragment cannot be loaded (cycle, not found, ...). config loading fails with it
+type configIncludeError struct {
+	err error
+}
+
+func (e *configIncludeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configIncludeError) Unwrap() error {
+	return e.err
+}
+
+// getConfigIncludeFolder return folder which shared config fragments reside ($FATIMA_HOME/conf)
+func getConfigIncludeFolder() string {
+	return filepath.Join(os.Getenv(fatima.ENV_FATIMA_HOME), FatimaFolderConf)
+}
+
+// resolveIncludePath absolute path is used as is. relative path is resolved from $FATIMA_HOME/conf
+func resolveIncludePath(include string) string {
+	if filepath.IsAbs(include) {
+		return include
+	}
+	return filepath.Join(getConfigIncludeFolder(), include)
+}
+
+// loadIncludes load and merge include fragments in declared order (later one wins).
+// fragment format is determined by file extension using configLoaders
+func loadIncludes(includes []string, profile string, chain includeChain) (yamlLoadResult, error) {
+	merged := newYamlLoadResult()
+	for _, include := range includes {
+		includePath := resolveIncludePath(include)
+		ext := strings.TrimPrefix(filepath.Ext(includePath), ".")
+		loader, ok := configLoaders[ext]
+		if !ok {
+			return merged, &configIncludeError{fmt.Errorf("unsupported include file format : %s", include)}
+		}
+		if !checkFileAvailable(includePath) {
+			return merged, &configIncludeError{fmt.Errorf("not found include file : %s", includePath)}
+		}
+		log.Info("including config fragment : %s", include)
+		r, err := loader(includePath, profile, chain)
+		if err != nil {
+			return merged, &configIncludeError{fmt.Errorf("fail to load include %s : %w", include, err)}
+		}
+		mergeFlattened(&merged, r, "include "+filepath.Base(includePath))
+	}
+	return merged, nil
+}
+
+// mergeWithIncludes merge own values on top of include fragments
+func mergeWithIncludes(own yamlLoadResult, includes []string, profile string, chain includeChain, sourceLabel string) (yamlLoadResult, error) {
+	if len(includes) == 0 {
+		return own, nil
+	}
+	merged, err := loadIncludes(includes, profile, chain)
+	if err != nil {
+		return merged, err
+	}
+	merged.IsMultiDoc = own.IsMultiDoc
+	mergeFlattened(&merged, own, sourceLabel)
+	return merged, nil
+}
+
+// extractAndStripIncludes reads include (top level) and fatima.import from the document and removes them.
+// value can be a string or a list of strings
+func extractAndStripIncludes(doc map[string]any) []string {
+	includes := make([]string, 0)
+	if v, ok := doc[yamlKeyInclude]; ok {
+		includes = append(includes, toIncludeList(v)...)
+		delete(doc, yamlKeyInclude)
+	}
+
+	fatimaMap, ok := doc["fatima"].(map[string]any)
+	if !ok {
+		return includes
+	}
+	if v, ok := fatimaMap[yamlKeyFatimaImport]; ok {
+		includes = append(includes, toIncludeList(v)...)
+		delete(fatimaMap, yamlKeyFatimaImport)
+		if len(fatimaMap) == 0 {
+			delete(doc, "fatima")
+		}
+	}
+	return includes
+}
+
+func toIncludeList(v any) []string {
+	list := make([]string, 0)
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			list = append(list, s)
+		}
+	case []any:
+		for _, e := range t {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", e)); s != "" && e != nil {
+				list = append(list, s)
+			}
+		}
+	default:
+		log.Warn("invalid include value type : %v", v)
+	}
+	return list
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 19. 오전 11:05
+ */
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	fatima "github.com/fatima-go/fatima-core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadApplicationConfigInclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(appDir, confDir string)
+		profile string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "yaml_include_list",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "db.yaml", "db:\n  host: shared\n  port: 5432\n")
+				writeTestFile(t, confDir, "broker.properties", "broker.url=amqp://shared\n")
+				writeTestFile(t, appDir, "application.yaml",
+					"include:\n  - db.yaml\n  - broker.properties\ndb:\n  host: mine\n")
+			},
+			want: map[string]string{"db.host": "mine", "db.port": "5432", "broker.url": "amqp://shared"},
+		},
+		{
+			name: "yaml_fatima_import",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "db.yaml", "db:\n  host: shared\n")
+				writeTestFile(t, appDir, "application.yaml", "fatima:\n  import: db.yaml\nkey1: val1\n")
+			},
+			want: map[string]string{"db.host": "shared", "key1": "val1"},
+		},
+		{
+			name: "later_include_wins",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "a.properties", "k=a\n")
+				writeTestFile(t, confDir, "b.properties", "k=b\n")
+				writeTestFile(t, appDir, "application.properties", "@include a.properties\n@include b.properties\nother=x\n")
+			},
+			want: map[string]string{"k": "b", "other": "x"},
+		},
+		{
+			name: "profile_override_beats_include",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "db.yaml", "db:\n  host: shared\n")
+				writeTestFile(t, appDir, "application.yaml",
+					"include: db.yaml\n---\nfatima:\n  profile: dev\ndb:\n  host: devhost\n")
+			},
+			profile: "dev",
+			want:    map[string]string{"db.host": "devhost"},
+		},
+		{
+			name: "nested_include",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "inner.properties", "inner=yes\n")
+				writeTestFile(t, confDir, "outer.yaml", "include: inner.properties\nouter: yes\n")
+				writeTestFile(t, appDir, "application.yaml", "include: outer.yaml\n")
+			},
+			want: map[string]string{"inner": "yes", "outer": "yes"},
+		},
+		{
+			name: "include_cycle_fails",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, confDir, "a.yaml", "include: b.yaml\na: 1\n")
+				writeTestFile(t, confDir, "b.yaml", "include: a.yaml\nb: 1\n")
+				writeTestFile(t, appDir, "application.yaml", "include: a.yaml\nkey: val\n")
+			},
+			want:    map[string]string{},
+			wantErr: "config include cycle detected : application.yaml -> a.yaml -> b.yaml -> a.yaml",
+		},
+		{
+			name: "missing_include_fails",
+			setup: func(appDir, confDir string) {
+				writeTestFile(t, appDir, "application.properties", "@include nothing.properties\nkey=val\n")
+			},
+			want:    map[string]string{},
+			wantErr: "not found include file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv(fatima.ENV_FATIMA_HOME, home)
+			confDir := filepath.Join(home, FatimaFolderConf)
+			appDir := filepath.Join(home, FatimaFolderApp, "myproc")
+			require.NoError(t, os.MkdirAll(confDir, 0755))
+			require.NoError(t, os.MkdirAll(appDir, 0755))
+			tt.setup(appDir, confDir)
+
+			loaded := LoadApplicationConfig(appDir, tt.profile, nil)
+			assert.Equal(t, tt.want, loaded.Values)
+			if tt.wantErr == "" {
+				assert.NoError(t, loaded.Err())
+				return
+			}
+			assert.ErrorContains(t, loaded.Err(), tt.wantErr)
+		})
+	}
+}
+
+func TestIncludeChainCycle(t *testing.T) {
+	chain, err := includeChain(nil).enter("/a/application.yaml")
+	require.NoError(t, err)
+	chain, err = chain.enter("/conf/db.yaml")
+	require.NoError(t, err)
+	_, err = chain.enter("/a/application.yaml")
+	assert.ErrorContains(t, err, "application.yaml -> db.yaml -> application.yaml")
+}
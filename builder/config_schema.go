@@ -0,0 +1,369 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 8:10
+ */
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatima-go/fatima-core/crypt"
+	log "github.com/fatima-go/fatima-log"
+	robfig_cron "github.com/robfig/cron/v3"
+)
+
+type ConfigValueType int
+
+const (
+	ConfigTypeString ConfigValueType = iota
+	ConfigTypeInt
+	ConfigTypeBool
+	ConfigTypeList
+	ConfigTypeCronSpec
+	ConfigTypeFloat
+	ConfigTypeDuration // e.g) 30s, 1h30m, 7d
+	ConfigTypeBytes    // e.g) 64KB, 30MB
+)
+
+func (t ConfigValueType) String() string {
+	switch t {
+	case ConfigTypeString:
+		return "string"
+	case ConfigTypeInt:
+		return "int"
+	case ConfigTypeBool:
+		return "bool"
+	case ConfigTypeList:
+		return "list"
+	case ConfigTypeCronSpec:
+		return "cronspec"
+	case ConfigTypeFloat:
+		return "float"
+	case ConfigTypeDuration:
+		return "duration"
+	case ConfigTypeBytes:
+		return "bytes"
+	}
+	return fmt.Sprintf("unknown(%d)", int(t))
+}
+
+// ConfigKeySpec describes a single application config key.
+// Key is either an exact key or a path.Match pattern (e.g. cron.*.spec)
+type ConfigKeySpec struct {
+	Key         string
+	Type        ConfigValueType
+	Required    bool     // only meaningful for exact keys
+	Allowed     []string // allowed values (case-insensitive). empty means any value
+	Min         *int64   // inclusive lower bound for ConfigTypeInt
+	Max         *int64   // inclusive upper bound for ConfigTypeInt
+	Description string
+	Validate    func(value string) error // optional custom validation
+}
+
+// Bound returns pointer of v. helper for ConfigKeySpec Min/Max
+func Bound(v int64) *int64 {
+	return &v
+}
+
+func (s ConfigKeySpec) isPattern() bool {
+	return strings.ContainsAny(s.Key, "*?[")
+}
+
+func (s ConfigKeySpec) matches(key string) bool {
+	if !s.isPattern() {
+		return s.Key == key
+	}
+	matched, err := path.Match(s.Key, key)
+	return err == nil && matched
+}
+
+// validate checks value against spec type, allowed values, range and custom validator.
+// secret value never appears in the error
+func (s ConfigKeySpec) validate(key, value string) error {
+	err := s.validateValue(key, value)
+	if err != nil && crypt.IsSecretKey(key) {
+		return fmt.Errorf("key %s has invalid %s value (masked)", key, s.Type)
+	}
+	return crypt.RedactError(err)
+}
+
+func (s ConfigKeySpec) validateValue(key, value string) error {
+	switch s.Type {
+	case ConfigTypeInt:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("key %s expects int value : %s", key, value)
+		}
+		if s.Min != nil && i < *s.Min {
+			return fmt.Errorf("key %s value %d is less than %d", key, i, *s.Min)
+		}
+		if s.Max != nil && i > *s.Max {
+			return fmt.Errorf("key %s value %d is greater than %d", key, i, *s.Max)
+		}
+	case ConfigTypeBool:
+		if _, err := ParseConfigBool(value); err != nil {
+			return fmt.Errorf("key %s expects bool value : %s", key, value)
+		}
+	case ConfigTypeFloat:
+		if _, err := ParseConfigFloat(value); err != nil {
+			return fmt.Errorf("key %s expects float value : %s", key, value)
+		}
+	case ConfigTypeDuration:
+		if _, err := ParseConfigDuration(value); err != nil {
+			return fmt.Errorf("key %s expects duration value : %s", key, value)
+		}
+	case ConfigTypeBytes:
+		if _, err := ParseConfigBytes(value); err != nil {
+			return fmt.Errorf("key %s expects byte size value : %s", key, value)
+		}
+	case ConfigTypeCronSpec:
+		if _, err := cronSpecParser.Parse(value); err != nil {
+			return fmt.Errorf("key %s has invalid cron spec [%s] : %s", key, value, err.Error())
+		}
+	}
+
+	if len(s.Allowed) > 0 && !containsFold(s.Allowed, value) {
+		return fmt.Errorf("key %s value [%s] is not one of %v", key, value, s.Allowed)
+	}
+
+	if s.Validate != nil {
+		if err := s.Validate(value); err != nil {
+			return fmt.Errorf("key %s : %w", key, err)
+		}
+	}
+	return nil
+}
+
+// cronSpecParser same spec format with lib cron (robfig cron WithSeconds)
+var cronSpecParser = robfig_cron.NewParser(
+	robfig_cron.Second | robfig_cron.Minute | robfig_cron.Hour |
+		robfig_cron.Dom | robfig_cron.Month | robfig_cron.Dow | robfig_cron.Descriptor)
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	configSchemaLock sync.RWMutex
+	configSchema     = make([]ConfigKeySpec, 0)
+
+	// reservedConfigPrefixes unknown keys under these prefixes are reported as warning
+	reservedConfigPrefixes = []string{"gofatima.", "log4fatima.", "cron."}
+)
+
+// RegisterConfigSchema register application config key specs.
+// application should register its schema before fatima runtime is built (e.g. in init())
+// because config is validated right after loading application config
+func RegisterConfigSchema(specs ...ConfigKeySpec) {
+	configSchemaLock.Lock()
+	defer configSchemaLock.Unlock()
+	configSchema = append(configSchema, specs...)
+}
+
+// RegisterReservedConfigPrefix register key prefix which every key under it should be declared in schema
+func RegisterReservedConfigPrefix(prefix string) {
+	configSchemaLock.Lock()
+	defer configSchemaLock.Unlock()
+	for _, v := range reservedConfigPrefixes {
+		if v == prefix {
+			return
+		}
+	}
+	reservedConfigPrefixes = append(reservedConfigPrefixes, prefix)
+}
+
+// GetConfigSchema return copy of registered config key specs
+func GetConfigSchema() []ConfigKeySpec {
+	configSchemaLock.RLock()
+	defer configSchemaLock.RUnlock()
+	specs := make([]ConfigKeySpec, len(configSchema))
+	copy(specs, configSchema)
+	return specs
+}
+
+// ConfigValidationResult result of validating config values against schema
+type ConfigValidationResult struct {
+	Errors   []error
+	Warnings []string
+}
+
+func (r ConfigValidationResult) HasError() bool {
+	return len(r.Errors) > 0
+}
+
+// Err return joined errors or nil
+func (r ConfigValidationResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid application config : %w", errors.Join(r.Errors...))
+}
+
+// ValidateConfigValues validate values against registered config schema
+func ValidateConfigValues(values map[string]string) ConfigValidationResult {
+	configSchemaLock.RLock()
+	defer configSchemaLock.RUnlock()
+	return validateConfigValues(values, configSchema, reservedConfigPrefixes)
+}
+
+func validateConfigValues(values map[string]string, specs []ConfigKeySpec, reserved []string) ConfigValidationResult {
+	result := ConfigValidationResult{}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		spec, found := findConfigKeySpec(specs, key)
+		if !found {
+			if hasReservedPrefix(reserved, key) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("unknown config key '%s'", key))
+			}
+			continue
+		}
+		if err := spec.validate(key, values[key]); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	for _, spec := range specs {
+		if !spec.Required || spec.isPattern() {
+			continue
+		}
+		if _, ok := values[spec.Key]; !ok {
+			result.Errors = append(result.Errors, fmt.Errorf("required key %s not found", spec.Key))
+		}
+	}
+
+	return result
+}
+
+// findConfigKeySpec exact key spec wins over pattern spec
+func findConfigKeySpec(specs []ConfigKeySpec, key string) (ConfigKeySpec, bool) {
+	var patternSpec ConfigKeySpec
+	patternFound := false
+	for _, spec := range specs {
+		if !spec.matches(key) {
+			continue
+		}
+		if !spec.isPattern() {
+			return spec, true
+		}
+		if !patternFound {
+			patternSpec = spec
+			patternFound = true
+		}
+	}
+	return patternSpec, patternFound
+}
+
+func hasReservedPrefix(reserved []string, key string) bool {
+	for _, prefix := range reserved {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateApplicationConfig validate loaded application config.
+// warnings are logged, errors are returned
+func validateApplicationConfig(values map[string]string) error {
+	result := ValidateConfigValues(values)
+	for _, w := range result.Warnings {
+		log.Warn("%s", w)
+	}
+	for _, e := range result.Errors {
+		log.Error("%s", e.Error())
+	}
+	return result.Err()
+}
+
+func init() {
+	// framework owned config keys
+	RegisterConfigSchema(
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_BACKUP_DAYS, Type: ConfigTypeInt, Min: Bound(0), Max: Bound(65535),
+			Description: "log file keeping days"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_SHOW_METHOD, Type: ConfigTypeBool,
+			Description: "show method name in log"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_SOURCE_PRINTSIZE, Type: ConfigTypeInt, Min: Bound(0), Max: Bound(255),
+			Description: "source print size in log"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_FILE_SIZE_LIMIT, Type: ConfigTypeInt, Min: Bound(0), Max: Bound(65535),
+			Description: "log file size limit (MB)"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_SENTRY_DSN, Type: ConfigTypeString,
+			Description: "sentry dsn"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_SENTRY_FLUSH_SECOND, Type: ConfigTypeInt, Min: Bound(0),
+			Description: "sentry flush seconds"},
+		ConfigKeySpec{Key: LOG4FATIMA_PROP_SENTRY_LOGLEVEL, Type: ConfigTypeString,
+			Allowed:     []string{"trace", "debug", "info", "warn", "error"},
+			Description: "sentry log level"},
+		ConfigKeySpec{Key: GofatimaPropPprofAddress, Type: ConfigTypeString,
+			Description: "pprof listen address. e.g :6060"},
+		ConfigKeySpec{Key: GofatimaPropMetricsAddress, Type: ConfigTypeString,
+			Description: "metrics (prometheus, openmetrics) listen address. e.g :9100"},
+		ConfigKeySpec{Key: GofatimaRedirectConsole, Type: ConfigTypeBool,
+			Description: "redirect stdout/stderr to proc output file"},
+		ConfigKeySpec{Key: GofatimaHATransitionTimeout, Type: ConfigTypeDuration,
+			Description: "timeout of HA transition (prepare and commit)"},
+		ConfigKeySpec{Key: GofatimaMeasureInterval, Type: ConfigTypeDuration,
+			Description: "interval of process measurement"},
+		ConfigKeySpec{Key: GofatimaActivityInterval, Type: ConfigTypeDuration,
+			Description: "interval of sending measurement activity"},
+		ConfigKeySpec{Key: "gofatima.measure.unit.*.interval", Type: ConfigTypeDuration,
+			Description: "interval of measure unit. unit is measured in background"},
+		ConfigKeySpec{Key: GofatimaMonitorRotateSize, Type: ConfigTypeBytes,
+			Description: "rotate monitor file when size exceeds"},
+		ConfigKeySpec{Key: GofatimaMonitorRotateInterval, Type: ConfigTypeDuration,
+			Description: "rotate monitor file periodically. 0 means size only"},
+		ConfigKeySpec{Key: GofatimaMonitorRotateCount, Type: ConfigTypeInt, Min: Bound(1), Max: Bound(100),
+			Description: "generations of rotated monitor file"},
+		ConfigKeySpec{Key: GofatimaMonitorCompress, Type: ConfigTypeBool,
+			Description: "gzip rotated monitor file"},
+		ConfigKeySpec{Key: GofatimaMonitorRetentionDays, Type: ConfigTypeInt, Min: Bound(1), Max: Bound(65535),
+			Description: "monitor history keeping days"},
+		ConfigKeySpec{Key: GofatimaMonitorFormat, Type: ConfigTypeString,
+			Allowed:     []string{"text", "json", "both"},
+			Description: "monitor file format. json is written as json lines in .jsonl file"},
+		ConfigKeySpec{Key: "cron.*.spec", Type: ConfigTypeCronSpec,
+			Description: "cron job schedule spec"},
+		ConfigKeySpec{Key: "cron.*.desc", Type: ConfigTypeString,
+			Description: "cron job description"},
+		ConfigKeySpec{Key: "cron.*.primary", Type: ConfigTypeBool,
+			Description: "run cron job only on PRIMARY system"},
+		ConfigKeySpec{Key: "cron.*.profile", Type: ConfigTypeString,
+			Description: "comma separated profiles to run cron job"},
+		ConfigKeySpec{Key: "cron.*.sample", Type: ConfigTypeString,
+			Description: "cron job rerun sample arguments"},
+		ConfigKeySpec{Key: "cron.*.rununique", Type: ConfigTypeBool,
+			Description: "prevent concurrent cron job running"},
+	)
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 8:40
+ */
+
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/crypt"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigValues(t *testing.T) {
+	appSpecs := []ConfigKeySpec{
+		{Key: "db.url", Type: ConfigTypeString, Required: true},
+		{Key: "db.pool.size", Type: ConfigTypeInt, Min: Bound(1), Max: Bound(64)},
+		{Key: "mode", Type: ConfigTypeString, Allowed: []string{"fast", "safe"}},
+		{Key: "name", Type: ConfigTypeString, Validate: func(v string) error {
+			if len(v) > 4 {
+				return errors.New("too long")
+			}
+			return nil
+		}},
+	}
+	specs := append(GetConfigSchema(), appSpecs...)
+	reserved := []string{"gofatima.", "log4fatima.", "cron."}
+
+	tests := []struct {
+		name         string
+		values       map[string]string
+		wantErrors   int
+		wantWarnings int
+	}{
+		{
+			name:   "valid",
+			values: map[string]string{"db.url": "x", "db.pool.size": "8", "mode": "FAST", "cron.my.batch.spec": "0 0 * * * *"},
+		},
+		{
+			name:       "required_missing",
+			values:     map[string]string{"db.pool.size": "8"},
+			wantErrors: 1,
+		},
+		{
+			name:       "int_out_of_range",
+			values:     map[string]string{"db.url": "x", "db.pool.size": "100"},
+			wantErrors: 1,
+		},
+		{
+			name:       "int_not_numeric",
+			values:     map[string]string{"db.url": "x", "db.pool.size": "eight"},
+			wantErrors: 1,
+		},
+		{
+			name:       "not_allowed_value",
+			values:     map[string]string{"db.url": "x", "mode": "slow"},
+			wantErrors: 1,
+		},
+		{
+			name:       "custom_validate",
+			values:     map[string]string{"db.url": "x", "name": "toolong"},
+			wantErrors: 1,
+		},
+		{
+			name:       "invalid_cron_spec",
+			values:     map[string]string{"db.url": "x", "cron.myjob.spec": "every minute"},
+			wantErrors: 1,
+		},
+		{
+			name:       "invalid_framework_bool",
+			values:     map[string]string{"db.url": "x", LOG4FATIMA_PROP_SHOW_METHOD: "maybe"},
+			wantErrors: 1,
+		},
+		{
+			name:         "unknown_reserved_key_warns",
+			values:       map[string]string{"db.url": "x", "cron.myjob.spce": "x", "log4fatima.filesize.limt": "10"},
+			wantWarnings: 2,
+		},
+		{
+			name:   "unknown_app_key_ignored",
+			values: map[string]string{"db.url": "x", "some.other.key": "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateConfigValues(tt.values, specs, reserved)
+			assert.Equal(t, tt.wantErrors, len(result.Errors), "errors : %v", result.Errors)
+			assert.Equal(t, tt.wantWarnings, len(result.Warnings), "warnings : %v", result.Warnings)
+			assert.Equal(t, tt.wantErrors > 0, result.Err() != nil)
+		})
+	}
+}
+
+func TestFindConfigKeySpecExactWins(t *testing.T) {
+	specs := []ConfigKeySpec{
+		{Key: "cron.*.spec", Type: ConfigTypeCronSpec},
+		{Key: "cron.special.spec", Type: ConfigTypeString},
+	}
+	spec, ok := findConfigKeySpec(specs, "cron.special.spec")
+	assert.True(t, ok)
+	assert.Equal(t, ConfigTypeString, spec.Type)
+}
+
+func TestValidateConfigDoesNotLeakSecret(t *testing.T) {
+	configSchemaLock.RLock()
+	originSchema := append([]ConfigKeySpec(nil), configSchema...)
+	configSchemaLock.RUnlock()
+	t.Cleanup(func() {
+		configSchemaLock.Lock()
+		configSchema = originSchema
+		configSchemaLock.Unlock()
+	})
+
+	log.Initialize(log.NewPreference(""))
+	crypt.RegisterSecretValue("hunter2-resolved")
+	RegisterConfigSchema(ConfigKeySpec{Key: "test.pin.secret", Type: ConfigTypeInt})
+	values := map[string]string{
+		"test.pin.secret": "hunter2-plaintext",
+		"test.other":      "hunter2-resolved",
+	}
+
+	output := captureStdout(t, func() {
+		err := validateApplicationConfig(values)
+		assert.ErrorContains(t, err, "test.pin.secret")
+		assert.NotContains(t, err.Error(), "hunter2")
+	})
+	assert.Contains(t, output, "test.pin.secret")
+	assert.NotContains(t, output, "hunter2")
+
+	reader := &PropertyConfigReader{configuration: values}
+	_, err := reader.GetInt("test.pin.secret")
+	assert.NotContains(t, err.Error(), "hunter2")
+	_, err = reader.GetInt("test.other")
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.Equal(t, crypt.SecretMask, reader.GetMaskedValues()["test.pin.secret"])
+
+	secret, err := crypt.GetSecret(reader, "test.pin.secret")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2-plaintext", secret.Reveal())
+	assert.NotContains(t, fmt.Sprintf("%v", secret), "hunter2")
+	_, err = reader.GetSecret("test.not.found")
+	assert.Error(t, err)
+}
+
+func TestRedactActivity(t *testing.T) {
+	crypt.RegisterSecretValue("4321")
+	crypt.RegisterSecretValue(`pa"ss<word>`)
+	activity := map[string]interface{}{
+		"count":   54321,
+		"pin":     "pin is 4321",
+		"escaped": `pa"ss<word>`,
+		"list":    []string{"4321", "other"},
+	}
+
+	b, err := json.Marshal(redactActivity(activity))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"count":54321,"pin":"pin is ******","escaped":"******","list":["******","other"]}`, string(b))
+}
+
+// captureStdout capture stdout (fatima-log console output) while f is running
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origin := os.Stdout
+	os.Stdout = w
+	// restored by cleanup even when f fails the test
+	t.Cleanup(func() { os.Stdout = origin })
+
+	f()
+	os.Stdout = origin
+	require.NoError(t, w.Close())
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오전 10:15
+ */
+
+package builder
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseConfigBool parse bool config value (case-insensitive)
+// true : true, yes, y, on, 1
+// false : false, no, n, off, 0
+func ParseConfigBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "no", "n", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid bool value : %s", value)
+}
+
+// ParseConfigDuration parse duration config value. e.g) 500ms, 30s, 1h30m, 7d
+// 'd' (day, 24h) unit is supported in addition to time.ParseDuration units
+func ParseConfigDuration(value string) (time.Duration, error) {
+	v := strings.TrimSpace(value)
+	if strings.HasSuffix(v, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(v, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration value : %s", value)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value : %s", value)
+	}
+	return d, nil
+}
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	// longer suffix first
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"TB", 1 << 40},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40},
+	{"B", 1},
+}
+
+// ParseConfigBytes parse byte size config value. e.g) 512, 64KB, 30MB, 1.5GB
+// units are binary (KB=1024) and case-insensitive. value without unit is bytes
+func ParseConfigBytes(value string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(value))
+	unit := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(v, u.suffix) {
+			unit = u.size
+			v = strings.TrimSpace(strings.TrimSuffix(v, u.suffix))
+			break
+		}
+	}
+
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if i < 0 || i > math.MaxInt64/unit {
+			return 0, fmt.Errorf("byte size out of range : %s", value)
+		}
+		return i * unit, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid byte size value : %s", value)
+	}
+	if f*float64(unit) >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size out of range : %s", value)
+	}
+	return int64(f * float64(unit)), nil
+}
+
+// ParseConfigInt parse int config value
+func ParseConfigInt(value string) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid int value : %s", value)
+	}
+	return i, nil
+}
+
+// ParseConfigInt64 parse int64 config value
+func ParseConfigInt64(value string) (int64, error) {
+	i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid int64 value : %s", value)
+	}
+	return i, nil
+}
+
+// ParseConfigFloat parse float config value
+func ParseConfigFloat(value string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid float value : %s", value)
+	}
+	return f, nil
+}
+
+// FilterStringMap return key/values which start with prefix. prefix (and following '.') is removed from key
+// e.g) prefix 'db' : db.host=a, db.port=1 => host=a, port=1
+func FilterStringMap(values map[string]string, prefix string) map[string]string {
+	p := strings.TrimSuffix(prefix, ".") + "."
+	m := make(map[string]string)
+	for k, v := range values {
+		if strings.HasPrefix(k, p) && len(k) > len(p) {
+			m[k[len(p):]] = v
+		}
+	}
+	return m
+}
+
+// ConfigValueOrDefault return defaultValue if getter fails (key is not found or value is invalid)
+func ConfigValueOrDefault[T any](getter func(string) (T, error), key string, defaultValue T) T {
+	v, err := getter(key)
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오전 11:30
+ */
+
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigBool(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{value: "true", want: true},
+		{value: "TRUE", want: true},
+		{value: " yes ", want: true},
+		{value: "on", want: true},
+		{value: "1", want: true},
+		{value: "false"},
+		{value: "No"},
+		{value: "off"},
+		{value: "0"},
+		{value: "", wantErr: true},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseConfigBool(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseConfigBytes(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "512", want: 512},
+		{value: "512B", want: 512},
+		{value: "64KB", want: 64 << 10},
+		{value: "30MB", want: 30 << 20},
+		{value: "30 mb", want: 30 << 20},
+		{value: "2MiB", want: 2 << 20},
+		{value: "1.5GB", want: 3 << 29},
+		{value: "1T", want: 1 << 40},
+		{value: "-1MB", wantErr: true},
+		{value: "MB", wantErr: true},
+		{value: "ten", wantErr: true},
+		{value: "9999999TB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseConfigBytes(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil, "err : %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseConfigDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "500ms", want: 500 * time.Millisecond},
+		{value: "30s", want: 30 * time.Second},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "0.5d", want: 12 * time.Hour},
+		{value: "30", wantErr: true},
+		{value: "d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseConfigDuration(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterStringMap(t *testing.T) {
+	values := map[string]string{"db.host": "a", "db.port": "1", "dbx.host": "b", "db": "c"}
+	assert.Equal(t, map[string]string{"host": "a", "port": "1"}, FilterStringMap(values, "db"))
+	assert.Equal(t, map[string]string{"host": "a", "port": "1"}, FilterStringMap(values, "db."))
+}
+
+func TestPropertyConfigReaderTrimSpace(t *testing.T) {
+	reader := &PropertyConfigReader{configuration: map[string]string{"key": " 10 ", "flag": " yes", "timeout": "30s "}}
+	i, err := reader.GetInt("key")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, i)
+	i, err = ParseConfigInt("\t7\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, i)
+	i64, err := reader.GetInt64("key")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), i64)
+	assert.True(t, reader.GetBoolOrDefault("flag", false))
+	assert.Equal(t, 30*time.Second, reader.GetDurationOrDefault("timeout", 0))
+}
@@ -28,13 +28,47 @@ const (
 	BuiltinVariableYyyymmdd       = "${var.builtin.date.yyyymmdd}"
 	BuiltinVariableAppName        = "${var.builtin.app.name}"
 	BuiltinVariableAppFolderData  = "${var.builtin.app.folder.data}"
+	BuiltinVariableHostname       = "${var.builtin.hostname}" // var.global.package.hostname or os hostname
+	BuiltinVariablePid            = "${var.builtin.pid}"
+	BuiltinVariablePackageName    = "${var.builtin.package.name}"  // var.global.package.name. default=default
+	BuiltinVariablePackageGroup   = "${var.builtin.package.group}" // var.global.package.groupname. default=basic
+	BuiltinVariableProfile        = "${var.builtin.profile}"
+	BuiltinVariableInstanceId     = "${var.builtin.instance.id}" // hostname-appname-pid
 
 	GlobalDefinePackageHostname  = "var.global.package.hostname"
 	GlobalDefinePackageGroupname = "var.global.package.groupname"
 	GlobalDefinePackageName      = "var.global.package.name"
+	GlobalDefinePredefineStrict  = "var.global.predefine.strict" // fail on unresolved placeholder. default=false
+	// GlobalDefineLogFolder fatima-log folder. builtins are resolved once at process start
+	// e.g) ${var.builtin.fatima.home}/log/${var.builtin.app.name}. default=app log folder
+	GlobalDefineLogFolder = "var.global.log.folder"
 )
 
 const (
-	GofatimaPropPprofAddress = "gofatima.pprof.address"    // e.g :6060, localhost:6060
-	GofatimaRedirectConsole  = "gofatima.redirect.console" // e.g true, false. default=true
+	defaultPackageName  = "default"
+	defaultPackageGroup = "basic"
+)
+
+const (
+	GofatimaPropPprofAddress    = "gofatima.pprof.address"         // e.g :6060, localhost:6060
+	GofatimaPropMetricsAddress  = "gofatima.metrics.address"       // e.g :9100. metrics are served at /metrics
+	GofatimaRedirectConsole     = "gofatima.redirect.console"      // e.g true, false. default=true
+	GofatimaHATransitionTimeout = "gofatima.ha.transition.timeout" // e.g 30s. default=30s
+)
+
+const (
+	GofatimaMeasureInterval  = "gofatima.measure.interval"  // e.g 5s. default=5s
+	GofatimaActivityInterval = "gofatima.activity.interval" // e.g 1m. default=1m
+	// GofatimaMeasureUnitInterval interval of each measure unit. unit key name in lower case, space replaced with '_'
+	// e.g) gofatima.measure.unit.restclient.interval=30s
+	GofatimaMeasureUnitInterval = "gofatima.measure.unit.%s.interval"
+)
+
+const (
+	GofatimaMonitorRotateSize     = "gofatima.monitor.rotate.size"     // e.g 30MB. default=30MB
+	GofatimaMonitorRotateInterval = "gofatima.monitor.rotate.interval" // e.g 1h, 1d. default=0 (size only)
+	GofatimaMonitorRotateCount    = "gofatima.monitor.rotate.count"    // generations of rotated file. default=1
+	GofatimaMonitorCompress       = "gofatima.monitor.compress"        // gzip rotated file. default=false
+	GofatimaMonitorRetentionDays  = "gofatima.monitor.retention.days"  // keeping days of history. default=1
+	GofatimaMonitorFormat         = "gofatima.monitor.format"          // text, json, both. default=text
 )
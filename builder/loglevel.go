@@ -0,0 +1,396 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 26. 오전 10:30
+ */
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+)
+
+const (
+	logLevelHistorySize = 100
+)
+
+var errInvalidLogLevel = errors.New("invalid log level")
+
+// logLevelSetting level with optional revert timer
+type logLevelSetting struct {
+	level  log.LogLevel
+	expire time.Time
+	timer  *time.Timer
+}
+
+func (s *logLevelSetting) stop() {
+	if s != nil && s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// LogLevelManager manage process log level and logger overrides.
+// base level comes from fatima-package.yaml and cfm loglevels. temporary level (ipc, api) wins over base level until revert.
+// overrides are applied (filtered) only by loggers from NewNamedLogger. fatima-log level stays at process level
+// so plain log calls are not affected and override more verbose than process level is bounded by process level
+type LogLevelManager struct {
+	mutex     sync.Mutex
+	base      log.LogLevel
+	temporary *logLevelSetting
+	overrides map[string]*logLevelSetting
+	history   []fatima.LogLevelChange
+	notify    monitor.SystemNotifyHandler
+	setLevel  func(level log.LogLevel)
+}
+
+func NewLogLevelManager(base log.LogLevel, notify monitor.SystemNotifyHandler) *LogLevelManager {
+	m := &LogLevelManager{
+		base:      base,
+		overrides: make(map[string]*logLevelSetting),
+		history:   make([]fatima.LogLevelChange, 0),
+		notify:    notify,
+		setLevel:  log.SetLevel,
+	}
+	return m
+}
+
+func isValidLogLevel(level log.LogLevel) bool {
+	switch level {
+	case log.LOG_ERROR, log.LOG_WARN, log.LOG_INFO, log.LOG_DEBUG, log.LOG_TRACE:
+		return true
+	}
+	return false
+}
+
+// GetBaseLevel return base level (config, cfm)
+func (m *LogLevelManager) GetBaseLevel() log.LogLevel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.base
+}
+
+// resetBaseLevel set base level at process initializing without history. return true if fatima-log level is changed
+func (m *LogLevelManager) resetBaseLevel(level log.LogLevel) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.base = level
+	before := log.GetLevel()
+	m.apply()
+	return before != log.GetLevel()
+}
+
+// SetBaseLevel change base level. process level is not changed while temporary level is set
+func (m *LogLevelManager) SetBaseLevel(level log.LogLevel, source string) {
+	m.mutex.Lock()
+	if m.base == level {
+		m.mutex.Unlock()
+		return
+	}
+	from := m.base
+	m.base = level
+	change := m.record(fatima.LogLevelChange{Base: true, From: from.String(), To: level.String(), Source: source})
+	m.apply()
+	m.mutex.Unlock()
+	m.publish(change)
+}
+
+// GetLevel return process log level
+func (m *LogLevelManager) GetLevel() log.LogLevel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.level()
+}
+
+func (m *LogLevelManager) level() log.LogLevel {
+	if m.temporary != nil {
+		return m.temporary.level
+	}
+	return m.base
+}
+
+// GetLoggerLevel return log level of logger. the longest matching override is used
+func (m *LogLevelManager) GetLoggerLevel(name string) log.LogLevel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	matched := ""
+	level := m.level()
+	for logger, s := range m.overrides {
+		if len(logger) > len(matched) && matchLoggerName(logger, name) {
+			matched, level = logger, s.level
+		}
+	}
+	return level
+}
+
+// IsEnabled return true if logger writes log of level
+func (m *LogLevelManager) IsEnabled(name string, level log.LogLevel) bool {
+	return m.GetLoggerLevel(name) >= level
+}
+
+// matchLoggerName true if name is logger or child of logger
+func matchLoggerName(logger, name string) bool {
+	if !strings.HasPrefix(name, logger) {
+		return false
+	}
+	if len(name) == len(logger) {
+		return true
+	}
+	c := name[len(logger)]
+	return c == '/' || c == '.'
+}
+
+func (m *LogLevelManager) ChangeLogLevel(level log.LogLevel, ttl time.Duration, source string) error {
+	if !isValidLogLevel(level) {
+		return errInvalidLogLevel
+	}
+	if ttl < 0 {
+		return fmt.Errorf("invalid ttl : %s", ttl)
+	}
+
+	m.mutex.Lock()
+	from := m.level()
+	m.temporary.stop()
+	setting := &logLevelSetting{level: level}
+	if ttl > 0 {
+		setting.expire = time.Now().Add(ttl)
+		setting.timer = time.AfterFunc(ttl, func() { m.expireLogLevel(setting) })
+	}
+	m.temporary = setting
+	change := m.record(fatima.LogLevelChange{From: from.String(), To: level.String(), Source: source, Expire: setting.expire})
+	m.apply()
+	m.mutex.Unlock()
+	m.publish(change)
+	return nil
+}
+
+func (m *LogLevelManager) RevertLogLevel(source string) {
+	m.mutex.Lock()
+	if m.temporary == nil {
+		m.mutex.Unlock()
+		return
+	}
+	from := m.level()
+	m.temporary.stop()
+	m.temporary = nil
+	change := m.record(fatima.LogLevelChange{From: from.String(), To: m.base.String(), Source: source})
+	m.apply()
+	m.mutex.Unlock()
+	m.publish(change)
+}
+
+func (m *LogLevelManager) expireLogLevel(setting *logLevelSetting) {
+	m.mutex.Lock()
+	if m.temporary != setting {
+		m.mutex.Unlock()
+		return // already changed
+	}
+	m.temporary = nil
+	change := m.record(fatima.LogLevelChange{From: setting.level.String(), To: m.base.String(), Source: fatima.LogLevelSourceRevert})
+	m.apply()
+	m.mutex.Unlock()
+	m.publish(change)
+}
+
+func (m *LogLevelManager) ChangeLoggerLevel(name string, level log.LogLevel, ttl time.Duration, source string) error {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return errors.New("empty logger name")
+	}
+	if !isValidLogLevel(level) {
+		return errInvalidLogLevel
+	}
+	if ttl < 0 {
+		return fmt.Errorf("invalid ttl : %s", ttl)
+	}
+
+	m.mutex.Lock()
+	from := m.level()
+	if old, ok := m.overrides[name]; ok {
+		from = old.level
+		old.stop()
+	}
+	setting := &logLevelSetting{level: level}
+	if ttl > 0 {
+		setting.expire = time.Now().Add(ttl)
+		setting.timer = time.AfterFunc(ttl, func() { m.expireLoggerLevel(name, setting) })
+	}
+	m.overrides[name] = setting
+	change := m.record(fatima.LogLevelChange{Logger: name, From: from.String(), To: level.String(), Source: source, Expire: setting.expire})
+	processLevel := m.level()
+	m.mutex.Unlock()
+	m.publish(change)
+	if level > processLevel {
+		log.Warn("logger %s level %s is bounded by process level %s", name, level, processLevel)
+	}
+	return nil
+}
+
+func (m *LogLevelManager) RevertLoggerLevel(name string, source string) {
+	m.mutex.Lock()
+	old, ok := m.overrides[name]
+	if !ok {
+		m.mutex.Unlock()
+		return
+	}
+	old.stop()
+	delete(m.overrides, name)
+	change := m.record(fatima.LogLevelChange{Logger: name, From: old.level.String(), To: m.level().String(), Source: source})
+	m.mutex.Unlock()
+	m.publish(change)
+}
+
+func (m *LogLevelManager) expireLoggerLevel(name string, setting *logLevelSetting) {
+	m.mutex.Lock()
+	if m.overrides[name] != setting {
+		m.mutex.Unlock()
+		return
+	}
+	delete(m.overrides, name)
+	change := m.record(fatima.LogLevelChange{Logger: name, From: setting.level.String(), To: m.level().String(), Source: fatima.LogLevelSourceRevert})
+	m.mutex.Unlock()
+	m.publish(change)
+}
+
+func (m *LogLevelManager) GetLogLevelStatus() fatima.LogLevelStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	status := fatima.LogLevelStatus{
+		Level:     m.level().String(),
+		BaseLevel: m.base.String(),
+		Overrides: make([]fatima.LoggerLevel, 0, len(m.overrides)),
+		History:   append([]fatima.LogLevelChange(nil), m.history...),
+	}
+	if m.temporary != nil {
+		status.Expire = m.temporary.expire
+	}
+	for name, s := range m.overrides {
+		status.Overrides = append(status.Overrides, fatima.LoggerLevel{Logger: name, Level: s.level.String(), Expire: s.expire})
+	}
+	sort.Slice(status.Overrides, func(i, j int) bool {
+		return status.Overrides[i].Logger < status.Overrides[j].Logger
+	})
+	return status
+}
+
+// apply set fatima-log level to process level. mutex should be held
+func (m *LogLevelManager) apply() {
+	level := m.level()
+	if log.GetLevel() != level {
+		m.setLevel(level)
+	}
+}
+
+// record append history. mutex should be held
+func (m *LogLevelManager) record(change fatima.LogLevelChange) fatima.LogLevelChange {
+	change.Time = time.Now()
+	m.history = append(m.history, change)
+	if len(m.history) > logLevelHistorySize {
+		m.history = m.history[len(m.history)-logLevelHistorySize:]
+	}
+	return change
+}
+
+// publish log and send event of change
+func (m *LogLevelManager) publish(change fatima.LogLevelChange) {
+	target := "process"
+	if change.Base {
+		target = "process base"
+	} else if len(change.Logger) > 0 {
+		target = "logger " + change.Logger
+	}
+	message := fmt.Sprintf("log level changed. %s %s -> %s by %s", target, change.From, change.To, change.Source)
+	if !change.Expire.IsZero() {
+		message += fmt.Sprintf(" until %s", change.Expire.Format("2006-01-02 15:04:05"))
+	}
+	log.Warn("%s", message)
+	if m.notify != nil {
+		m.notify.SendEvent("%s", message)
+	}
+}
+
+// custom loggers print source of caller of NamedLogger method (fatima-log skips one wrapper frame)
+var (
+	errorLogger = log.NewCustomLogger("error")
+	warnLogger  = log.NewCustomLogger("warn")
+	infoLogger  = log.NewCustomLogger("info")
+	debugLogger = log.NewCustomLogger("debug")
+	traceLogger = log.NewCustomLogger("trace")
+)
+
+// NamedLogger logger whose level can be overridden by logger name (or package path)
+type NamedLogger struct {
+	name    string
+	manager *LogLevelManager
+}
+
+// NewNamedLogger return logger of name. name is usually package path
+func NewNamedLogger(name string) NamedLogger {
+	return NamedLogger{name: name}
+}
+
+// IsEnabled return true if override (or process level) of logger allows level. fatima-log level still gates the output
+func (l NamedLogger) IsEnabled(level log.LogLevel) bool {
+	manager := l.manager
+	if manager == nil {
+		manager = fatimaProcess.logLevel
+	}
+	if manager == nil {
+		return log.GetLevel() >= level
+	}
+	return manager.IsEnabled(l.name, level)
+}
+
+func (l NamedLogger) Error(format string, a ...interface{}) {
+	if l.IsEnabled(log.LOG_ERROR) {
+		errorLogger.Printf(format, a...)
+	}
+}
+
+func (l NamedLogger) Warn(format string, a ...interface{}) {
+	if l.IsEnabled(log.LOG_WARN) {
+		warnLogger.Printf(format, a...)
+	}
+}
+
+func (l NamedLogger) Info(format string, a ...interface{}) {
+	if l.IsEnabled(log.LOG_INFO) {
+		infoLogger.Printf(format, a...)
+	}
+}
+
+func (l NamedLogger) Debug(format string, a ...interface{}) {
+	if l.IsEnabled(log.LOG_DEBUG) {
+		debugLogger.Printf(format, a...)
+	}
+}
+
+func (l NamedLogger) Trace(format string, a ...interface{}) {
+	if l.IsEnabled(log.LOG_TRACE) {
+		traceLogger.Printf(format, a...)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 26. 오후 1:00
+ */
+
+package builder
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNotifyHandler struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (h *testNotifyHandler) SendAlarm(level monitor.AlarmLevel, action monitor.ActionType, message string) {
+}
+
+func (h *testNotifyHandler) SendAlarmWithCategory(level monitor.AlarmLevel, action monitor.ActionType, message string, category string) {
+}
+
+func (h *testNotifyHandler) SendActivity(json interface{}) {
+}
+
+func (h *testNotifyHandler) SendEvent(message string, v ...interface{}) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.events = append(h.events, fmt.Sprintf(message, v...))
+}
+
+func (h *testNotifyHandler) getEvents() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+// typed log levels for assertion
+const (
+	levelError log.LogLevel = log.LOG_ERROR
+	levelWarn  log.LogLevel = log.LOG_WARN
+	levelInfo  log.LogLevel = log.LOG_INFO
+	levelDebug log.LogLevel = log.LOG_DEBUG
+	levelTrace log.LogLevel = log.LOG_TRACE
+)
+
+// newTestLogLevelManager manager which does not change fatima-log level
+func newTestLogLevelManager(base log.LogLevel) (*LogLevelManager, *testNotifyHandler, *log.LogLevel) {
+	notify := &testNotifyHandler{}
+	m := NewLogLevelManager(base, notify)
+	applied := new(log.LogLevel)
+	m.setLevel = func(level log.LogLevel) { *applied = level }
+	return m, notify, applied
+}
+
+func TestLogLevelTemporary(t *testing.T) {
+	m, notify, applied := newTestLogLevelManager(levelInfo)
+
+	require.NoError(t, m.ChangeLogLevel(levelDebug, 0, fatima.LogLevelSourceAPI))
+	assert.Equal(t, levelDebug, m.GetLevel())
+	assert.Equal(t, levelDebug, *applied)
+
+	// base change (cfm) does not override temporary level
+	m.SetBaseLevel(levelWarn, fatima.LogLevelSourceCfm)
+	assert.Equal(t, levelDebug, m.GetLevel())
+	assert.Equal(t, levelWarn, m.GetBaseLevel())
+
+	m.RevertLogLevel(fatima.LogLevelSourceAPI)
+	assert.Equal(t, levelWarn, m.GetLevel())
+	assert.Equal(t, levelWarn, *applied)
+	m.RevertLogLevel(fatima.LogLevelSourceAPI) // nothing to revert
+
+	assert.Equal(t, []string{
+		"log level changed. process INFO -> DEBUG by api",
+		"log level changed. process base INFO -> WARN by cfm",
+		"log level changed. process DEBUG -> WARN by api",
+	}, notify.getEvents())
+
+	assert.Equal(t, errInvalidLogLevel, m.ChangeLogLevel(log.LOG_NONE, 0, fatima.LogLevelSourceAPI))
+	assert.Error(t, m.ChangeLogLevel(levelInfo, -time.Second, fatima.LogLevelSourceAPI))
+}
+
+func TestLogLevelAutoRevert(t *testing.T) {
+	m, _, _ := newTestLogLevelManager(levelInfo)
+
+	require.NoError(t, m.ChangeLogLevel(levelTrace, 20*time.Millisecond, fatima.LogLevelSourceIPC))
+	status := m.GetLogLevelStatus()
+	assert.Equal(t, "TRACE", status.Level)
+	assert.Equal(t, "INFO", status.BaseLevel)
+	assert.False(t, status.Expire.IsZero())
+
+	require.NoError(t, m.ChangeLoggerLevel("github.com/x/y", levelDebug, 20*time.Millisecond, fatima.LogLevelSourceIPC))
+	require.Eventually(t, func() bool {
+		s := m.GetLogLevelStatus()
+		return s.Level == "INFO" && len(s.Overrides) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	history := m.GetLogLevelStatus().History
+	require.Len(t, history, 4)
+	assert.Equal(t, fatima.LogLevelSourceRevert, history[2].Source)
+	assert.Equal(t, fatima.LogLevelSourceRevert, history[3].Source)
+
+	// replaced setting is not reverted by old timer
+	require.NoError(t, m.ChangeLogLevel(levelDebug, 20*time.Millisecond, fatima.LogLevelSourceIPC))
+	require.NoError(t, m.ChangeLogLevel(levelTrace, 0, fatima.LogLevelSourceIPC))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, levelTrace, m.GetLevel())
+}
+
+func TestLogLevelLoggerOverride(t *testing.T) {
+	m, _, applied := newTestLogLevelManager(levelWarn)
+
+	require.NoError(t, m.ChangeLoggerLevel("github.com/fatima-go/app", levelDebug, 0, fatima.LogLevelSourceAPI))
+	require.NoError(t, m.ChangeLoggerLevel("github.com/fatima-go/app/db", levelError, 0, fatima.LogLevelSourceAPI))
+	assert.NotEqual(t, levelDebug, *applied, "override does not change fatima-log level of plain log calls")
+
+	cases := []struct {
+		name  string
+		level log.LogLevel
+	}{
+		{"github.com/fatima-go/app", levelDebug},
+		{"github.com/fatima-go/app/api", levelDebug},
+		{"github.com/fatima-go/app/db", levelError},
+		{"github.com/fatima-go/app/db.pool", levelError},
+		{"github.com/fatima-go/application", levelWarn},
+		{"main", levelWarn},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.level, m.GetLoggerLevel(c.name), c.name)
+	}
+	assert.True(t, m.IsEnabled("github.com/fatima-go/app/api", levelDebug))
+	assert.False(t, m.IsEnabled("github.com/fatima-go/app/db", levelWarn))
+
+	l := NamedLogger{name: "github.com/fatima-go/app/db", manager: m}
+	assert.True(t, l.IsEnabled(levelError))
+	assert.False(t, l.IsEnabled(levelInfo))
+
+	status := m.GetLogLevelStatus()
+	assert.Equal(t, []fatima.LoggerLevel{
+		{Logger: "github.com/fatima-go/app", Level: "DEBUG"},
+		{Logger: "github.com/fatima-go/app/db", Level: "ERROR"},
+	}, status.Overrides)
+
+	m.RevertLoggerLevel("github.com/fatima-go/app", fatima.LogLevelSourceAPI)
+	m.RevertLoggerLevel("github.com/fatima-go/app/db", fatima.LogLevelSourceAPI)
+	assert.Empty(t, m.GetLogLevelStatus().Overrides)
+	assert.Equal(t, levelWarn, m.GetLoggerLevel("github.com/fatima-go/app/db"))
+	assert.Error(t, m.ChangeLoggerLevel(" ", levelInfo, 0, fatima.LogLevelSourceAPI))
+}
+
+func TestLogLevelHistorySize(t *testing.T) {
+	m, _, _ := newTestLogLevelManager(levelInfo)
+	for i := 0; i < logLevelHistorySize+10; i++ {
+		level := levelDebug
+		if i%2 == 0 {
+			level = levelTrace
+		}
+		require.NoError(t, m.ChangeLogLevel(level, 0, fatima.LogLevelSourceAPI))
+	}
+	assert.Len(t, m.GetLogLevelStatus().History, logLevelHistorySize)
+}
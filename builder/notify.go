@@ -21,11 +21,13 @@
 package builder
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/crypt"
 	"github.com/fatima-go/fatima-core/lib"
 	"github.com/fatima-go/fatima-core/monitor"
 	"github.com/fatima-go/fatima-log"
@@ -34,6 +36,7 @@ import (
 const (
 	ApplicationCode = 0x1
 	LogicMeasure    = 10
+	LogicMetric     = 11 // metric families. saturn which does not know it ignores the message
 	LogicNotify     = 20
 	NotifyFrom      = "go-fatima"
 	NotifyInitiator = "go-fatima"
@@ -65,7 +68,7 @@ func buildAlarmMessage(fatimaRuntime fatima.FatimaRuntime, level monitor.AlarmLe
 	content["alarm_level"] = level.String()
 	content["from"] = NotifyFrom
 	content["initiator"] = NotifyInitiator
-	content["message"] = message
+	content["message"] = crypt.Redact(message)
 	if action.IsProcessStartup() {
 		content["deployment"] = GetProcessDeployment()
 	}
@@ -110,7 +113,7 @@ func buildEventMessage(fatimaRuntime fatima.FatimaRuntime, message string, v ...
 	content["timestamp"] = time.Now().Format("2006-01-02 15:04:05")
 	content["from"] = NotifyFrom
 	content["initiator"] = NotifyInitiator
-	content["message"] = message
+	content["message"] = crypt.Redact(message)
 
 	if len(v) > 0 {
 		args := make([]string, 0)
@@ -137,6 +140,9 @@ func buildEventMessage(fatimaRuntime fatima.FatimaRuntime, message string, v ...
 				args = append(args, ".")
 			}
 		}
+		for i := range args {
+			args[i] = crypt.Redact(args[i])
+		}
 		content["params"] = args
 	}
 
@@ -155,12 +161,20 @@ func buildEventMessage(fatimaRuntime fatima.FatimaRuntime, message string, v ...
 }
 
 func buildActivityMessage(fatimaRuntime fatima.FatimaRuntime, v interface{}) []byte {
+	return buildMeasureMessage(fatimaRuntime, LogicMeasure, v)
+}
+
+func buildMetricMessage(fatimaRuntime fatima.FatimaRuntime, families []monitor.MetricFamily) []byte {
+	return buildMeasureMessage(fatimaRuntime, LogicMetric, families)
+}
+
+func buildMeasureMessage(fatimaRuntime fatima.FatimaRuntime, logic int, v interface{}) []byte {
 	m := make(map[string]interface{})
 	header := make(map[string]interface{})
 	body := make(map[string]interface{})
 
 	header["application_code"] = ApplicationCode
-	header["logic"] = LogicMeasure
+	header["logic"] = logic
 
 	body["package_host"] = fatimaRuntime.GetPackaging().GetHost()
 	body["package_name"] = fatimaRuntime.GetPackaging().GetName()
@@ -168,7 +182,8 @@ func buildActivityMessage(fatimaRuntime fatima.FatimaRuntime, v interface{}) []b
 	body["package_profile"] = fatimaRuntime.GetEnv().GetProfile()
 	body["package_process"] = fatimaRuntime.GetEnv().GetSystemProc().GetProgramName()
 	body["event_time"] = lib.CurrentTimeMillis()
-	body["message"] = v
+	// activity value is application owned struct. secret plaintext should not be delivered
+	body["message"] = redactActivity(v)
 
 	m["header"] = header
 	m["body"] = body
@@ -181,3 +196,35 @@ func buildActivityMessage(fatimaRuntime fatima.FatimaRuntime, v interface{}) []b
 
 	return b
 }
+
+// redactActivity redact string values of activity before marshaling.
+// redacting marshaled json may break numbers and misses escaped secret (e.g. '"', '<')
+func redactActivity(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err = decoder.Decode(&decoded); err != nil {
+		return v
+	}
+	return redactJSONValue(decoded)
+}
+
+func redactJSONValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return crypt.Redact(value)
+	case map[string]interface{}:
+		for k, e := range value {
+			value[k] = redactJSONValue(e)
+		}
+	case []interface{}:
+		for i, e := range value {
+			value[i] = redactJSONValue(e)
+		}
+	}
+	return v
+}
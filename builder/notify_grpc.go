@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatima-go/fatima-core"
@@ -48,6 +49,7 @@ type GrpcSystemNotifyHandler struct {
 	saturnAddress string
 	conn          *grpc.ClientConn
 	queue         chan []byte
+	dropped       atomic.Uint64
 }
 
 // NewGrpcSystemNotifyHandler create system notify handler
@@ -171,6 +173,7 @@ func (s *GrpcSystemNotifyHandler) enqueueForSending(bytes []byte) {
 			messageDropFlag = true
 			log.Warn("notify handler drop message....")
 		}
+		s.dropped.Add(1)
 		return // DROP...
 	}
 
@@ -178,6 +181,16 @@ func (s *GrpcSystemNotifyHandler) enqueueForSending(bytes []byte) {
 	s.queue <- bytes
 }
 
+// GetQueueSize number of messages waiting to be sent
+func (s *GrpcSystemNotifyHandler) GetQueueSize() int {
+	return len(s.queue)
+}
+
+// GetDroppedCount number of messages dropped because queue is full
+func (s *GrpcSystemNotifyHandler) GetDroppedCount() uint64 {
+	return s.dropped.Load()
+}
+
 func (s *GrpcSystemNotifyHandler) SendAlarm(level monitor.AlarmLevel, action monitor.ActionType, message string) {
 	s.enqueueForSending(buildAlarmMessage(s.fatimaRuntime, level, action, message, ""))
 }
@@ -193,3 +206,7 @@ func (s *GrpcSystemNotifyHandler) SendEvent(message string, v ...interface{}) {
 func (s *GrpcSystemNotifyHandler) SendActivity(json interface{}) {
 	s.enqueueForSending(buildActivityMessage(s.fatimaRuntime, json))
 }
+
+func (s *GrpcSystemNotifyHandler) SendMetrics(families []monitor.MetricFamily) {
+	s.enqueueForSending(buildMetricMessage(s.fatimaRuntime, families))
+}
@@ -0,0 +1,320 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 23. 오후 2:00
+ */
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+)
+
+// process launching descriptors in fatima-package.yaml
+//
+//	- gid: 2
+//	  name: api
+//	  env: {API_MODE: batch}
+//	  args: [-port, "8080"]
+//	  workdir: app/api
+//	  limits: {rlimit: {nofile: 65536, core: unlimited}, gomaxprocs: 4, gomemlimit: 1GB}
+//	  restart: {policy: on-failure, max: 5, backoff: 1s, maxbackoff: 1m}
+//	  depends: [jupiter]
+//	  health: {type: http, target: "http://127.0.0.1:8080/health", interval: 10s, timeout: 2s, retries: 3}
+
+const (
+	envGoMaxProcs = "GOMAXPROCS"
+	envGoMemLimit = "GOMEMLIMIT"
+)
+
+var supportedRlimits = map[string]bool{
+	"as":      true,
+	"core":    true,
+	"cpu":     true,
+	"data":    true,
+	"fsize":   true,
+	"memlock": true,
+	"nofile":  true,
+	"nproc":   true,
+	"stack":   true,
+}
+
+// ProcessLimitItem resource limits. rlimit value is number (with optional byte unit) or 'unlimited'
+type ProcessLimitItem struct {
+	Rlimit     map[string]string `yaml:"rlimit,omitempty"`
+	GoMaxProcs int               `yaml:"gomaxprocs,omitempty"`
+	GoMemLimit string            `yaml:"gomemlimit,omitempty"`
+}
+
+func (l *ProcessLimitItem) build() (fatima.ProcessResourceLimit, error) {
+	limit := fatima.ProcessResourceLimit{}
+	if l == nil {
+		return limit, nil
+	}
+
+	errs := make([]error, 0)
+	if len(l.Rlimit) > 0 {
+		limit.Rlimits = make(map[string]uint64, len(l.Rlimit))
+	}
+	for name, value := range l.Rlimit {
+		name = strings.ToLower(name)
+		if !supportedRlimits[name] {
+			errs = append(errs, fmt.Errorf("unsupported rlimit %s", name))
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "unlimited", "infinity":
+			limit.Rlimits[name] = fatima.RlimitUnlimited
+			continue
+		}
+		v, err := ParseConfigBytes(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rlimit %s : %w", name, err))
+			continue
+		}
+		limit.Rlimits[name] = uint64(v)
+	}
+
+	if l.GoMaxProcs < 0 {
+		errs = append(errs, fmt.Errorf("invalid gomaxprocs %d", l.GoMaxProcs))
+	}
+	limit.GoMaxProcs = l.GoMaxProcs
+	if len(l.GoMemLimit) > 0 {
+		v, err := ParseConfigBytes(l.GoMemLimit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gomemlimit : %w", err))
+		}
+		limit.GoMemLimit = v
+	}
+	return limit, errors.Join(errs...)
+}
+
+// ProcessRestartItem restart policy. backoff values are duration (e.g. 500ms, 1s, 1m)
+type ProcessRestartItem struct {
+	Policy     string `yaml:"policy,omitempty"`
+	Max        int    `yaml:"max,omitempty"`
+	Backoff    string `yaml:"backoff,omitempty"`
+	MaxBackoff string `yaml:"maxbackoff,omitempty"`
+}
+
+func (r *ProcessRestartItem) build() (fatima.ProcessRestartPolicy, error) {
+	policy := fatima.ProcessRestartPolicy{}
+	if r == nil {
+		return policy, nil
+	}
+
+	errs := make([]error, 0)
+	policy.Policy = strings.ToLower(r.Policy)
+	switch policy.Policy {
+	case "", fatima.RestartAlways, fatima.RestartOnFailure, fatima.RestartNever:
+	default:
+		errs = append(errs, fmt.Errorf("invalid restart policy %s", r.Policy))
+	}
+	if r.Max < 0 {
+		errs = append(errs, fmt.Errorf("invalid restart max %d", r.Max))
+	}
+	policy.MaxRestarts = r.Max
+
+	var err error
+	if policy.Backoff, err = parseOptionalDuration(r.Backoff); err != nil {
+		errs = append(errs, fmt.Errorf("restart backoff : %w", err))
+	}
+	if policy.MaxBackoff, err = parseOptionalDuration(r.MaxBackoff); err != nil {
+		errs = append(errs, fmt.Errorf("restart maxbackoff : %w", err))
+	}
+	if policy.MaxBackoff > 0 && policy.Backoff > policy.MaxBackoff {
+		errs = append(errs, errors.New("restart backoff is greater than maxbackoff"))
+	}
+	return policy, errors.Join(errs...)
+}
+
+// ProcessHealthItem health check settings
+type ProcessHealthItem struct {
+	Type     string `yaml:"type"`
+	Target   string `yaml:"target,omitempty"`
+	Interval string `yaml:"interval,omitempty"`
+	Timeout  string `yaml:"timeout,omitempty"`
+	Retries  int    `yaml:"retries,omitempty"`
+}
+
+func (h *ProcessHealthItem) build() (fatima.ProcessHealthCheck, error) {
+	check := fatima.ProcessHealthCheck{}
+	if h == nil {
+		return check, nil
+	}
+
+	errs := make([]error, 0)
+	check.Type = strings.ToLower(h.Type)
+	check.Target = h.Target
+	switch check.Type {
+	case fatima.HealthCheckHeartbeat:
+	case fatima.HealthCheckHttp:
+		if !strings.HasPrefix(h.Target, "http://") && !strings.HasPrefix(h.Target, "https://") {
+			errs = append(errs, fmt.Errorf("health target should be http(s) url : %s", h.Target))
+		}
+	case fatima.HealthCheckTcp, fatima.HealthCheckExec:
+		if len(strings.TrimSpace(h.Target)) == 0 {
+			errs = append(errs, fmt.Errorf("health target is required for %s", check.Type))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid health type %s", h.Type))
+	}
+	if h.Retries < 0 {
+		errs = append(errs, fmt.Errorf("invalid health retries %d", h.Retries))
+	}
+	check.Retries = h.Retries
+
+	var err error
+	if check.Interval, err = parseOptionalDuration(h.Interval); err != nil {
+		errs = append(errs, fmt.Errorf("health interval : %w", err))
+	}
+	if check.Timeout, err = parseOptionalDuration(h.Timeout); err != nil {
+		errs = append(errs, fmt.Errorf("health timeout : %w", err))
+	}
+	return check, errors.Join(errs...)
+}
+
+func parseOptionalDuration(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	d, err := ParseConfigDuration(value)
+	if err == nil && d < 0 {
+		return 0, fmt.Errorf("negative duration : %s", value)
+	}
+	return d, err
+}
+
+// validateProcessDescriptor validate launching descriptors of process
+func validateProcessDescriptor(p ProcessItem) error {
+	errs := make([]error, 0)
+	for k := range p.Env {
+		if len(k) == 0 || strings.ContainsAny(k, "= \t") {
+			errs = append(errs, fmt.Errorf("invalid env name [%s]", k))
+		}
+	}
+	if _, err := p.Limits.build(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := p.Restart.build(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := p.Health.build(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validateProcessDependencies check unknown, self and circular dependencies
+func validateProcessDependencies(processes map[string]ProcessItem) []error {
+	errs := make([]error, 0)
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := processes[name]
+		for _, dep := range p.Depends {
+			if dep == name {
+				errs = append(errs, fmt.Errorf("%s : process [%s] depends on itself", p.source(), name))
+			} else if _, ok := processes[dep]; !ok {
+				errs = append(errs, fmt.Errorf("%s : process [%s] depends on unknown process [%s]", p.source(), name, dep))
+			}
+		}
+	}
+
+	// detect cycle with dfs. 1 : visiting, 2 : done
+	state := make(map[string]int)
+	var visit func(name string, path []string) []string
+	visit = func(name string, path []string) []string {
+		switch state[name] {
+		case 1:
+			return append(path, name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, dep := range processes[name].Depends {
+			if _, ok := processes[dep]; !ok || dep == name {
+				continue
+			}
+			if cycle := visit(dep, append(path, name)); cycle != nil {
+				return cycle
+			}
+		}
+		state[name] = 2
+		return nil
+	}
+	for _, name := range names {
+		if cycle := visit(name, nil); cycle != nil {
+			errs = append(errs, fmt.Errorf("circular process dependency : %s", strings.Join(cycle, " -> ")))
+			break
+		}
+	}
+	return errs
+}
+
+// BuildProcessEnviron return environment (KEY=VALUE) for launching process.
+// process env overrides base and GOMAXPROCS/GOMEMLIMIT are added from resource limit unless specified in env
+func BuildProcessEnviron(proc fatima.FatimaPkgProc, base []string) []string {
+	env := make(map[string]string)
+	order := make([]string, 0, len(base))
+	set := func(k, v string) {
+		if _, ok := env[k]; !ok {
+			order = append(order, k)
+		}
+		env[k] = v
+	}
+	for _, kv := range base {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			set(k, v)
+		}
+	}
+
+	procEnv := proc.GetEnv()
+	keys := make([]string, 0, len(procEnv))
+	for k := range procEnv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		set(k, procEnv[k])
+	}
+
+	limit := proc.GetResourceLimit()
+	if _, ok := procEnv[envGoMaxProcs]; !ok && limit.GoMaxProcs > 0 {
+		set(envGoMaxProcs, strconv.Itoa(limit.GoMaxProcs))
+	}
+	if _, ok := procEnv[envGoMemLimit]; !ok && limit.GoMemLimit > 0 {
+		set(envGoMemLimit, fmt.Sprintf("%dB", limit.GoMemLimit))
+	}
+
+	environ := make([]string, 0, len(order))
+	for _, k := range order {
+		environ = append(environ, k+"="+env[k])
+	}
+	return environ
+}
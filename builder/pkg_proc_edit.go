@@ -0,0 +1,576 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 24. 오전 11:00
+ */
+
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fatima-go/fatima-core/lib"
+	"github.com/fatima-go/fatima-log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fatimaPackageLockFile     = ".fatima-package.lock"
+	fatimaPackageLockTimeout  = 10 * time.Second
+	fatimaPackageBackupSuffix = ".bak"
+	fatimaPackageIndent       = 4
+)
+
+// header comment for newly created fatima-package.yaml
+const fatimaPackageHeader = "# this is fatima-package.yaml sample\n" +
+	"# group (group id, group name)\n" +
+	"# process list\n" +
+	"# column : gid, name, loglevel, path, startmode\n" +
+	"# - gid : group id\n" +
+	"# - startmode : default 0(always started by juno), 1(not started by juno), 2(by HA), 3(by PS)"
+
+// AddGroup add group to fatima-package.yaml
+func (y *YamlFatimaPackageConfig) AddGroup(group GroupItem) error {
+	return y.modify(func(def *fatimaPackageDefinition) error {
+		for _, g := range def.Groups {
+			if g.Id == group.Id || strings.EqualFold(g.Name, group.Name) {
+				return fmt.Errorf("group %d [%s] already exists", g.Id, g.Name)
+			}
+		}
+		group.dropIn = ""
+		def.Groups = append(def.Groups, group)
+		return nil
+	})
+}
+
+// RemoveGroup remove group from every file declaring it. group which has process cannot be removed
+func (y *YamlFatimaPackageConfig) RemoveGroup(name string) error {
+	return y.modify(func(def *fatimaPackageDefinition) error {
+		gid, found := -1, false
+		groups := make([]GroupItem, 0, len(def.Groups))
+		for _, g := range def.Groups {
+			if strings.EqualFold(g.Name, name) {
+				gid, found = g.Id, true
+				continue
+			}
+			groups = append(groups, g)
+		}
+		if !found {
+			return fmt.Errorf("group [%s] not found", name)
+		}
+		for _, p := range def.Processes {
+			if p.Gid == gid {
+				return fmt.Errorf("group [%s] has process [%s]", name, p.Name)
+			}
+		}
+		def.Groups = groups
+		return nil
+	})
+}
+
+// AddProcess add process to fatima-package.yaml
+func (y *YamlFatimaPackageConfig) AddProcess(proc ProcessItem) error {
+	return y.modify(func(def *fatimaPackageDefinition) error {
+		for _, p := range def.Processes {
+			if p.Name == proc.Name {
+				return fmt.Errorf("process [%s] already exists in %s", p.Name, p.source())
+			}
+		}
+		proc.dropIn = ""
+		def.Processes = append(def.Processes, proc)
+		return nil
+	})
+}
+
+// UpdateProcess replace process which has same name. file declaring the process is updated
+func (y *YamlFatimaPackageConfig) UpdateProcess(proc ProcessItem) error {
+	return y.modify(func(def *fatimaPackageDefinition) error {
+		for i, p := range def.Processes {
+			if p.Name == proc.Name {
+				proc.dropIn = p.dropIn
+				def.Processes[i] = proc
+				return nil
+			}
+		}
+		return fmt.Errorf("process [%s] not found", proc.Name)
+	})
+}
+
+// RemoveProcess remove process from file declaring it
+func (y *YamlFatimaPackageConfig) RemoveProcess(name string) error {
+	return y.modify(func(def *fatimaPackageDefinition) error {
+		for i, p := range def.Processes {
+			if p.Name == name {
+				def.Processes = append(def.Processes[:i], def.Processes[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("process [%s] not found", name)
+	})
+}
+
+// SaveChanges write current groups and processes to files declaring them.
+// comments and layout are preserved. changed files are backed up to '.bak' before writing
+func (y *YamlFatimaPackageConfig) SaveChanges() error {
+	lock, err := y.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	def := fatimaPackageDefinition{
+		Groups:    append([]GroupItem(nil), y.Groups...),
+		Processes: append([]ProcessItem(nil), y.Processes...),
+	}
+	return y.store(def)
+}
+
+// modify apply edit to latest configuration on disk while holding lock
+func (y *YamlFatimaPackageConfig) modify(edit func(def *fatimaPackageDefinition) error) error {
+	lock, err := y.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	def, err := readFatimaPackage(y.env.GetFolderGuide())
+	if err != nil {
+		return err
+	}
+	if err = edit(&def); err != nil {
+		return err
+	}
+	return y.store(def)
+}
+
+func (y *YamlFatimaPackageConfig) lock() (*lib.FileLock, error) {
+	dir := filepath.Dir(y.env.GetFolderGuide().GetPackageProcFile())
+	return lib.LockFile(filepath.Join(dir, fatimaPackageLockFile), fatimaPackageLockTimeout)
+}
+
+// store validate definition, write changed files and apply definition to y
+func (y *YamlFatimaPackageConfig) store(def fatimaPackageDefinition) error {
+	guide := y.env.GetFolderGuide()
+	warnings, err := validateFatimaPackage(def, guide.GetFatimaHome())
+	for _, w := range warnings {
+		log.Warn("%s", w)
+	}
+	if err != nil {
+		return err
+	}
+
+	mainFile := guide.GetPackageProcFile()
+	dropIns, err := findFatimaPackageDropIns(fatimaPackageDropInDir(mainFile))
+	if err != nil {
+		return err
+	}
+
+	files := append([]string{mainFile}, dropIns...)
+	known := make(map[string]bool)
+	contents := make(map[string][]byte)
+	for _, file := range files {
+		dropIn := ""
+		if file != mainFile {
+			dropIn = filepath.Join(FatimaFolderProcConfigDropIn, filepath.Base(file))
+		}
+		known[dropIn] = true
+
+		groups := make([]GroupItem, 0)
+		for _, g := range def.Groups {
+			if g.dropIn == dropIn {
+				groups = append(groups, g)
+			}
+		}
+		processes := make([]ProcessItem, 0)
+		for _, p := range def.Processes {
+			if p.dropIn == dropIn {
+				processes = append(processes, p)
+			}
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil && !(file == mainFile && errors.Is(err, os.ErrNotExist)) {
+			return err
+		}
+		edited, changed, err := syncFatimaPackageFile(data, groups, processes)
+		if err != nil {
+			return fmt.Errorf("%s : %w", sourceOfDropIn(dropIn), err)
+		}
+		if changed {
+			contents[file] = edited
+		}
+	}
+
+	for _, g := range def.Groups {
+		if !known[g.dropIn] {
+			return fmt.Errorf("%s does not exist any more", g.dropIn)
+		}
+	}
+	for _, p := range def.Processes {
+		if !known[p.dropIn] {
+			return fmt.Errorf("%s does not exist any more", p.dropIn)
+		}
+	}
+
+	for _, file := range files {
+		data, ok := contents[file]
+		if !ok {
+			continue
+		}
+		if err = lib.BackupFile(file, fatimaPackageBackupSuffix); err != nil {
+			return fmt.Errorf("fail to backup %s : %w", file, err)
+		}
+		if err = lib.WriteFileAtomic(file, data, 0644); err != nil {
+			return err
+		}
+		log.Info("fatima package configuration %s saved", filepath.Base(file))
+	}
+
+	y.Groups = def.Groups
+	y.Processes = def.Processes
+	return nil
+}
+
+// syncFatimaPackageFile make group and process sequences of yaml document same as given items.
+// existing nodes (matched by group id and process name) are updated in place to keep comments and styles
+func syncFatimaPackageFile(data []byte, groups []GroupItem, processes []ProcessItem) ([]byte, bool, error) {
+	docs := make([]*yaml.Node, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		doc := new(yaml.Node)
+		if err := dec.Decode(doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		if len(groups) == 0 && len(processes) == 0 {
+			return data, false, nil
+		}
+		doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+		if len(bytes.TrimSpace(data)) == 0 {
+			doc.HeadComment = fatimaPackageHeader
+		}
+		docs = append(docs, doc)
+	}
+
+	root := docs[0].Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, false, errors.New("root is not mapping")
+	}
+
+	groupItems := make([]sequenceItem, 0, len(groups))
+	for _, g := range groups {
+		n, err := encodeItemNode(g, "id", "name")
+		if err != nil {
+			return nil, false, err
+		}
+		g.dropIn = ""
+		groupItems = append(groupItems, sequenceItem{node: n, value: g})
+	}
+	processItems := make([]sequenceItem, 0, len(processes))
+	for _, p := range processes {
+		n, err := encodeItemNode(p, "gid", "name")
+		if err != nil {
+			return nil, false, err
+		}
+		p.dropIn = ""
+		processItems = append(processItems, sequenceItem{node: n, value: p})
+	}
+
+	groupChanged, err := syncSequenceNode(root, "group", groupItems, "id", true)
+	if err != nil {
+		return nil, false, err
+	}
+	processChanged, err := syncSequenceNode(root, "process", processItems, "name", false)
+	if err != nil {
+		return nil, false, err
+	}
+	if !groupChanged && !processChanged {
+		return data, false, nil
+	}
+
+	var buff bytes.Buffer
+	enc := yaml.NewEncoder(&buff)
+	enc.SetIndent(detectYamlIndent(data))
+	for _, doc := range docs {
+		if err = enc.Encode(doc); err != nil {
+			return nil, false, err
+		}
+	}
+	if err = enc.Close(); err != nil {
+		return nil, false, err
+	}
+	return buff.Bytes(), true, nil
+}
+
+// encodeItemNode encode item to mapping node. keys with zero value are dropped except required keys
+func encodeItemNode(item any, required ...string) (*yaml.Node, error) {
+	n := new(yaml.Node)
+	if err := n.Encode(item); err != nil {
+		return nil, err
+	}
+
+	content := make([]*yaml.Node, 0, len(n.Content))
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if isZeroNode(v) && !containsString(required, k.Value) {
+			continue
+		}
+		content = append(content, k, v)
+	}
+	n.Content = content
+	return n, nil
+}
+
+// sequenceItem encoded node and value (without dropIn) of group or process
+type sequenceItem struct {
+	node  *yaml.Node
+	value any
+}
+
+// syncSequenceNode sync sequence of root[key] with items. existing node is merged only when its item is changed
+func syncSequenceNode(root *yaml.Node, key string, items []sequenceItem, idKey string, flow bool) (bool, error) {
+	seq := mappingValue(root, key)
+	changed := false
+	if seq == nil {
+		if len(items) == 0 {
+			return false, nil
+		}
+		seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if flow {
+			seq.Style = yaml.FlowStyle
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, seq)
+		changed = true
+	}
+	if seq.Kind == yaml.ScalarNode && seq.ShortTag() == "!!null" {
+		// e.g) 'process:' without value
+		if len(items) == 0 {
+			return false, nil
+		}
+		seq.Kind, seq.Tag, seq.Value = yaml.SequenceNode, "!!seq", ""
+		changed = true
+	}
+	if seq.Kind != yaml.SequenceNode {
+		return false, fmt.Errorf("%s is not sequence", key)
+	}
+
+	// new item follows style of last item. e.g) - {id: 1, name: OPM}
+	var style yaml.Style
+	if len(seq.Content) > 0 {
+		style = seq.Content[len(seq.Content)-1].Style
+	}
+	existing := make(map[string]*yaml.Node)
+	for _, n := range seq.Content {
+		if id := mappingValue(n, idKey); id != nil {
+			if _, dup := existing[id.Value]; !dup {
+				existing[id.Value] = n
+			}
+		}
+	}
+
+	content := make([]*yaml.Node, 0, len(items))
+	for i, item := range items {
+		id := mappingValue(item.node, idKey).Value
+		node, ok := existing[id]
+		if ok {
+			delete(existing, id)
+			if !sameItemNode(node, item.value) && mergeMappingNode(node, item.node, yamlFieldNames(reflect.TypeOf(item.value))) {
+				changed = true
+			}
+		} else {
+			item.node.Style = style
+			node = item.node
+			changed = true
+		}
+		if i >= len(seq.Content) || seq.Content[i] != node {
+			changed = true
+		}
+		content = append(content, node)
+	}
+	if len(content) != len(seq.Content) {
+		changed = true
+	}
+	seq.Content = content
+	return changed, nil
+}
+
+// sameItemNode true when node is decoded to same value. node of unchanged item is not touched
+func sameItemNode(node *yaml.Node, value any) bool {
+	decoded := reflect.New(reflect.TypeOf(value))
+	if err := node.Decode(decoded.Interface()); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(decoded.Elem().Interface(), value)
+}
+
+// mergeMappingNode update dst with src. unchanged values (and their comments, styles) are kept.
+// keys which are not fields (e.g. used by other tools) are kept. field absent in src (omitempty) is kept
+// when dst has zero value, otherwise it is set to zero value
+func mergeMappingNode(dst, src *yaml.Node, fields map[string]bool) bool {
+	changed := false
+	seen := make(map[string]bool)
+	content := make([]*yaml.Node, 0, len(dst.Content))
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		k, v := dst.Content[i], dst.Content[i+1]
+		seen[k.Value] = true
+		sv := mappingValue(src, k.Value)
+		if sv == nil {
+			if fields[k.Value] && !isZeroNode(v) {
+				sv = zeroNodeOf(v)
+			} else {
+				sv = v
+			}
+		}
+		if !equalNode(v, sv) {
+			sv.HeadComment, sv.LineComment, sv.FootComment = v.HeadComment, v.LineComment, v.FootComment
+			if v.Kind == sv.Kind && (v.Kind != yaml.ScalarNode || v.ShortTag() == sv.ShortTag()) {
+				sv.Style = v.Style
+			}
+			v = sv
+			changed = true
+		}
+		content = append(content, k, v)
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if !seen[src.Content[i].Value] {
+			content = append(content, src.Content[i], src.Content[i+1])
+			changed = true
+		}
+	}
+	dst.Content = content
+	return changed
+}
+
+// zeroNodeOf zero value node of same kind. e.g) true -> false, [a] -> []
+func zeroNodeOf(n *yaml.Node) *yaml.Node {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	case yaml.MappingNode:
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: yaml.FlowStyle}
+	}
+	switch n.ShortTag() {
+	case "!!bool":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+	case "!!int", "!!float":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: n.ShortTag(), Value: "0"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "", Style: yaml.DoubleQuotedStyle}
+}
+
+// yamlFieldNames keys of struct fields. e.g) ProcessItem -> gid, name, loglevel, ...
+func yamlFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = strings.ToLower(field.Name)
+		}
+		names[name] = true
+	}
+	return names
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func equalNode(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		return a.ShortTag() == b.ShortTag() && a.Value == b.Value
+	}
+	if len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !equalNode(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeroNode(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!int", "!!float":
+			return n.Value == "0"
+		case "!!bool":
+			return n.Value == "false"
+		case "!!null":
+			return true
+		}
+		return len(n.Value) == 0
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
+
+// detectYamlIndent return indent of first indented line. default fatimaPackageIndent
+func detectYamlIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent >= 2 && indent <= 8 {
+			return indent
+		}
+	}
+	return fatimaPackageIndent
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 23. 오전 10:10
+ */
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FatimaFolderProcConfigDropIn folder (in conf) holding additional process definitions (*.yaml)
+	// which are merged into fatima-package.yaml in file name order
+	FatimaFolderProcConfigDropIn = "fatima-package.d"
+)
+
+var validLogLevelNames = map[string]bool{
+	"trace": true,
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+	"none":  true,
+}
+
+// fatimaPackageDefinition content of fatima-package.yaml (or fatima-package.d/*.yaml)
+type fatimaPackageDefinition struct {
+	Groups    []GroupItem   `yaml:"group,flow"`
+	Processes []ProcessItem `yaml:"process"`
+}
+
+// loadFatimaPackage read fatima-package.yaml and fatima-package.d/*.yaml, merge and validate them
+func loadFatimaPackage(guide fatima.FolderGuide) (fatimaPackageDefinition, error) {
+	merged, err := readFatimaPackage(guide)
+	if err != nil {
+		return merged, err
+	}
+
+	warnings, err := validateFatimaPackage(merged, guide.GetFatimaHome())
+	for _, w := range warnings {
+		log.Warn("%s", w)
+	}
+	return merged, err
+}
+
+// readFatimaPackage read and merge fatima-package.yaml and fatima-package.d/*.yaml without validation
+func readFatimaPackage(guide fatima.FolderGuide) (fatimaPackageDefinition, error) {
+	mainFile := guide.GetPackageProcFile()
+	merged, err := readFatimaPackageFile(mainFile, "")
+	if err != nil {
+		return merged, err
+	}
+
+	dropIns, err := findFatimaPackageDropIns(fatimaPackageDropInDir(mainFile))
+	if err != nil {
+		return merged, err
+	}
+	for _, file := range dropIns {
+		def, err := readFatimaPackageFile(file, filepath.Join(FatimaFolderProcConfigDropIn, filepath.Base(file)))
+		if err != nil {
+			return merged, err
+		}
+		merged.Groups = append(merged.Groups, def.Groups...)
+		merged.Processes = append(merged.Processes, def.Processes...)
+	}
+	return merged, nil
+}
+
+func fatimaPackageDropInDir(mainFile string) string {
+	return filepath.Join(filepath.Dir(mainFile), FatimaFolderProcConfigDropIn)
+}
+
+// readFatimaPackageFile read yaml file. dropIn is display name of drop-in file (empty for fatima-package.yaml)
+func readFatimaPackageFile(path string, dropIn string) (fatimaPackageDefinition, error) {
+	def := fatimaPackageDefinition{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return def, err
+	}
+	if err = yaml.Unmarshal(data, &def); err != nil {
+		return def, fmt.Errorf("fail to parse %s : %w", path, err)
+	}
+
+	for i := range def.Groups {
+		def.Groups[i].dropIn = dropIn
+	}
+	for i := range def.Processes {
+		def.Processes[i].dropIn = dropIn
+	}
+	return def, nil
+}
+
+// findFatimaPackageDropIns return sorted yaml files in dir. missing dir is not an error
+func findFatimaPackageDropIns(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// validateFatimaPackage validate merged definition. same group (id and name) may be declared in several files.
+// missing process path is reported as warning because the package may be deployed partially
+func validateFatimaPackage(def fatimaPackageDefinition, fatimaHome string) ([]string, error) {
+	warnings := make([]string, 0)
+	errs := make([]error, 0)
+
+	if len(def.Groups) == 0 {
+		errs = append(errs, errors.New("no group"))
+	}
+	if len(def.Processes) == 0 {
+		errs = append(errs, errors.New("no process"))
+	}
+
+	groupById := make(map[int]GroupItem)
+	groupByName := make(map[string]GroupItem)
+	for _, g := range def.Groups {
+		name := strings.ToLower(g.Name)
+		if len(name) == 0 {
+			errs = append(errs, fmt.Errorf("%s : group %d has no name", g.source(), g.Id))
+			continue
+		}
+		if prev, ok := groupById[g.Id]; ok {
+			if strings.ToLower(prev.Name) != name {
+				errs = append(errs, fmt.Errorf("%s : group id %d is declared as [%s] in %s", g.source(), g.Id, prev.Name, prev.source()))
+			}
+			continue
+		}
+		if prev, ok := groupByName[name]; ok {
+			errs = append(errs, fmt.Errorf("%s : group [%s] is declared with id %d in %s", g.source(), g.Name, prev.Id, prev.source()))
+			continue
+		}
+		groupById[g.Id] = g
+		groupByName[name] = g
+	}
+
+	processByName := make(map[string]ProcessItem)
+	for _, p := range def.Processes {
+		if len(p.Name) == 0 {
+			errs = append(errs, fmt.Errorf("%s : process has no name", p.source()))
+			continue
+		}
+		if prev, ok := processByName[p.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s : duplicated process [%s]. already defined in %s", p.source(), p.Name, prev.source()))
+			continue
+		}
+		processByName[p.Name] = p
+
+		if _, ok := groupById[p.Gid]; !ok {
+			errs = append(errs, fmt.Errorf("%s : process [%s] has unknown gid %d", p.source(), p.Name, p.Gid))
+		}
+		if p.Startmode < fatima.StartModeByJuno || p.Startmode > fatima.StartModeByPS {
+			errs = append(errs, fmt.Errorf("%s : process [%s] has invalid startmode %d", p.source(), p.Name, p.Startmode))
+		}
+		if len(p.Loglevel) > 0 && !validLogLevelNames[strings.ToLower(p.Loglevel)] {
+			errs = append(errs, fmt.Errorf("%s : process [%s] has invalid loglevel [%s]", p.source(), p.Name, p.Loglevel))
+		}
+		if p.Weight < 0 || p.StartSec < 0 {
+			errs = append(errs, fmt.Errorf("%s : process [%s] has negative weight or startsec", p.source(), p.Name))
+		}
+		if len(p.Path) > 0 {
+			path := p.Path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(fatimaHome, path)
+			}
+			if _, err := os.Stat(path); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s : process [%s] path %s does not exist", p.source(), p.Name, path))
+			}
+		}
+		if err := validateProcessDescriptor(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s : process [%s] : %w", p.source(), p.Name, err))
+		}
+	}
+	errs = append(errs, validateProcessDependencies(processByName)...)
+
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+	return warnings, fmt.Errorf("invalid fatima package configuration : %w", errors.Join(errs...))
+}
+
+func sourceOfDropIn(dropIn string) string {
+	if len(dropIn) == 0 {
+		return FatimaFileProcConfig
+	}
+	return dropIn
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 24. 오후 3:00
+ */
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
+)
+
+const (
+	ProcessPlanStart = "start"
+	ProcessPlanStop  = "stop"
+)
+
+// ProcessPlanOption option for building start/stop plan
+type ProcessPlanOption struct {
+	// Status current system status. HA/PS processes are skipped in start plan when nil
+	Status monitor.FatimaSystemStatus
+	// Group plan only processes of this group. empty means all groups
+	Group string
+	// ExceptOpm exclude OPM group
+	ExceptOpm bool
+}
+
+// ProcessPlanEntry process in plan
+type ProcessPlanEntry struct {
+	Name     string               `json:"name"`
+	Group    string               `json:"group"`
+	Weight   int                  `json:"weight"`
+	StartSec int                  `json:"startsec"`
+	Proc     fatima.FatimaPkgProc `json:"-"`
+}
+
+// ProcessWave processes which can be started (or stopped) at the same time
+type ProcessWave struct {
+	Processes []ProcessPlanEntry `json:"processes"`
+	// WaitSec seconds to wait after starting this wave before next wave (max startsec of wave). 0 for stop plan
+	WaitSec int `json:"waitsec"`
+}
+
+// SkippedProcess process excluded from plan with reason
+type SkippedProcess struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ProcessPlan ordered waves. waves should be executed in order
+type ProcessPlan struct {
+	Kind    string           `json:"kind"`
+	Waves   []ProcessWave    `json:"waves"`
+	Skipped []SkippedProcess `json:"skipped,omitempty"`
+}
+
+// String return printable plan for dry-run
+func (p ProcessPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s plan : %d wave(s)\n", p.Kind, len(p.Waves))
+	for i, wave := range p.Waves {
+		names := make([]string, 0, len(wave.Processes))
+		for _, e := range wave.Processes {
+			names = append(names, fmt.Sprintf("%s(%s)", e.Name, e.Group))
+		}
+		fmt.Fprintf(&b, "wave %d : %s", i+1, strings.Join(names, ", "))
+		if wave.WaitSec > 0 {
+			fmt.Fprintf(&b, " [wait %ds]", wave.WaitSec)
+		}
+		b.WriteString("\n")
+	}
+	for _, s := range p.Skipped {
+		fmt.Fprintf(&b, "skip %s : %s\n", s.Name, s.Reason)
+	}
+	return b.String()
+}
+
+// PlanStart build start plan. processes are ordered by group (OPM first, then group id),
+// weight (higher first) and dependencies. dependency on process which is not in plan is ignored
+func (y *YamlFatimaPackageConfig) PlanStart(option ProcessPlanOption) (ProcessPlan, error) {
+	return y.plan(ProcessPlanStart, option)
+}
+
+// PlanStop build stop plan which is reverse order of start plan.
+// HA/PS processes are included regardless of system status because they might be running
+func (y *YamlFatimaPackageConfig) PlanStop(option ProcessPlanOption) (ProcessPlan, error) {
+	plan, err := y.plan(ProcessPlanStop, option)
+	if err != nil {
+		return plan, err
+	}
+
+	for i, j := 0, len(plan.Waves)-1; i < j; i, j = i+1, j-1 {
+		plan.Waves[i], plan.Waves[j] = plan.Waves[j], plan.Waves[i]
+	}
+	for i := range plan.Waves {
+		plan.Waves[i].WaitSec = 0
+	}
+	return plan, nil
+}
+
+func (y *YamlFatimaPackageConfig) plan(kind string, option ProcessPlanOption) (ProcessPlan, error) {
+	plan := ProcessPlan{Kind: kind, Waves: make([]ProcessWave, 0)}
+
+	groupName := make(map[int]string)
+	groupRank := make(map[int]int)
+	groups := append([]GroupItem(nil), y.Groups...)
+	sort.Stable(GroupItems(groups))
+	groupRank[1] = 0
+	for i, g := range groups {
+		if _, ok := groupName[g.Id]; !ok {
+			groupName[g.Id] = g.Name
+		}
+		if _, ok := groupRank[g.Id]; !ok {
+			groupRank[g.Id] = i + 1
+		}
+	}
+
+	planGid := -1
+	if len(option.Group) > 0 {
+		if planGid = y.GetGroupId(option.Group); planGid < 0 {
+			return plan, fmt.Errorf("group [%s] not found", option.Group)
+		}
+	}
+	opmGid := y.GetGroupId("OPM")
+
+	candidates := make([]ProcessItem, 0)
+	for _, p := range y.Processes {
+		if planGid >= 0 && p.Gid != planGid {
+			continue
+		}
+		if option.ExceptOpm && p.Gid == opmGid {
+			continue
+		}
+		if reason := skipReason(kind, p, option.Status); len(reason) > 0 {
+			plan.Skipped = append(plan.Skipped, SkippedProcess{Name: p.Name, Reason: reason})
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+
+	// tier : (group rank, weight desc). earlier tier goes first unless dependencies require otherwise
+	tierLess := func(a, b ProcessItem) bool {
+		if groupRank[a.Gid] != groupRank[b.Gid] {
+			return groupRank[a.Gid] < groupRank[b.Gid]
+		}
+		return a.Weight > b.Weight
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return tierLess(candidates[i], candidates[j])
+	})
+
+	inPlan := make(map[string]bool)
+	for _, p := range candidates {
+		inPlan[p.Name] = true
+	}
+	placed := make(map[string]bool)
+	remaining := candidates
+	for len(remaining) > 0 {
+		ready := make([]ProcessItem, 0)
+		for _, p := range remaining {
+			if dependenciesPlaced(p, inPlan, placed) {
+				ready = append(ready, p)
+			}
+		}
+		if len(ready) == 0 {
+			return plan, errors.New("circular process dependency")
+		}
+
+		// next wave : ready processes of first tier
+		first := ready[0]
+		wave := ProcessWave{Processes: make([]ProcessPlanEntry, 0)}
+		next := make([]ProcessItem, 0, len(remaining))
+		for _, p := range remaining {
+			if !tierLess(first, p) && !tierLess(p, first) && dependenciesPlaced(p, inPlan, placed) {
+				wave.Processes = append(wave.Processes, ProcessPlanEntry{
+					Name:     p.Name,
+					Group:    groupName[p.Gid],
+					Weight:   p.Weight,
+					StartSec: p.StartSec,
+					Proc:     p,
+				})
+				if p.StartSec > wave.WaitSec {
+					wave.WaitSec = p.StartSec
+				}
+				continue
+			}
+			next = append(next, p)
+		}
+		for _, e := range wave.Processes {
+			placed[e.Name] = true
+		}
+		plan.Waves = append(plan.Waves, wave)
+		remaining = next
+	}
+	return plan, nil
+}
+
+func dependenciesPlaced(p ProcessItem, inPlan, placed map[string]bool) bool {
+	for _, dep := range p.Depends {
+		if inPlan[dep] && !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// skipReason return reason why process is excluded from plan. empty means included
+func skipReason(kind string, p ProcessItem, status monitor.FatimaSystemStatus) string {
+	switch p.GetStartMode() {
+	case fatima.StartModeAlone:
+		return "startmode alone. not managed by juno"
+	case fatima.StartModeByHA:
+		if kind == ProcessPlanStop {
+			return ""
+		}
+		if status == nil {
+			return "startmode HA. system status unknown"
+		}
+		if !status.IsActive() {
+			return "startmode HA. system is " + status.GetHAStatus().String()
+		}
+	case fatima.StartModeByPS:
+		if kind == ProcessPlanStop {
+			return ""
+		}
+		if status == nil {
+			return "startmode PS. system status unknown"
+		}
+		if !status.IsPrimary() {
+			return "startmode PS. system is " + status.GetPSStatus().String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 24. 오후 4:10
+ */
+
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSystemStatus struct {
+	ha monitor.HAStatus
+	ps monitor.PSStatus
+}
+
+func (s testSystemStatus) GetPSStatus() monitor.PSStatus { return s.ps }
+func (s testSystemStatus) GetHAStatus() monitor.HAStatus { return s.ha }
+func (s testSystemStatus) IsActive() bool                { return s.ha == monitor.HA_STATUS_ACTIVE }
+func (s testSystemStatus) IsPrimary() bool               { return s.ps == monitor.PS_STATUS_PRIMARY }
+
+func newTestPlanConfig() *YamlFatimaPackageConfig {
+	return &YamlFatimaPackageConfig{
+		Groups: []GroupItem{{Id: 3, Name: "BATCH"}, {Id: 1, Name: "OPM"}, {Id: 2, Name: "SVC"}},
+		Processes: []ProcessItem{
+			{Gid: 3, Name: "report"},
+			{Gid: 2, Name: "api", Depends: []string{"cache"}, StartSec: 3},
+			{Gid: 2, Name: "cache", StartSec: 5},
+			{Gid: 2, Name: "db", Weight: 10, StartSec: 10},
+			{Gid: 2, Name: "web"},
+			{Gid: 2, Name: "ha-worker", Startmode: 2},
+			{Gid: 2, Name: "ps-worker", Startmode: 3},
+			{Gid: 3, Name: "tool", Startmode: 1},
+			{Gid: 1, Name: "jupiter", StartSec: 2},
+		},
+	}
+}
+
+func planNames(plan ProcessPlan) [][]string {
+	waves := make([][]string, 0)
+	for _, w := range plan.Waves {
+		names := make([]string, 0)
+		for _, e := range w.Processes {
+			names = append(names, e.Name)
+		}
+		waves = append(waves, names)
+	}
+	return waves
+}
+
+func TestPlanStart(t *testing.T) {
+	config := newTestPlanConfig()
+
+	plan, err := config.PlanStart(ProcessPlanOption{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"jupiter"}, {"db"}, {"cache", "web"}, {"api"}, {"report"}}, planNames(plan))
+	assert.Equal(t, []int{2, 10, 5, 3, 0}, []int{plan.Waves[0].WaitSec, plan.Waves[1].WaitSec,
+		plan.Waves[2].WaitSec, plan.Waves[3].WaitSec, plan.Waves[4].WaitSec})
+	assert.Equal(t, []SkippedProcess{
+		{Name: "ha-worker", Reason: "startmode HA. system status unknown"},
+		{Name: "ps-worker", Reason: "startmode PS. system status unknown"},
+		{Name: "tool", Reason: "startmode alone. not managed by juno"},
+	}, plan.Skipped)
+
+	plan, err = config.PlanStart(ProcessPlanOption{
+		Status:    testSystemStatus{ha: monitor.HA_STATUS_ACTIVE, ps: monitor.PS_STATUS_SECONDARY},
+		Group:     "svc",
+		ExceptOpm: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"db"}, {"cache", "web", "ha-worker"}, {"api"}}, planNames(plan))
+	assert.Equal(t, []SkippedProcess{{Name: "ps-worker", Reason: "startmode PS. system is Secondary"}}, plan.Skipped)
+
+	assert.Equal(t, "start plan : 3 wave(s)\n"+
+		"wave 1 : db(SVC) [wait 10s]\n"+
+		"wave 2 : cache(SVC), web(SVC), ha-worker(SVC) [wait 5s]\n"+
+		"wave 3 : api(SVC) [wait 3s]\n"+
+		"skip ps-worker : startmode PS. system is Secondary\n", plan.String())
+
+	_, err = config.PlanStart(ProcessPlanOption{Group: "nothing"})
+	assert.Error(t, err)
+}
+
+func TestPlanStop(t *testing.T) {
+	config := newTestPlanConfig()
+
+	plan, err := config.PlanStop(ProcessPlanOption{ExceptOpm: true})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"report"}, {"api"}, {"cache", "web", "ha-worker", "ps-worker"}, {"db"}}, planNames(plan))
+	assert.Equal(t, []SkippedProcess{{Name: "tool", Reason: "startmode alone. not managed by juno"}}, plan.Skipped)
+
+	b, err := json.Marshal(plan)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"kind":"stop"`)
+	assert.Contains(t, string(b), `{"name":"db","group":"SVC","weight":10,"startsec":10}`)
+}
+
+func TestPlanDependencyAcrossTier(t *testing.T) {
+	// dependency wins over group order
+	config := &YamlFatimaPackageConfig{
+		Groups: []GroupItem{{Id: 1, Name: "OPM"}, {Id: 2, Name: "SVC"}},
+		Processes: []ProcessItem{
+			{Gid: 1, Name: "jupiter", Depends: []string{"store"}},
+			{Gid: 2, Name: "store"},
+			{Gid: 2, Name: "api"},
+		},
+	}
+
+	plan, err := config.PlanStart(ProcessPlanOption{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"store", "api"}, {"jupiter"}}, planNames(plan))
+}
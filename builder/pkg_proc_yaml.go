@@ -21,15 +21,11 @@
 package builder
 
 import (
-	"bytes"
-	"fmt"
-	"os"
 	"sort"
 	"strings"
 
 	"github.com/fatima-go/fatima-core"
 	"github.com/fatima-go/fatima-log"
-	"gopkg.in/yaml.v3"
 )
 
 type ProcessItem struct {
@@ -42,6 +38,21 @@ type ProcessItem struct {
 	Startmode int    `yaml:"startmode,omitempty"`
 	Weight    int    `yaml:"weight,omitempty"`   // 프로세스 가동 weight. weight 값이 높은 프로세스들을 먼저 가동시킨다
 	StartSec  int    `yaml:"startsec,omitempty"` // 프로세스가 가동 후 온라인이 될때까지 충분히 보장되는 시간(초)
+
+	// process launching descriptors (optional). see pkg_proc_descriptor.go
+	Env     map[string]string   `yaml:"env,omitempty"`
+	Args    []string            `yaml:"args,omitempty"`
+	WorkDir string              `yaml:"workdir,omitempty"`
+	Limits  *ProcessLimitItem   `yaml:"limits,omitempty"`
+	Restart *ProcessRestartItem `yaml:"restart,omitempty"`
+	Depends []string            `yaml:"depends,omitempty"`
+	Health  *ProcessHealthItem  `yaml:"health,omitempty"`
+
+	dropIn string // fatima-package.d file which declares this process. empty for fatima-package.yaml
+}
+
+func (p ProcessItem) source() string {
+	return sourceOfDropIn(p.dropIn)
 }
 
 func (p ProcessItem) GetGid() int {
@@ -89,9 +100,50 @@ func (p ProcessItem) GetLogLevel() log.LogLevel {
 	return buildLogLevel(p.Loglevel)
 }
 
+func (p ProcessItem) GetEnv() map[string]string {
+	env := make(map[string]string, len(p.Env))
+	for k, v := range p.Env {
+		env[k] = v
+	}
+	return env
+}
+
+func (p ProcessItem) GetArgs() []string {
+	return append([]string(nil), p.Args...)
+}
+
+func (p ProcessItem) GetWorkDir() string {
+	return p.WorkDir
+}
+
+// GetResourceLimit return resource limit. invalid values are rejected on loading
+func (p ProcessItem) GetResourceLimit() fatima.ProcessResourceLimit {
+	limit, _ := p.Limits.build()
+	return limit
+}
+
+func (p ProcessItem) GetRestartPolicy() fatima.ProcessRestartPolicy {
+	policy, _ := p.Restart.build()
+	return policy
+}
+
+func (p ProcessItem) GetDependencies() []string {
+	return append([]string(nil), p.Depends...)
+}
+
+func (p ProcessItem) GetHealthCheck() fatima.ProcessHealthCheck {
+	check, _ := p.Health.build()
+	return check
+}
+
 type GroupItem struct {
-	Id   int    `yaml:"id"`
-	Name string `yaml:"name"`
+	Id     int    `yaml:"id"`
+	Name   string `yaml:"name"`
+	dropIn string
+}
+
+func (g GroupItem) source() string {
+	return sourceOfDropIn(g.dropIn)
 }
 
 type GroupItems []GroupItem
@@ -108,73 +160,66 @@ type YamlFatimaPackageConfig struct {
 }
 
 // NewYamlFatimaPackageConfig load fatima package processes information
-// from $FATIMA_HOME/conf/fatima-package.yaml
+// from $FATIMA_HOME/conf/fatima-package.yaml (and conf/fatima-package.d/*.yaml)
 func NewYamlFatimaPackageConfig(env fatima.FatimaEnv) *YamlFatimaPackageConfig {
+	instance, err := LoadYamlFatimaPackageConfig(env)
+	check(err)
+	return instance
+}
+
+// LoadYamlFatimaPackageConfig same as NewYamlFatimaPackageConfig but return error instead of panic
+func LoadYamlFatimaPackageConfig(env fatima.FatimaEnv) (*YamlFatimaPackageConfig, error) {
 	instance := new(YamlFatimaPackageConfig)
 	instance.env = env
-	instance.Reload()
-	return instance
+	if err := instance.Reload(); err != nil {
+		return nil, err
+	}
+	return instance, nil
 }
 
+// OrderByGroup order processes by group id. OPM group (gid 1) comes first.
+// processes of undeclared group are placed at the end keeping current order
 func (y *YamlFatimaPackageConfig) OrderByGroup() {
-	ordered := make([]ProcessItem, 0)
+	sort.Stable(GroupItems(y.Groups))
 
-	sort.Sort(GroupItems(y.Groups))
-
-	// pickup OPM first
-	for _, v := range y.Processes {
-		if v.Gid == 1 {
-			ordered = append(ordered, v)
+	rank := make(map[int]int)
+	rank[1] = 0
+	for i, g := range y.Groups {
+		if _, ok := rank[g.Id]; !ok {
+			rank[g.Id] = i + 1
 		}
 	}
-
-	for i := 1; i < len(y.Groups); i++ {
-		for _, v := range y.Processes {
-			if v.Gid == y.Groups[i].Id {
-				ordered = append(ordered, v)
-			}
+	rankOf := func(gid int) int {
+		if r, ok := rank[gid]; ok {
+			return r
 		}
+		return len(y.Groups) + 1
 	}
 
-	y.Processes = ordered
+	sort.SliceStable(y.Processes, func(i, j int) bool {
+		return rankOf(y.Processes[i].Gid) < rankOf(y.Processes[j].Gid)
+	})
 }
 
+// Save write groups and processes to fatima-package.yaml (and fatima-package.d files which declare them).
+// error is logged. use SaveChanges to get error
 func (y *YamlFatimaPackageConfig) Save() {
-	d, err := yaml.Marshal(y)
-	if err != nil {
-		log.Warn("fail to create yaml data : %s", err.Error())
-		return
-	}
-
-	var comment = "---\n" +
-		"# this is fatima-package.yaml sample\n" +
-		"# group (group id, group name)\n" +
-		"# process list\n" +
-		"# column : gid, name, loglevel, path, startmode\n" +
-		"# - gid : group id\n" +
-		"# - startmode : default 0(always started by juno), 1(not started by juno), 2(by HA), 3(by PS)\n"
-
-	var buff bytes.Buffer
-	buff.WriteString(comment)
-	buff.Write(d)
-	err = os.WriteFile(y.env.GetFolderGuide().GetPackageProcFile(), buff.Bytes(), 0644)
-	if err != nil {
+	if err := y.SaveChanges(); err != nil {
 		log.Warn("fail to save yaml configuration file : %s", err.Error())
-		return
 	}
 }
 
-// Reload read file ($FATIMA_HOME/conf/fatima-package.yaml)
-func (y *YamlFatimaPackageConfig) Reload() {
-	data, err := os.ReadFile(y.env.GetFolderGuide().GetPackageProcFile())
-	check(err)
-
-	err = yaml.Unmarshal(data, &y)
-	check(err)
-
-	if len(y.Groups) == 0 || len(y.Processes) == 0 {
-		panic(fmt.Errorf("invalid fatima yaml configuration : %s", y.env.GetFolderGuide().GetPackageProcFile()))
+// Reload read file ($FATIMA_HOME/conf/fatima-package.yaml) and merge conf/fatima-package.d/*.yaml.
+// when reading or validation fails, current configuration is kept and error is returned
+func (y *YamlFatimaPackageConfig) Reload() error {
+	def, err := loadFatimaPackage(y.env.GetFolderGuide())
+	if err != nil {
+		return err
 	}
+
+	y.Groups = def.Groups
+	y.Processes = def.Processes
+	return nil
 }
 
 func (y *YamlFatimaPackageConfig) GetProcByName(name string) fatima.FatimaPkgProc {
@@ -275,7 +320,8 @@ func NewDummyFatimaPackageConfig(env fatima.FatimaEnv) *DummyFatimaPackageConfig
 	return instance
 }
 
-func (y *DummyFatimaPackageConfig) Reload() {
+func (y *DummyFatimaPackageConfig) Reload() error {
+	return nil
 }
 
 func (y *DummyFatimaPackageConfig) GetProcByName(name string) fatima.FatimaPkgProc {
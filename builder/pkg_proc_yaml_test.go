@@ -0,0 +1,370 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 23. 오전 11:20
+ */
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPackageEnv struct {
+	fatima.FatimaEnv
+	guide testPackageFolderGuide
+}
+
+func (e testPackageEnv) GetFolderGuide() fatima.FolderGuide {
+	return e.guide
+}
+
+type testPackageFolderGuide struct {
+	fatima.FolderGuide
+	home string
+}
+
+func (g testPackageFolderGuide) GetFatimaHome() string {
+	return g.home
+}
+
+func (g testPackageFolderGuide) GetPackageProcFile() string {
+	return filepath.Join(g.home, FatimaFolderConf, FatimaFileProcConfig)
+}
+
+const testPackageYaml = `
+group:
+  - {id: 1, name: OPM}
+  - {id: 2, name: SVC}
+process:
+  - {gid: 1, name: jupiter, loglevel: info}
+  - {gid: 2, name: api, loglevel: debug, path: app/api}
+`
+
+func prepareFatimaPackage(t *testing.T, dropIns map[string]string) testPackageEnv {
+	home := t.TempDir()
+	conf := filepath.Join(home, FatimaFolderConf)
+	require.NoError(t, os.MkdirAll(filepath.Join(conf, FatimaFolderProcConfigDropIn), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(conf, FatimaFileProcConfig), []byte(testPackageYaml), 0644))
+	for name, content := range dropIns {
+		require.NoError(t, os.WriteFile(filepath.Join(conf, FatimaFolderProcConfigDropIn, name), []byte(content), 0644))
+	}
+	return testPackageEnv{guide: testPackageFolderGuide{home: home}}
+}
+
+func TestYamlFatimaPackageConfigDropIn(t *testing.T) {
+	env := prepareFatimaPackage(t, map[string]string{
+		"20-batch.yaml": "group:\n  - {id: 3, name: BATCH}\nprocess:\n  - {gid: 3, name: batch, startmode: 1}\n",
+		"10-svc.yml":    "group:\n  - {id: 2, name: svc}\nprocess:\n  - {gid: 2, name: api2}\n",
+		"readme.txt":    "not a yaml",
+	})
+
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+	assert.Len(t, config.Groups, 4)
+	assert.Equal(t, 2, config.GetGroupId("svc"))
+	names := make([]string, 0)
+	for _, p := range config.GetAllProc(false) {
+		names = append(names, p.GetName())
+	}
+	assert.Equal(t, []string{"jupiter", "api", "api2", "batch"}, names)
+	assert.Equal(t, fatima.ProcessStartMode(fatima.StartModeAlone), config.GetProcByName("batch").GetStartMode())
+	assert.Len(t, config.GetProcByGroup("svc"), 2)
+
+	// drop-in items are not saved into fatima-package.yaml
+	config.Save()
+	def, err := readFatimaPackageFile(env.guide.GetPackageProcFile(), "")
+	require.NoError(t, err)
+	assert.Len(t, def.Groups, 2)
+	assert.Len(t, def.Processes, 2)
+}
+
+func TestYamlFatimaPackageConfigValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		dropIn string
+		errs   []string
+	}{
+		{"duplicated process", "process:\n  - {gid: 2, name: api}\n",
+			[]string{"fatima-package.d/x.yaml : duplicated process [api]. already defined in fatima-package.yaml"}},
+		{"unknown gid", "process:\n  - {gid: 9, name: x}\n", []string{"process [x] has unknown gid 9"}},
+		{"invalid startmode and loglevel", "process:\n  - {gid: 2, name: x, startmode: 7, loglevel: verbose}\n",
+			[]string{"invalid startmode 7", "invalid loglevel [verbose]"}},
+		{"group conflict", "group:\n  - {id: 2, name: OTHER}\n  - {id: 5, name: opm}\n",
+			[]string{"group id 2 is declared as [SVC]", "group [opm] is declared with id 1"}},
+		{"parse error", "process: [", []string{"fail to parse"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			env := prepareFatimaPackage(t, map[string]string{"x.yaml": c.dropIn})
+			_, err := LoadYamlFatimaPackageConfig(env)
+			require.Error(t, err)
+			for _, msg := range c.errs {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestYamlFatimaPackageConfigReloadKeepsCurrent(t *testing.T) {
+	env := prepareFatimaPackage(t, nil)
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(env.guide.GetPackageProcFile(), []byte("group: []\nprocess: []\n"), 0644))
+	err = config.Reload()
+	assert.ErrorContains(t, err, "no group")
+	assert.NotNil(t, config.GetProcByName("api"), "current configuration should be kept")
+
+	assert.Panics(t, func() { NewYamlFatimaPackageConfig(env) })
+}
+
+func TestProcessItemDescriptor(t *testing.T) {
+	env := prepareFatimaPackage(t, map[string]string{"api.yaml": `
+process:
+  - gid: 2
+    name: worker
+    env: {WORKER_MODE: batch, GOMAXPROCS: "2"}
+    args: [-port, "8080"]
+    workdir: app/worker
+    limits: {rlimit: {nofile: 65536, core: unlimited}, gomaxprocs: 4, gomemlimit: 1GB}
+    restart: {policy: on-failure, max: 5, backoff: 1s, maxbackoff: 5s}
+    depends: [jupiter, api]
+    health: {type: http, target: "http://127.0.0.1:8080/health", interval: 10s, timeout: 2s, retries: 3}
+`})
+
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+	proc := config.GetProcByName("worker")
+	require.NotNil(t, proc)
+
+	assert.Equal(t, []string{"-port", "8080"}, proc.GetArgs())
+	assert.Equal(t, "app/worker", proc.GetWorkDir())
+	assert.Equal(t, []string{"jupiter", "api"}, proc.GetDependencies())
+	assert.Equal(t, fatima.ProcessResourceLimit{
+		Rlimits:    map[string]uint64{"nofile": 65536, "core": fatima.RlimitUnlimited},
+		GoMaxProcs: 4,
+		GoMemLimit: 1 << 30,
+	}, proc.GetResourceLimit())
+
+	restart := proc.GetRestartPolicy()
+	assert.Equal(t, fatima.RestartOnFailure, restart.Policy)
+	assert.Equal(t, 5, restart.MaxRestarts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		[]time.Duration{restart.NextBackoff(1), restart.NextBackoff(2), restart.NextBackoff(3), restart.NextBackoff(4), restart.NextBackoff(100)})
+
+	assert.Equal(t, fatima.ProcessHealthCheck{Type: fatima.HealthCheckHttp, Target: "http://127.0.0.1:8080/health",
+		Interval: 10 * time.Second, Timeout: 2 * time.Second, Retries: 3}, proc.GetHealthCheck())
+
+	// env in descriptor overrides base and GOMAXPROCS of env wins over limits
+	environ := BuildProcessEnviron(proc, []string{"PATH=/bin", "WORKER_MODE=online"})
+	assert.Equal(t, []string{"PATH=/bin", "WORKER_MODE=batch", "GOMAXPROCS=2", "GOMEMLIMIT=1073741824B"}, environ)
+
+	// processes without descriptor
+	assert.Empty(t, config.GetProcByName("api").GetEnv())
+	assert.Equal(t, fatima.ProcessRestartPolicy{}, config.GetProcByName("api").GetRestartPolicy())
+}
+
+func TestProcessItemDescriptorValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		dropIn string
+		errs   []string
+	}{
+		{"limits", "process:\n  - {gid: 2, name: x, limits: {rlimit: {files: 10, nofile: many}, gomemlimit: 1XB}}\n",
+			[]string{"unsupported rlimit files", "rlimit nofile", "gomemlimit"}},
+		{"restart", "process:\n  - {gid: 2, name: x, restart: {policy: sometimes, backoff: 1m, maxbackoff: 1s}}\n",
+			[]string{"invalid restart policy sometimes", "backoff is greater than maxbackoff"}},
+		{"health", "process:\n  - {gid: 2, name: x, health: {type: tcp, interval: soon}}\n",
+			[]string{"health target is required for tcp", "health interval"}},
+		{"unknown dependency", "process:\n  - {gid: 2, name: x, depends: [x, nothing]}\n",
+			[]string{"process [x] depends on itself", "depends on unknown process [nothing]"}},
+		{"circular dependency", "process:\n  - {gid: 2, name: a, depends: [b]}\n  - {gid: 2, name: b, depends: [c]}\n  - {gid: 2, name: c, depends: [a]}\n",
+			[]string{"circular process dependency : a -> b -> c -> a"}},
+		{"env", "process:\n  - {gid: 2, name: x, env: {\"A=B\": c}}\n", []string{"invalid env name [A=B]"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			env := prepareFatimaPackage(t, map[string]string{"x.yaml": c.dropIn})
+			_, err := LoadYamlFatimaPackageConfig(env)
+			require.Error(t, err)
+			for _, msg := range c.errs {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestYamlFatimaPackageConfigEdit(t *testing.T) {
+	env := prepareFatimaPackage(t, map[string]string{
+		"batch.yaml": "# batch processes\nprocess:\n  - gid: 2\n    name: batch # nightly\n    loglevel: info\n",
+	})
+	mainFile := env.guide.GetPackageProcFile()
+	require.NoError(t, os.WriteFile(mainFile, []byte(`# my package
+group: [{id: 1, name: OPM}, {id: 2, name: SVC}]
+process:
+  # operation
+  - {gid: 1, name: jupiter, loglevel: info}
+  - gid: 2
+    name: api # public api
+    loglevel: debug
+    args: ["-port", "8080"]
+`), 0640))
+	require.NoError(t, os.Chmod(mainFile, 0640))
+
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+
+	require.NoError(t, config.AddGroup(GroupItem{Id: 3, Name: "BATCH"}))
+	assert.Error(t, config.AddGroup(GroupItem{Id: 4, Name: "svc"}))
+	require.NoError(t, config.AddProcess(ProcessItem{Gid: 3, Name: "report", Loglevel: "warn"}))
+	assert.Error(t, config.AddProcess(ProcessItem{Gid: 2, Name: "batch"}))
+	assert.Error(t, config.AddProcess(ProcessItem{Gid: 9, Name: "invalid"}), "invalid result should not be saved")
+
+	api := config.GetProcByName("api").(ProcessItem)
+	api.Loglevel = "info"
+	require.NoError(t, config.UpdateProcess(api))
+	batch := config.GetProcByName("batch").(ProcessItem)
+	batch.Gid = 3
+	require.NoError(t, config.UpdateProcess(batch))
+
+	assert.Error(t, config.RemoveGroup("svc"), "group having process cannot be removed")
+	assert.Error(t, config.RemoveProcess("nothing"))
+
+	data, err := os.ReadFile(mainFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# my package
+group: [{id: 1, name: OPM}, {id: 2, name: SVC}, {id: 3, name: BATCH}]
+process:
+  # operation
+  - {gid: 1, name: jupiter, loglevel: info}
+  - gid: 2
+    name: api # public api
+    loglevel: info
+    args: ["-port", "8080"]
+  - gid: 3
+    name: report
+    loglevel: warn
+`, string(data))
+	info, err := os.Stat(mainFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	_, err = os.Stat(mainFile + fatimaPackageBackupSuffix)
+	assert.NoError(t, err)
+
+	data, err = os.ReadFile(filepath.Join(filepath.Dir(mainFile), FatimaFolderProcConfigDropIn, "batch.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "# batch processes\nprocess:\n  - gid: 3\n    name: batch # nightly\n    loglevel: info\n", string(data))
+
+	require.NoError(t, config.RemoveProcess("report"))
+	require.NoError(t, config.RemoveProcess("batch"))
+	require.NoError(t, config.RemoveGroup("batch"))
+	assert.Nil(t, config.GetProcByName("batch"))
+	assert.Equal(t, -1, config.GetGroupId("batch"))
+
+	reloaded, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+	assert.Equal(t, config.Processes, reloaded.Processes)
+}
+
+func TestYamlFatimaPackageConfigEditKeepsOthers(t *testing.T) {
+	env := prepareFatimaPackage(t, nil)
+	mainFile := env.guide.GetPackageProcFile()
+	jupiter := `  - gid: 1
+    name: jupiter
+    loglevel: info
+    hb: false
+    weight: 0
+    startmode: 0 # juno starts it
+    juno_only_field: x
+`
+	api := `  - gid: 2
+    name: api
+    loglevel: debug
+    hb: true
+    juno_only_field: y
+`
+	require.NoError(t, os.WriteFile(mainFile, []byte("group: [{id: 1, name: OPM}, {id: 2, name: SVC}]\nprocess:\n"+jupiter+api), 0644))
+
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+	require.NoError(t, config.AddProcess(ProcessItem{Gid: 2, Name: "other", Loglevel: "warn"}))
+
+	data, err := os.ReadFile(mainFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "process:\n"+jupiter+api+"  - gid: 2\n    name: other\n")
+
+	// only changed field is rewritten. unknown field is kept
+	item := config.GetProcByName("api").(ProcessItem)
+	item.Hb = false
+	require.NoError(t, config.UpdateProcess(item))
+	data, err = os.ReadFile(mainFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "process:\n"+jupiter+`  - gid: 2
+    name: api
+    loglevel: debug
+    hb: false
+    juno_only_field: y
+`)
+}
+
+func TestYamlFatimaPackageConfigLocked(t *testing.T) {
+	env := prepareFatimaPackage(t, nil)
+	config, err := LoadYamlFatimaPackageConfig(env)
+	require.NoError(t, err)
+
+	lock, err := config.lock()
+	require.NoError(t, err)
+	done := make(chan error)
+	go func() {
+		done <- config.AddProcess(ProcessItem{Gid: 2, Name: "later"})
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("edit should wait for lock")
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.NoError(t, lock.Unlock())
+	require.NoError(t, <-done)
+	assert.NotNil(t, config.GetProcByName("later"))
+}
+
+func TestOrderByGroup(t *testing.T) {
+	config := &YamlFatimaPackageConfig{
+		Groups: []GroupItem{{Id: 3, Name: "C"}, {Id: 1, Name: "OPM"}, {Id: 2, Name: "B"}},
+		Processes: []ProcessItem{
+			{Gid: 3, Name: "c1"}, {Gid: 9, Name: "orphan"}, {Gid: 2, Name: "b1"}, {Gid: 1, Name: "opm"}, {Gid: 3, Name: "c2"},
+		},
+	}
+	config.OrderByGroup()
+
+	names := make([]string, 0)
+	for _, p := range config.Processes {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"opm", "b1", "c1", "c2", "orphan"}, names)
+}
@@ -0,0 +1,296 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 19. 오후 2:30
+ */
+
+package builder
+
+/*
+predefine expression syntax
+
+	${key}                 predefine(fatima-package-predefine.properties) or builtin variable
+	${key:-fallback}       fallback is used when key is not found. fallback can contain expressions
+	${env.HOME}            os environment variable
+	${var.${env.X}.host}   nested expression. inner expression is resolved first
+	${fn.date(20060102)}   builtin function. see predefineFunctions
+	$${literal}            escaped. results in ${literal}
+*/
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatima-go/fatima-core/crypt"
+)
+
+const (
+	expressionEnvPrefix      = "env."
+	expressionFunctionPrefix = "fn."
+	expressionDefaultToken   = ":-"
+)
+
+// PredefineExpressionResolver resolves predefine expressions and reports
+// unresolved placeholders (strict mode) or reference cycles as error
+type PredefineExpressionResolver interface {
+	ResolveExpression(value string) (string, error)
+}
+
+// PredefineFunc builtin function for ${fn.name(args)} expression. args is (already resolved) raw text inside parentheses
+type PredefineFunc func(args string) (string, error)
+
+var (
+	predefineFunctionLock sync.RWMutex
+	predefineFunctions    = map[string]PredefineFunc{
+		"date": func(args string) (string, error) {
+			if len(strings.TrimSpace(args)) == 0 {
+				return "", errors.New("date layout is required. e.g) fn.date(20060102)")
+			}
+			return time.Now().Format(strings.TrimSpace(args)), nil
+		},
+		"hostname": func(args string) (string, error) {
+			return os.Hostname()
+		},
+		"upper": func(args string) (string, error) {
+			return strings.ToUpper(args), nil
+		},
+		"lower": func(args string) (string, error) {
+			return strings.ToLower(args), nil
+		},
+	}
+)
+
+// RegisterPredefineFunction register (or replace) builtin function usable as ${fn.name(args)}
+func RegisterPredefineFunction(name string, fn PredefineFunc) {
+	predefineFunctionLock.Lock()
+	defer predefineFunctionLock.Unlock()
+	predefineFunctions[name] = fn
+}
+
+func getPredefineFunction(name string) (PredefineFunc, bool) {
+	predefineFunctionLock.RLock()
+	defer predefineFunctionLock.RUnlock()
+	fn, ok := predefineFunctions[name]
+	return fn, ok
+}
+
+// builtinLookup find builtin variable value by key.
+// dynamic is true when the value can differ on every lookup (e.g. date)
+type builtinLookup func(key string) (value string, dynamic bool, ok bool)
+
+// predefineExpression interpolation engine for predefine expressions
+type predefineExpression struct {
+	builtin builtinLookup     // builtin variable. value is not expanded
+	defines map[string]string // raw predefine values. value is expanded on lookup
+	strict  bool              // fail on unresolved placeholder
+}
+
+// expandState state shared while expanding a value
+type expandState struct {
+	dynamic bool // value refers dynamic builtin or function. result can differ on every resolve
+}
+
+func newPredefineExpression(builtin builtinLookup, defines map[string]string) *predefineExpression {
+	if defines == nil {
+		defines = make(map[string]string)
+	}
+	return &predefineExpression{builtin: builtin, defines: defines}
+}
+
+func (e *predefineExpression) resolve(value string) (string, error) {
+	return e.expand(value, nil, &expandState{})
+}
+
+// resolveDynamic resolve value and report whether value refers dynamic builtin or function
+func (e *predefineExpression) resolveDynamic(value string) (string, bool, error) {
+	state := &expandState{}
+	v, err := e.expand(value, nil, state)
+	return v, state.dynamic, err
+}
+
+// expand replace every ${...} expression in s
+func (e *predefineExpression) expand(s string, stack []string, state *expandState) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var buff strings.Builder
+	var errs []error
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			buff.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			buff.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end := findExpressionEnd(s, i+2)
+		if end < 0 {
+			// not terminated. leave as it is
+			buff.WriteString(s[i:])
+			break
+		}
+
+		v, resolved, err := e.evaluate(s[i+2:end], stack, state)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+			buff.WriteString(s[i : end+1])
+		case !resolved:
+			buff.WriteString(s[i : end+1])
+		default:
+			buff.WriteString(v)
+		}
+		i = end + 1
+	}
+
+	return buff.String(), errors.Join(errs...)
+}
+
+// findExpressionEnd find index of '}' which closes expression started before 'from'
+func findExpressionEnd(s string, from int) int {
+	depth := 0
+	for i := from; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// splitDefault split inner expression to key and fallback (key:-fallback) at nesting depth 0
+func splitDefault(inner string) (string, string, bool) {
+	depth := 0
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], "${"):
+			depth++
+			i++
+		case inner[i] == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(inner[i:], expressionDefaultToken):
+			return inner[:i], inner[i+len(expressionDefaultToken):], true
+		}
+	}
+	return inner, "", false
+}
+
+// evaluate inner expression (without ${ and }).
+// resolved is false when the key is not found and there is no fallback (non-strict mode)
+func (e *predefineExpression) evaluate(inner string, stack []string, state *expandState) (string, bool, error) {
+	rawKey, rawFallback, hasFallback := splitDefault(inner)
+	key, err := e.expand(rawKey, stack, state)
+	if err != nil {
+		return "", false, err
+	}
+	key = strings.TrimSpace(key)
+
+	v, found, err := e.lookup(key, stack, state)
+	if err != nil {
+		return "", false, err
+	}
+	if found {
+		return v, true, nil
+	}
+
+	if hasFallback {
+		v, err = e.expand(rawFallback, stack, state)
+		return v, err == nil, err
+	}
+
+	if e.strict {
+		return "", false, fmt.Errorf("unresolved placeholder ${%s}", key)
+	}
+	return "", false, nil
+}
+
+func (e *predefineExpression) lookup(key string, stack []string, state *expandState) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(key, expressionEnvPrefix):
+		v, ok := os.LookupEnv(key[len(expressionEnvPrefix):])
+		return v, ok, nil
+	case strings.HasPrefix(key, expressionFunctionPrefix):
+		// function result (e.g. date, hostname) is not cached
+		state.dynamic = true
+		return e.call(key[len(expressionFunctionPrefix):])
+	}
+
+	if e.builtin != nil {
+		if v, dynamic, ok := e.builtin(key); ok {
+			state.dynamic = state.dynamic || dynamic
+			return v, true, nil
+		}
+	}
+
+	raw, ok := e.defines[key]
+	if !ok {
+		return "", false, nil
+	}
+
+	for _, k := range stack {
+		if k == key {
+			return "", false, fmt.Errorf("predefine reference cycle detected : %s -> %s", strings.Join(stack, " -> "), key)
+		}
+	}
+
+	next := make([]string, len(stack), len(stack)+1)
+	copy(next, stack)
+	v, err := e.expand(raw, append(next, key), state)
+	if err != nil {
+		return "", false, err
+	}
+	if strings.HasSuffix(key, SecretKeySuffix) {
+		v = crypt.ResolveSecret(v)
+	}
+	if crypt.IsSecretKey(key) {
+		crypt.RegisterSecretValue(v)
+	}
+	return v, true, nil
+}
+
+// call builtin function. e.g) date(20060102)
+func (e *predefineExpression) call(expr string) (string, bool, error) {
+	open := strings.Index(expr, "(")
+	if open < 1 || !strings.HasSuffix(expr, ")") {
+		return "", false, fmt.Errorf("invalid function expression : fn.%s", expr)
+	}
+	name := strings.TrimSpace(expr[:open])
+	fn, ok := getPredefineFunction(name)
+	if !ok {
+		return "", false, fmt.Errorf("unknown predefine function : %s", name)
+	}
+	v, err := fn(expr[open+1 : len(expr)-1])
+	if err != nil {
+		return "", false, fmt.Errorf("fn.%s : %w", name, err)
+	}
+	return v, true, nil
+}
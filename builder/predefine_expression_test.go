@@ -0,0 +1,144 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 19. 오후 4:10
+ */
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredefineExpressionResolve(t *testing.T) {
+	t.Setenv("FATIMA_TEST_ZONE", "seoul")
+	RegisterPredefineFunction("reverse", func(args string) (string, error) {
+		r := []rune(args)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r), nil
+	})
+
+	builtin := func(key string) (string, bool, bool) {
+		if key == "fatima.home" {
+			return "/fatima", false, true
+		}
+		return "", false, false
+	}
+	defines := map[string]string{
+		"var.db.host":        "db.local",
+		"var.seoul.host":     "seoul.local",
+		"var.db.url":         "jdbc://${var.db.host}:${var.db.port:-3306}",
+		"var.log.dir":        "${fatima.home}/log",
+		"var.cycle.a":        "${var.cycle.b}",
+		"var.cycle.b":        "${var.cycle.a}",
+		"var.escaped":        "$${var.db.host}",
+		"var.name":           "Fatima",
+		"var.unterminated":   "${var.db.host",
+		"var.nested.default": "${var.none:-${var.db.host}}",
+	}
+
+	tests := []struct {
+		name    string
+		strict  bool
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "plain", value: "no expression", want: "no expression"},
+		{name: "define", value: "${var.db.host}", want: "db.local"},
+		{name: "builtin", value: "${fatima.home}/data", want: "/fatima/data"},
+		{name: "recursive_define", value: "${var.db.url}", want: "jdbc://db.local:3306"},
+		{name: "define_with_builtin", value: "${var.log.dir}", want: "/fatima/log"},
+		{name: "default_unused", value: "${var.db.host:-other}", want: "db.local"},
+		{name: "default_used", value: "${var.none:-other}", want: "other"},
+		{name: "default_empty", value: "[${var.none:-}]", want: "[]"},
+		{name: "default_nested", value: "${var.nested.default}", want: "db.local"},
+		{name: "env", value: "${env.FATIMA_TEST_ZONE}", want: "seoul"},
+		{name: "env_missing_default", value: "${env.FATIMA_TEST_NONE:-none}", want: "none"},
+		{name: "nested_key", value: "${var.${env.FATIMA_TEST_ZONE}.host}", want: "seoul.local"},
+		{name: "function", value: "${fn.upper(${var.name})}", want: "FATIMA"},
+		{name: "registered_function", value: "${fn.reverse(abc)}", want: "cba"},
+		{name: "escape", value: "$${var.db.host}", want: "${var.db.host}"},
+		{name: "escaped_define", value: "${var.escaped}", want: "${var.db.host}"},
+		{name: "unterminated", value: "${var.unterminated}", want: "${var.db.host"},
+		{name: "unresolved_kept", value: "${var.none}/x", want: "${var.none}/x"},
+		{name: "unresolved_strict", strict: true, value: "${var.none}/x", wantErr: "unresolved placeholder ${var.none}"},
+		{name: "cycle", value: "${var.cycle.a}", wantErr: "predefine reference cycle detected : var.cycle.a -> var.cycle.b -> var.cycle.a"},
+		{name: "unknown_function", value: "${fn.nothing()}", wantErr: "unknown predefine function : nothing"},
+		{name: "invalid_function", value: "${fn.upper}", wantErr: "invalid function expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression := newPredefineExpression(builtin, defines)
+			expression.strict = tt.strict
+			got, err := expression.resolve(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPredefineFunctionDate(t *testing.T) {
+	expression := newPredefineExpression(nil, nil)
+	got, err := expression.resolve("app-${fn.date(2006)}.log")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "app-2"))
+	assert.Len(t, got, len("app-2026.log"))
+}
+
+func TestPredefineExpressionDynamic(t *testing.T) {
+	builtin := func(key string) (string, bool, bool) {
+		switch key {
+		case "var.builtin.date.yyyymmdd":
+			return "20261019", true, true
+		case "var.builtin.pid":
+			return "1234", false, true
+		}
+		return "", false, false
+	}
+	defines := map[string]string{"var.log.dir": "/log/${var.builtin.date.yyyymmdd}"}
+
+	tests := []struct {
+		name    string
+		value   string
+		dynamic bool
+	}{
+		{name: "plain", value: "/data"},
+		{name: "static_builtin", value: "${var.builtin.pid}"},
+		{name: "dynamic_builtin", value: "${var.builtin.date.yyyymmdd}", dynamic: true},
+		{name: "dynamic_via_define", value: "${var.log.dir}/app.log", dynamic: true},
+		{name: "function", value: "${fn.upper(a)}", dynamic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, dynamic, err := newPredefineExpression(builtin, defines).resolveDynamic(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.dynamic, dynamic)
+		})
+	}
+}
@@ -31,10 +31,13 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/fatima-go/fatima-core"
 	"github.com/fatima-go/fatima-core/builder/platform"
+	"github.com/fatima-go/fatima-core/crypt"
 	"github.com/fatima-go/fatima-core/ipc"
 	"github.com/fatima-go/fatima-core/lib"
 	"github.com/fatima-go/fatima-core/monitor"
@@ -67,8 +70,10 @@ const (
 	tagProcess     = "process"
 )
 
-// process init
-func init() {
+// bootstrapProcess prepares this fatima process : signals, logging, single instance and notify handler.
+// it runs once when the runtime is first requested (not on package import) so that
+// package level tools importing builder (e.g. supervisor) are not treated as a fatima process
+func bootstrapProcess() {
 	log.SetLevel(log.LOG_TRACE)
 
 	// handle process signals
@@ -82,7 +87,7 @@ func init() {
 
 	// fatima-log initialize
 	if fatimaProcess.env.GetFolderGuide().IsAppExist() {
-		logPref := log.NewPreferenceWithProcName(fatimaProcess.env.GetFolderGuide().GetLogFolder(), fatimaProcess.env.GetSystemProc().GetProgramName())
+		logPref := log.NewPreferenceWithProcName(resolveLogFolder(fatimaProcess.env), fatimaProcess.env.GetSystemProc().GetProgramName())
 		logPref.DeliveryMode = log.DELIVERY_MODE_ASYNC
 		log.Initialize(logPref)
 	} else {
@@ -107,6 +112,7 @@ func init() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
 		os.Exit(0)
 	}
+	fatimaProcess.logLevel = NewLogLevelManager(log.GetLevel(), fatimaProcess.notifyHandler)
 
 	log.Warn("%s is starting", fatimaProcess.env.GetSystemProc().GetProgramName())
 
@@ -128,6 +134,29 @@ func newFatimaProcessEnv() *FatimaProcessEnv {
 	return processEnv
 }
 
+// NewPackageFatimaEnv create FatimaEnv for package level tools (e.g. supervisor) running outside of fatima process.
+// only fatima home and conf folder are resolved. program folders are not created
+func NewPackageFatimaEnv(fatimaHome string) fatima.FatimaEnv {
+	folderGuide := new(FatimaFolderGuide)
+	folderGuide.fatimaHomePath = fatimaHome
+	folderGuide.conf = filepath.Join(fatimaHome, FatimaFolderConf)
+
+	processEnv := new(FatimaProcessEnv)
+	processEnv.systemProc = newSystemProc()
+	processEnv.folderGuide = folderGuide
+	processEnv.profile = os.Getenv(fatima.ENV_FATIMA_PROFILE)
+	return processEnv
+}
+
+// resolveLogFolder log folder templated by package global property (var.global.log.folder) or app log folder
+func resolveLogFolder(env fatima.FatimaEnv) string {
+	v, ok := NewPropertyPredefineReader(env).GetDefine(GlobalDefineLogFolder)
+	if v = strings.TrimSpace(v); ok && len(v) > 0 {
+		return v
+	}
+	return env.GetFolderGuide().GetLogFolder()
+}
+
 // create platform support utility
 func createPlatformSupport() fatima.PlatformSupport {
 	return new(platform.OSPlatform)
@@ -136,8 +165,10 @@ func createPlatformSupport() fatima.PlatformSupport {
 type FatimaProcessStatus uint8
 
 var fatimaProcess *FatimaRuntimeProcess = new(FatimaRuntimeProcess)
+var bootstrapOnce sync.Once
 
 func NewFatimaRuntime() *FatimaRuntimeProcess {
+	bootstrapOnce.Do(bootstrapProcess)
 	return fatimaProcess
 }
 
@@ -190,7 +221,7 @@ type FatimaRuntimeProcess struct {
 	platform      fatima.PlatformSupport
 	systemStatus  FatimaPackageSystemStatus
 	sigs          chan os.Signal
-	logLevel      log.LogLevel
+	logLevel      *LogLevelManager
 	builder       FatimaRuntimeBuilder
 	packaging     *FatimaPackaging
 	interactor    fatima.ProcessInteractor
@@ -202,12 +233,43 @@ func (process *FatimaRuntimeProcess) GetEnv() fatima.FatimaEnv {
 	return process.env
 }
 
+// GetLogLevel return current process log level
 func (process *FatimaRuntimeProcess) GetLogLevel() log.LogLevel {
-	return process.logLevel
+	return process.logLevel.GetLevel()
+}
+
+// GetBaseLogLevel return log level from fatima-package.yaml or cfm loglevels
+func (process *FatimaRuntimeProcess) GetBaseLogLevel() log.LogLevel {
+	return process.logLevel.GetBaseLevel()
 }
 
+// SetLogLevel change base log level (cfm loglevels)
 func (process *FatimaRuntimeProcess) SetLogLevel(logLevel log.LogLevel) {
-	process.logLevel = logLevel
+	process.logLevel.SetBaseLevel(logLevel, fatima.LogLevelSourceCfm)
+}
+
+func (process *FatimaRuntimeProcess) GetLogLevelManager() *LogLevelManager {
+	return process.logLevel
+}
+
+func (process *FatimaRuntimeProcess) ChangeLogLevel(level log.LogLevel, ttl time.Duration, source string) error {
+	return process.logLevel.ChangeLogLevel(level, ttl, source)
+}
+
+func (process *FatimaRuntimeProcess) RevertLogLevel(source string) {
+	process.logLevel.RevertLogLevel(source)
+}
+
+func (process *FatimaRuntimeProcess) ChangeLoggerLevel(name string, level log.LogLevel, ttl time.Duration, source string) error {
+	return process.logLevel.ChangeLoggerLevel(name, level, ttl, source)
+}
+
+func (process *FatimaRuntimeProcess) RevertLoggerLevel(name string, source string) {
+	process.logLevel.RevertLoggerLevel(name, source)
+}
+
+func (process *FatimaRuntimeProcess) GetLogLevelStatus() fatima.LogLevelStatus {
+	return process.logLevel.GetLogLevelStatus()
 }
 
 func (process *FatimaRuntimeProcess) SetInteractor(interactor fatima.ProcessInteractor) {
@@ -220,7 +282,7 @@ func (process *FatimaRuntimeProcess) GetConfig() fatima.Config {
 
 func (process *FatimaRuntimeProcess) GetPackaging() fatima.Packaging {
 	if process.packaging == nil {
-		pack := FatimaPackaging{name: "default", host: "unknown", group: "basic"}
+		pack := FatimaPackaging{name: defaultPackageName, host: "unknown", group: defaultPackageGroup}
 		v, ok := process.builder.GetPredefines().GetDefine(GlobalDefinePackageGroupname)
 		if ok {
 			pack.group = v
@@ -254,6 +316,14 @@ func (process *FatimaRuntimeProcess) GetSystemNotifyHandler() monitor.SystemNoti
 	return process.notifyHandler
 }
 
+// GetSystemStatusManager return manager which changes HA, PS status and log levels (cfm). nil before interactor is set
+func (process *FatimaRuntimeProcess) GetSystemStatusManager() monitor.SystemStatusManager {
+	if provider, ok := process.interactor.(monitor.SystemStatusManagerProvider); ok {
+		return provider.GetSystemStatusManager()
+	}
+	return nil
+}
+
 func (process *FatimaRuntimeProcess) GetBuilder() FatimaRuntimeBuilder {
 	return process.builder
 }
@@ -355,6 +425,14 @@ func (process *FatimaRuntimeProcess) RegisterMeasureUnit(unit monitor.SystemMeas
 	}
 }
 
+// GetMetricRegistry nil before interactor is set or when interactor does not provide registry
+func (process *FatimaRuntimeProcess) GetMetricRegistry() monitor.MetricRegistry {
+	if provider, ok := process.interactor.(monitor.MetricRegistryProvider); ok {
+		return provider.GetMetricRegistry()
+	}
+	return nil
+}
+
 // Initialize : initialize process
 func (process *FatimaRuntimeProcess) Initialize(builder FatimaRuntimeBuilder) {
 	if process.status >= procStatusInitializing {
@@ -371,10 +449,8 @@ func (process *FatimaRuntimeProcess) Initialize(builder FatimaRuntimeBuilder) {
 	buildLogging(builder)
 
 	// match log level
-	process.logLevel = pkgProc.GetLogLevel()
-	if process.logLevel != log.GetLevel() {
-		log.SetLevel(process.logLevel)
-		log.Info("change log level : %s", process.logLevel)
+	if process.logLevel.resetBaseLevel(pkgProc.GetLogLevel()) {
+		log.Info("change log level : %s", pkgProc.GetLogLevel())
 	}
 
 	// initialize process 'proc' folder
@@ -529,7 +605,7 @@ func getFileSize(p string) int {
 
 func check(e error) {
 	if e != nil {
-		panic(fmt.Errorf("fail to build runtime : %w", e))
+		panic(fmt.Errorf("fail to build runtime : %w", crypt.RedactError(e)))
 	}
 }
 
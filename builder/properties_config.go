@@ -24,10 +24,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/crypt"
 	log "github.com/fatima-go/fatima-log"
 )
 
@@ -37,6 +38,7 @@ type PropertyConfigReader struct {
 	format          string
 	yamlListKeys    map[string]bool
 	yamlSkippedKeys map[string]bool
+	templates       map[string]string // raw values referring dynamic builtin. resolved on every lookup
 }
 
 // NewPropertyConfigReader serving properties(key/value) for process
@@ -46,55 +48,170 @@ func NewPropertyConfigReader(env fatima.FatimaEnv, predefines fatima.Predefines)
 		env.GetProfile(),
 		predefines,
 	)
+
+	// fail startup if predefine expression cannot be resolved (strict mode) or config does not satisfy registered schema
+	check(loaded.Err())
+	check(validateApplicationConfig(loaded.Values))
+
 	instance := &PropertyConfigReader{
 		predefines:      predefines,
 		configuration:   loaded.Values,
 		format:          loaded.Format,
 		yamlListKeys:    loaded.YamlListKeys,
 		yamlSkippedKeys: loaded.YamlSkippedKeys,
+		templates:       loaded.Templates,
 	}
 	return instance
 }
 
 func (this *PropertyConfigReader) GetValue(key string) (string, bool) {
+	// value referring dynamic builtin (e.g. ${var.builtin.date.yyyymmdd}) should be current
+	if t, ok := this.templates[key]; ok {
+		return this.predefines.ResolvePredefine(t), true
+	}
 	v, ok := this.configuration[key]
 	return v, ok
 }
 
 func (this *PropertyConfigReader) GetString(key string) (string, error) {
-	v, ok := this.configuration[key]
+	v, ok := this.GetValue(key)
 	if !ok {
 		return "", fmt.Errorf("not found key in config : %s", key)
 	}
 	return v, nil
 }
 
+// GetSecret value which is masked when printed. use Reveal to get plaintext
+func (this *PropertyConfigReader) GetSecret(key string) (crypt.Secret, error) {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return "", fmt.Errorf("not found key in config : %s", key)
+	}
+	return crypt.Secret(v), nil
+}
+
 func (this *PropertyConfigReader) GetInt(key string) (int, error) {
-	v, ok := this.configuration[key]
+	v, ok := this.GetValue(key)
 	if !ok {
 		return 0, fmt.Errorf("not found key in config : %s", key)
 	}
 
-	i, err := strconv.Atoi(v)
+	i, err := ParseConfigInt(v)
 	if err != nil {
-		return 0, fmt.Errorf("not numeric value for key %s : %s", key, err.Error())
+		return 0, invalidValueError(key, "numeric", err)
 	}
 
 	return i, nil
 }
 
 func (this *PropertyConfigReader) GetBool(key string) (bool, error) {
-	v, ok := this.configuration[key]
+	v, ok := this.GetValue(key)
 	if !ok {
 		return false, fmt.Errorf("not found key in config : %s", key)
 	}
 
-	switch strings.ToUpper(v) {
-	case "TRUE":
-		return true, nil
+	b, err := ParseConfigBool(v)
+	if err != nil {
+		return false, invalidValueError(key, "bool", err)
+	}
+
+	return b, nil
+}
+
+func (this *PropertyConfigReader) GetInt64(key string) (int64, error) {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return 0, fmt.Errorf("not found key in config : %s", key)
+	}
+
+	i, err := ParseConfigInt64(v)
+	if err != nil {
+		return 0, invalidValueError(key, "numeric", err)
+	}
+
+	return i, nil
+}
+
+func (this *PropertyConfigReader) GetFloat(key string) (float64, error) {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return 0, fmt.Errorf("not found key in config : %s", key)
+	}
+
+	f, err := ParseConfigFloat(v)
+	if err != nil {
+		return 0, invalidValueError(key, "numeric", err)
+	}
+
+	return f, nil
+}
+
+func (this *PropertyConfigReader) GetDuration(key string) (time.Duration, error) {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return 0, fmt.Errorf("not found key in config : %s", key)
+	}
+
+	d, err := ParseConfigDuration(v)
+	if err != nil {
+		return 0, invalidValueError(key, "duration", err)
+	}
+
+	return d, nil
+}
+
+func (this *PropertyConfigReader) GetBytes(key string) (int64, error) {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return 0, fmt.Errorf("not found key in config : %s", key)
 	}
 
-	return false, nil
+	b, err := ParseConfigBytes(v)
+	if err != nil {
+		return 0, invalidValueError(key, "byte size", err)
+	}
+
+	return b, nil
+}
+
+func (this *PropertyConfigReader) GetStringMap(prefix string) map[string]string {
+	m := FilterStringMap(this.configuration, prefix)
+	for k, v := range FilterStringMap(this.templates, prefix) {
+		m[k] = this.predefines.ResolvePredefine(v)
+	}
+	return m
+}
+
+func (this *PropertyConfigReader) GetStringOrDefault(key string, defaultValue string) string {
+	v, ok := this.GetValue(key)
+	if !ok {
+		return defaultValue
+	}
+	return v
+}
+
+func (this *PropertyConfigReader) GetIntOrDefault(key string, defaultValue int) int {
+	return ConfigValueOrDefault(this.GetInt, key, defaultValue)
+}
+
+func (this *PropertyConfigReader) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	return ConfigValueOrDefault(this.GetInt64, key, defaultValue)
+}
+
+func (this *PropertyConfigReader) GetFloatOrDefault(key string, defaultValue float64) float64 {
+	return ConfigValueOrDefault(this.GetFloat, key, defaultValue)
+}
+
+func (this *PropertyConfigReader) GetBoolOrDefault(key string, defaultValue bool) bool {
+	return ConfigValueOrDefault(this.GetBool, key, defaultValue)
+}
+
+func (this *PropertyConfigReader) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return ConfigValueOrDefault(this.GetDuration, key, defaultValue)
+}
+
+func (this *PropertyConfigReader) GetBytesOrDefault(key string, defaultValue int64) int64 {
+	return ConfigValueOrDefault(this.GetBytes, key, defaultValue)
 }
 
 func (this *PropertyConfigReader) GetList(key string) ([]string, error) {
@@ -102,7 +219,7 @@ func (this *PropertyConfigReader) GetList(key string) ([]string, error) {
 		return nil, fmt.Errorf("unsupported value type for list at key : %s", key)
 	}
 
-	v, ok := this.configuration[key]
+	v, ok := this.GetValue(key)
 	if !ok {
 		return nil, fmt.Errorf("not found key in config : %s", key)
 	}
@@ -126,6 +243,19 @@ func (this *PropertyConfigReader) GetDefine(key string) (string, bool) {
 	return this.predefines.GetDefine(key)
 }
 
+// GetMaskedValues return copy of configuration with secret values masked. use it for config dump
+func (this *PropertyConfigReader) GetMaskedValues() map[string]string {
+	return crypt.MaskValues(this.configuration)
+}
+
+// invalidValueError build typed getter error. secret value never appears in the error
+func invalidValueError(key, kind string, err error) error {
+	if crypt.IsSecretKey(key) {
+		return fmt.Errorf("not %s value for key %s", kind, key)
+	}
+	return crypt.RedactError(fmt.Errorf("not %s value for key %s : %s", kind, key, err.Error()))
+}
+
 // SplitCommaTrim splits s by comma, trims whitespace from each element, and removes empty elements.
 func SplitCommaTrim(s string) []string {
 	parts := strings.Split(s, ",")
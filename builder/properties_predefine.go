@@ -23,39 +23,49 @@ package builder
 import (
 	"fmt"
 	"net"
+	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatima-go/fatima-core"
-	"github.com/fatima-go/fatima-core/crypt"
+	log "github.com/fatima-go/fatima-log"
 )
 
 type buildtinVariable string
 
 type variableValue struct {
-	key   buildtinVariable
-	value string
+	key     buildtinVariable
+	value   string
+	compute func() string // dynamic variable. evaluated on every lookup (e.g. date)
 }
 
 func (this variableValue) getValue() string {
-	switch this.key {
-	case BuiltinVariableYyyymm:
-		return time.Now().Format("200601")
-	case BuiltinVariableYyyymmdd:
-		return time.Now().Format("20060102")
+	if this.compute != nil {
+		return this.compute()
 	}
 	return this.value
 }
 
+func (this variableValue) isDynamic() bool {
+	return this.compute != nil
+}
+
+func dateVariable(key buildtinVariable, layout string) variableValue {
+	return variableValue{key: key, compute: func() string {
+		return time.Now().Format(layout)
+	}}
+}
+
 type PropertyPredefineReader struct {
 	env              fatima.FatimaEnv
 	builtinVariables []variableValue
 
-	// defines service key/value properties
-	defines  map[string]string
-	replacer *strings.Replacer
+	// defines service key/value properties (as written in predefine file)
+	defines    map[string]string
+	expression *predefineExpression
 }
 
 // NewPropertyPredefineReader serving fatima package global(shared) properties
@@ -64,6 +74,10 @@ func NewPropertyPredefineReader(env fatima.FatimaEnv) *PropertyPredefineReader {
 	instance.env = env
 	instance.defines = make(map[string]string)
 
+	// load fatima package global properties (fatima-package-predefine.properties)
+	instance.loadDefines()
+	instance.expression = newPredefineExpression(instance.lookupBuiltin, instance.defines)
+
 	// create builtin properties
 	instance.buildBuiltin()
 
@@ -73,59 +87,131 @@ func NewPropertyPredefineReader(env fatima.FatimaEnv) *PropertyPredefineReader {
 }
 
 func (reader *PropertyPredefineReader) ResolvePredefine(value string) string {
-	return reader.replacer.Replace(value)
+	v, err := reader.ResolveExpression(value)
+	if err != nil {
+		log.Warn("fail to resolve predefine expression : %s", err.Error())
+	}
+	return v
+}
+
+// ResolveExpression resolve predefine expressions in value.
+// unresolved placeholders are reported as error only in strict mode
+func (reader *PropertyPredefineReader) ResolveExpression(value string) (string, error) {
+	return reader.expression.resolve(value)
+}
+
+// IsDynamicExpression whether value refers dynamic builtin variable (e.g. ${var.builtin.date.yyyymmdd}) or function.
+// resolved result of dynamic expression can differ on every resolve so it should not be cached
+func (reader *PropertyPredefineReader) IsDynamicExpression(value string) bool {
+	_, dynamic, _ := reader.expression.resolveDynamic(value)
+	return dynamic
+}
+
+// SetStrict fail on unresolved placeholder or not
+func (reader *PropertyPredefineReader) SetStrict(strict bool) {
+	reader.expression.strict = strict
+}
+
+func (reader *PropertyPredefineReader) IsStrict() bool {
+	return reader.expression.strict
 }
 
 func (reader *PropertyPredefineReader) GetDefine(key string) (string, bool) {
-	v, ok := reader.defines[fmt.Sprintf("${%s}", key)]
+	v, ok, err := reader.expression.lookup(key, nil, &expandState{})
+	if err != nil {
+		log.Warn("fail to resolve predefine %s : %s", key, err.Error())
+	}
 	return v, ok
 }
 
 // buildBuiltin create builtin properties
 // e.g) ${var.builtin.fatima.home}, ${var.builtin.local.ipaddress}
+// package builtins are resolved (same as GetDefine) after other builtins so that package defines can refer them
 func (reader *PropertyPredefineReader) buildBuiltin() {
 	reader.builtinVariables = make([]variableValue, 0)
 
-	reader.appendBuiltinVar(variableValue{BuiltinVariableHome, reader.env.GetSystemProc().GetHomeDir()})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableFatimaHome, reader.env.GetFolderGuide().GetFatimaHome()})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableLocalIpaddress, getDefaultIpAddress()})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableYyyymm, ""})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableYyyymmdd, ""})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableAppName, reader.env.GetSystemProc().GetProgramName()})
-	reader.appendBuiltinVar(variableValue{BuiltinVariableAppFolderData, reader.env.GetFolderGuide().GetDataFolder()})
+	proc := reader.env.GetSystemProc()
+
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableHome, value: proc.GetHomeDir()})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableFatimaHome, value: reader.env.GetFolderGuide().GetFatimaHome()})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableLocalIpaddress, value: getDefaultIpAddress()})
+	reader.appendBuiltinVar(dateVariable(BuiltinVariableYyyymm, "200601"))
+	reader.appendBuiltinVar(dateVariable(BuiltinVariableYyyymmdd, "20060102"))
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableAppName, value: proc.GetProgramName()})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableAppFolderData, value: reader.env.GetFolderGuide().GetDataFolder()})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariablePid, value: strconv.Itoa(proc.GetPid())})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableProfile, value: reader.env.GetProfile()})
+
+	hostname := reader.getPackageHostname()
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableHostname, value: hostname})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariablePackageName, value: reader.getDefineOrDefault(GlobalDefinePackageName, defaultPackageName)})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariablePackageGroup, value: reader.getDefineOrDefault(GlobalDefinePackageGroupname, defaultPackageGroup)})
+	reader.appendBuiltinVar(variableValue{key: BuiltinVariableInstanceId,
+		value: fmt.Sprintf("%s-%s-%d", hostname, proc.GetProgramName(), proc.GetPid())})
 }
 
-// prepareMatchers serving fatima global configuration properties as variable
-func (reader *PropertyPredefineReader) prepareMatchers() {
-	var matchers []string
+// getPackageHostname hostname from package global property. os hostname is used if not defined
+func (reader *PropertyPredefineReader) getPackageHostname() string {
+	if v, ok := reader.GetDefine(GlobalDefinePackageHostname); ok && len(v) > 0 {
+		return v
+	}
+	n, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return n
+}
 
-	// add builtin vars to matchers
+// getDefineOrDefault resolved package global property (same as GetDefine) or defaultValue
+func (reader *PropertyPredefineReader) getDefineOrDefault(key, defaultValue string) string {
+	if v, ok := reader.GetDefine(key); ok && len(v) > 0 {
+		return v
+	}
+	return defaultValue
+}
+
+// lookupBuiltin find builtin variable value by key (without ${ and })
+func (reader *PropertyPredefineReader) lookupBuiltin(key string) (string, bool, bool) {
 	for _, v := range reader.builtinVariables {
-		matchers = append(matchers, string(v.key))
-		matchers = append(matchers, v.getValue())
+		if string(v.key) == "${"+key+"}" {
+			return v.getValue(), v.isDynamic(), true
+		}
 	}
-	builtinReplacer := strings.NewReplacer(matchers...)
-	props, _ := readProperties(filepath.Join(reader.env.GetFolderGuide().GetConfFolder(), FatimaGlobalPredefinePropertiesFile))
+	return "", false, false
+}
 
-	// add package global properties to matchers
+// loadDefines load fatima package global properties
+func (reader *PropertyPredefineReader) loadDefines() {
+	props, _ := readProperties(filepath.Join(reader.env.GetFolderGuide().GetConfFolder(), FatimaGlobalPredefinePropertiesFile))
 	for k, v := range props {
-		keyForm := fmt.Sprintf("${%s}", k)
-
-		// we have to call 'Replace' because package global property contains 'builtin'
-		valueForm := builtinReplacer.Replace(v)
+		reader.defines[k] = v
+	}
+}
 
-		// need secret replace
-		if strings.HasSuffix(k, SecretKeySuffix) {
-			valueForm = crypt.ResolveSecret(valueForm)
+// prepareMatchers serving fatima global configuration properties as variable
+func (reader *PropertyPredefineReader) prepareMatchers() {
+	if v, ok := reader.defines[GlobalDefinePredefineStrict]; ok {
+		strict, err := ParseConfigBool(v)
+		if err != nil {
+			log.Warn("[%s] %s", GlobalDefinePredefineStrict, err.Error())
 		}
-
-		reader.defines[keyForm] = valueForm
-		matchers = append(matchers, keyForm)
-		matchers = append(matchers, valueForm)
+		reader.expression.strict = strict
 	}
 
-	// builtinReplacer : new Replacer from a list of old, new string pairs
-	reader.replacer = strings.NewReplacer(matchers...)
+	// every package global property should be resolvable (e.g. no reference cycle)
+	keys := make([]string, 0, len(reader.defines))
+	for k := range reader.defines {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, _, err := reader.expression.lookup(k, nil, &expandState{}); err != nil {
+			if reader.expression.strict {
+				check(fmt.Errorf("invalid predefine %s : %w", k, err))
+			}
+			log.Warn("invalid predefine %s : %s", k, err.Error())
+		}
+	}
 }
 
 const (
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 10:20
+ */
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyPredefineReaderPackageDefines(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(fatima.ENV_FATIMA_PROFILE, "stage")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, FatimaFolderConf), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, FatimaFolderConf, FatimaGlobalPredefinePropertiesFile), []byte(
+		"var.global.package.name=${var.builtin.profile}-pkg\n"+
+			"var.global.package.hostname=host-${var.builtin.profile}\n"+
+			"var.global.predefine.strict=Yes\n"+
+			"var.global.log.folder=${var.builtin.fatima.home}/log/${var.builtin.app.name}\n"), 0644))
+	env := NewPackageFatimaEnv(home)
+
+	reader := NewPropertyPredefineReader(env)
+	assert.True(t, reader.IsStrict())
+
+	// builtins are same as resolved defines
+	name, ok := reader.GetDefine(GlobalDefinePackageName)
+	require.True(t, ok)
+	assert.Equal(t, "stage-pkg", name)
+	assert.Equal(t, name, reader.ResolvePredefine(BuiltinVariablePackageName))
+	host, ok := reader.GetDefine(GlobalDefinePackageHostname)
+	require.True(t, ok)
+	assert.Equal(t, "host-stage", host)
+	assert.Equal(t, host, reader.ResolvePredefine(BuiltinVariableHostname))
+
+	// log folder template
+	assert.Equal(t, filepath.Join(home, "log", env.GetSystemProc().GetProgramName()), resolveLogFolder(env))
+}
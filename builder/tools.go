@@ -50,10 +50,17 @@ func ensureDirectory(path string, forceCreate bool) error {
 
 // readProperties read properties (key=value pairs)
 func readProperties(path string) (map[string]string, error) {
+	resolved, _, err := parseProperties(path)
+	return resolved, err
+}
+
+// parseProperties read properties (key=value pairs) and '@include <file>' directives
+func parseProperties(path string) (map[string]string, []string, error) {
 	resolved := make(map[string]string)
+	includes := make([]string, 0)
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer file.Close()
 
@@ -65,6 +72,12 @@ func readProperties(path string) (map[string]string, error) {
 		if strings.HasPrefix(line, "#") || len(line) < 3 {
 			continue
 		}
+		if strings.HasPrefix(line, propertiesIncludeToken+" ") {
+			if include := strings.TrimSpace(line[len(propertiesIncludeToken):]); include != "" {
+				includes = append(includes, include)
+			}
+			continue
+		}
 		idx = strings.Index(line, "#")
 		if idx > 0 {
 			if line[idx-1] == ' ' {
@@ -83,5 +96,5 @@ func readProperties(path string) (map[string]string, error) {
 		resolved[key] = val
 	}
 
-	return resolved, nil
+	return resolved, includes, nil
 }
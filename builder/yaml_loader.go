@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	log "github.com/fatima-go/fatima-log"
@@ -45,7 +46,12 @@ func newYamlLoadResult() yamlLoadResult {
 	}
 }
 
-func loadYamlFile(path string, profile string) (yamlLoadResult, error) {
+func loadYamlFile(path string, profile string, chain includeChain) (yamlLoadResult, error) {
+	chain, err := chain.enter(path)
+	if err != nil {
+		return yamlLoadResult{}, err
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return yamlLoadResult{}, err
@@ -67,7 +73,7 @@ func loadYamlFile(path string, profile string) (yamlLoadResult, error) {
 
 	if len(docs) <= 1 {
 		if len(docs) == 1 {
-			return flattenDoc(docs[0]), nil
+			return flattenDocWithIncludes(docs[0], profile, chain, path)
 		}
 		return newYamlLoadResult(), nil
 	}
@@ -78,13 +84,21 @@ func loadYamlFile(path string, profile string) (yamlLoadResult, error) {
 
 	base, matching := classifyDocs(docs, profile, path)
 	if base != nil {
-		mergeFlattened(&result, flattenDoc(base), "")
+		r, err := flattenDocWithIncludes(base, profile, chain, path)
+		if err != nil {
+			return yamlLoadResult{}, err
+		}
+		mergeFlattened(&result, r, "")
 	}
 	for i, m := range matching {
 		if i > 0 {
 			log.Warn("duplicate profile '%s' block in %s, later overrides earlier", profile, path)
 		}
-		mergeFlattened(&result, flattenDoc(m), fmt.Sprintf("profile '%s'", profile))
+		r, err := flattenDocWithIncludes(m, profile, chain, path)
+		if err != nil {
+			return yamlLoadResult{}, err
+		}
+		mergeFlattened(&result, r, fmt.Sprintf("profile '%s'", profile))
 	}
 	return result, nil
 }
@@ -138,6 +152,13 @@ func flattenDoc(doc map[string]any) yamlLoadResult {
 	return r
 }
 
+// flattenDocWithIncludes flattens a single yaml document and merges it on top of its include fragments.
+// include fragments have lower precedence than the document itself
+func flattenDocWithIncludes(doc map[string]any, profile string, chain includeChain, path string) (yamlLoadResult, error) {
+	includes := extractAndStripIncludes(doc)
+	return mergeWithIncludes(flattenDoc(doc), includes, profile, chain, filepath.Base(path))
+}
+
 // mergeFlattened merges src into dst. For each key in src whose value differs from
 // the existing value in dst, logs INFO with sourceLabel before overwriting.
 // Pass an empty sourceLabel when merging a base with no prior content (no overrides expected).
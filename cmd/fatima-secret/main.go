@@ -0,0 +1,361 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 21. 오후 2:10
+ */
+
+// fatima-secret manages '.secret' values of fatima config files
+//
+//	fatima-secret keygen  [-id k1]
+//	fatima-secret encrypt [-scheme native2|native|b64] [-profile dev] [-key k1] [-keyfile path] [value]
+//	fatima-secret rotate  [-key k2] [-keyfile path] [-profile dev] [-plain] [-dry-run] file...
+//	fatima-secret audit   [-profile dev] file...
+//
+// files are application.(yaml|yml|properties) or fatima-package-predefine.properties
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatima-go/fatima-core/crypt"
+	"github.com/fatima-go/fatima-core/lib"
+)
+
+const (
+	exitOk       = 0
+	exitFail     = 1
+	exitUsage    = 2
+	defaultKeyId = "k1"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	var err error
+	code := exitOk
+	switch args[0] {
+	case "keygen":
+		err = runKeygen(args[1:], stdout)
+	case "encrypt":
+		err = runEncrypt(args[1:], stdin, stdout)
+	case "rotate":
+		err = runRotate(args[1:], stdout)
+	case "audit":
+		code, err = runAudit(args[1:], stdout)
+	case "help", "-h", "--help":
+		usage(stdout)
+		return exitOk
+	default:
+		fmt.Fprintf(stderr, "unknown command : %s\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitUsage
+		}
+		fmt.Fprintf(stderr, "%s : %s\n", args[0], err.Error())
+		return exitFail
+	}
+	return code
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `usage : fatima-secret <command> [options]
+
+commands
+  keygen   create random AES-256 key line for keyfile ($FATIMA_HOME/conf/fatima-secret.keys)
+  encrypt  encrypt value (or first line of stdin) and print secret string
+  rotate   re-encrypt every '.secret' key in config files with active (or -key) native2 key
+  audit    report '.secret' keys which look like plaintext or use weak scheme`)
+}
+
+// keyOptions common options to select native2 key
+type keyOptions struct {
+	keyfile string
+	keyId   string
+	profile string
+}
+
+func (o *keyOptions) bind(fs *flag.FlagSet) {
+	fs.StringVar(&o.keyfile, "keyfile", "", "native2 keyfile path. default $FATIMA_HOME/conf/"+crypt.SecretKeyfileName)
+	fs.StringVar(&o.keyId, "key", "", "native2 key id for encryption. default active key")
+	fs.StringVar(&o.profile, "profile", "", "profile of legacy native scheme. default $FATIMA_PROFILE")
+}
+
+// apply load keyring and register legacy native provider for profile
+func (o *keyOptions) apply() error {
+	if len(o.keyfile) > 0 {
+		if err := os.Setenv(crypt.EnvSecretKeyfile, o.keyfile); err != nil {
+			return err
+		}
+	}
+	crypt.SetSecretKeyring(nil)
+
+	if len(o.profile) > 0 {
+		profile := o.profile
+		err := crypt.RegisterSecretProvider(crypt.SecretSchemeNative, crypt.SecretProviderFunc(func(secret string) (string, error) {
+			return crypt.DecryptSecretNativeForProfile(secret, profile)
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(o.keyId) == 0 {
+		return nil
+	}
+	keyring, err := crypt.GetSecretKeyring()
+	if err != nil {
+		return err
+	}
+	return keyring.SetActive(o.keyId)
+}
+
+func runKeygen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	keyId := fs.String("id", defaultKeyId, "key id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, err := crypt.GenerateSecretKey()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s=%s\n", *keyId, key)
+	return nil
+}
+
+func runEncrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	scheme := fs.String("scheme", crypt.SecretSchemeNative2, "secret scheme (native2, native, b64)")
+	var opts keyOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var value string
+	if fs.NArg() > 0 {
+		value = fs.Arg(0)
+	} else {
+		// read from stdin not to leave plaintext in shell history
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		value = strings.TrimRight(line, "\r\n")
+	}
+	if len(value) == 0 {
+		return errors.New("empty value")
+	}
+
+	var secret string
+	var err error
+	switch *scheme {
+	case crypt.SecretSchemeNative2:
+		if err = opts.apply(); err != nil {
+			return err
+		}
+		secret, err = crypt.CreateSecretNative2(value)
+	case crypt.SecretSchemeNative:
+		profile := opts.profile
+		if len(profile) == 0 {
+			profile = os.Getenv("FATIMA_PROFILE")
+		}
+		if len(profile) == 0 {
+			return errors.New("profile is required for native scheme. use -profile")
+		}
+		secret, err = crypt.CreateSecretNativeForProfile(value, profile)
+	case crypt.SecretSchemeB64:
+		secret = crypt.CreateSecretBase64(value)
+	default:
+		return fmt.Errorf("unsupported scheme for encryption : %s", *scheme)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, secret)
+	return nil
+}
+
+// embedded schemes hold cipher in config file. other schemes (file, env, vault, aws) are references
+var embeddedSecretSchemes = map[string]bool{
+	crypt.SecretSchemeNative:  true,
+	crypt.SecretSchemeNative2: true,
+	crypt.SecretSchemeB64:     true,
+}
+
+func runRotate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("rotate", flag.ContinueOnError)
+	var opts keyOptions
+	opts.bind(fs)
+	plain := fs.Bool("plain", false, "encrypt plaintext values too")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no file")
+	}
+	if err := opts.apply(); err != nil {
+		return err
+	}
+	keyring, err := crypt.GetSecretKeyring()
+	if err != nil {
+		return err
+	}
+	activePrefix := fmt.Sprintf("%s:%s:", crypt.SecretSchemeNative2, keyring.GetActive())
+
+	for _, path := range fs.Args() {
+		count := 0
+		data, edited, err := editSecretFile(path, func(entry secretEntry) (string, bool, error) {
+			if len(entry.value) == 0 || strings.HasPrefix(entry.value, activePrefix) {
+				return entry.value, false, nil
+			}
+
+			var rotated string
+			var err error
+			if isEmbeddedSecret(entry.value) {
+				rotated, err = crypt.ReencryptSecret(entry.value)
+			} else if *plain && !isSecretReference(entry.value) {
+				rotated, err = crypt.CreateSecretNative2(entry.value)
+			} else {
+				return entry.value, false, nil
+			}
+			if err != nil {
+				return "", false, err
+			}
+			count++
+			if *dryRun {
+				fmt.Fprintf(stdout, "%s: %s would re-encrypt\n", path, entry.key)
+			} else {
+				fmt.Fprintf(stdout, "%s: %s re-encrypted\n", path, entry.key)
+			}
+			return rotated, true, nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s : %w", path, err)
+		}
+		if !edited || *dryRun {
+			fmt.Fprintf(stdout, "%s: %d key(s) to rotate\n", path, count)
+			continue
+		}
+		if err = lib.WriteFileAtomic(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s: %d key(s) rotated\n", path, count)
+	}
+	return nil
+}
+
+func runAudit(args []string, stdout io.Writer) (int, error) {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	var opts keyOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() == 0 {
+		return exitUsage, errors.New("no file")
+	}
+	if err := opts.apply(); err != nil {
+		return exitFail, err
+	}
+
+	code := exitOk
+	for _, path := range fs.Args() {
+		entries, err := readSecretEntries(path)
+		if err != nil {
+			return exitFail, fmt.Errorf("%s : %w", path, err)
+		}
+		for _, entry := range entries {
+			finding, severe := auditSecret(entry.value)
+			if len(finding) == 0 {
+				continue
+			}
+			if severe {
+				code = exitFail
+			}
+			fmt.Fprintf(stdout, "%s: %s: %s\n", path, entry.key, finding)
+		}
+	}
+	return code, nil
+}
+
+// auditSecret return finding of secret value. severe is true when value looks like plaintext or cannot be resolved
+func auditSecret(value string) (string, bool) {
+	if len(value) == 0 || strings.Contains(value, "${") {
+		return "", false
+	}
+
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok || len(scheme) == 0 {
+		return "plaintext value", true
+	}
+	if _, registered := crypt.GetSecretProvider(scheme); !registered {
+		return "plaintext value (unknown scheme " + scheme + ")", true
+	}
+
+	switch scheme {
+	case crypt.SecretSchemeB64:
+		return "b64 is encoding, not encryption. rotate to native2", false
+	case crypt.SecretSchemeNative:
+		if _, err := crypt.ResolveSecretWithError(value); err != nil {
+			return "cannot decrypt : " + err.Error(), true
+		}
+		return "legacy native scheme. rotate to native2", false
+	case crypt.SecretSchemeNative2:
+		if _, err := crypt.ResolveSecretWithError(value); err != nil {
+			return "cannot decrypt : " + err.Error(), true
+		}
+	}
+	return "", false
+}
+
+func isEmbeddedSecret(value string) bool {
+	scheme, _, ok := strings.Cut(value, ":")
+	return ok && embeddedSecretSchemes[scheme]
+}
+
+func isSecretReference(value string) bool {
+	if strings.Contains(value, "${") {
+		return true
+	}
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok {
+		return false
+	}
+	_, registered := crypt.GetSecretProvider(scheme)
+	return registered
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 21. 오후 4:30
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareKeyfile create keyfile with k1 (active) and k2
+func prepareKeyfile(t *testing.T) string {
+	dir := t.TempDir()
+	keyfile := filepath.Join(dir, crypt.SecretKeyfileName)
+	var out bytes.Buffer
+	require.Equal(t, exitOk, run([]string{"keygen", "-id", "k1"}, nil, &out, &out))
+	require.Equal(t, exitOk, run([]string{"keygen", "-id", "k2"}, nil, &out, &out))
+	require.NoError(t, os.WriteFile(keyfile, []byte("active=k1\n"+out.String()), 0600))
+	t.Setenv(crypt.EnvSecretKey, "")
+	t.Cleanup(func() { crypt.SetSecretKeyring(nil) })
+	return keyfile
+}
+
+func TestEncrypt(t *testing.T) {
+	keyfile := prepareKeyfile(t)
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"encrypt", "-keyfile", keyfile, "-key", "k2"}, strings.NewReader("p@ssword\n"), &out, &errOut)
+	require.Equal(t, exitOk, code, errOut.String())
+	secret := strings.TrimSpace(out.String())
+	assert.True(t, strings.HasPrefix(secret, "native2:k2:"))
+	assert.Equal(t, "p@ssword", crypt.ResolveSecret(secret))
+
+	out.Reset()
+	code = run([]string{"encrypt", "-scheme", "native", "-profile", "prod", "p@ssword"}, nil, &out, &errOut)
+	require.Equal(t, exitOk, code, errOut.String())
+	content := strings.TrimPrefix(strings.TrimSpace(out.String()), crypt.SecretSchemeNative+":")
+	plain, err := crypt.DecryptSecretNativeForProfile(content, "prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "p@ssword", plain)
+
+	assert.Equal(t, exitFail, run([]string{"encrypt", "-scheme", "rot13", "x"}, nil, &out, &errOut))
+	assert.Equal(t, exitUsage, run([]string{"unknown"}, nil, &out, &errOut))
+}
+
+func TestRotateAndAudit(t *testing.T) {
+	keyfile := prepareKeyfile(t)
+	legacy, err := crypt.CreateSecretNativeForProfile("legacy-pw", "prod")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	props := filepath.Join(dir, "fatima-package-predefine.properties")
+	require.NoError(t, os.WriteFile(props, []byte(
+		"# predefine\n"+
+			"var.db.user=fatima\n"+
+			"var.db.password.secret = "+legacy+" # legacy\n"+
+			"var.api.token.secret=plain-token\n"+
+			"var.vault.secret=vault:secret/db#pw\n"), 0640))
+	yml := filepath.Join(dir, "application.yaml")
+	require.NoError(t, os.WriteFile(yml, []byte(
+		"db:\n  password.secret: "+crypt.CreateSecretBase64("b64-pw")+"\n  host: localhost\n"+
+			"---\nfatima:\n  profile: prod\ndb:\n  password.secret: "+legacy+"\n"), 0600))
+
+	// audit before rotation
+	var out, errOut bytes.Buffer
+	code := run([]string{"audit", "-profile", "prod", props, yml}, nil, &out, &errOut)
+	assert.Equal(t, exitFail, code)
+	report := out.String()
+	assert.Contains(t, report, "var.db.password.secret: legacy native scheme")
+	assert.Contains(t, report, "var.api.token.secret: plaintext value")
+	assert.Contains(t, report, "db.password.secret: b64 is encoding")
+	assert.NotContains(t, report, "var.vault.secret")
+	assert.NotContains(t, report, "plain-token", "audit should not print secret value")
+
+	// dry run does not write
+	before, _ := os.ReadFile(props)
+	out.Reset()
+	code = run([]string{"rotate", "-keyfile", keyfile, "-profile", "prod", "-dry-run", props}, nil, &out, &errOut)
+	require.Equal(t, exitOk, code, errOut.String())
+	after, _ := os.ReadFile(props)
+	assert.Equal(t, before, after)
+	assert.Contains(t, out.String(), "would re-encrypt")
+	assert.NotContains(t, out.String(), "re-encrypted")
+
+	out.Reset()
+	code = run([]string{"rotate", "-keyfile", keyfile, "-key", "k2", "-profile", "prod", props, yml}, nil, &out, &errOut)
+	require.Equal(t, exitOk, code, errOut.String())
+
+	propsData, _ := os.ReadFile(props)
+	assert.Contains(t, string(propsData), "var.db.user=fatima\n")
+	assert.Contains(t, string(propsData), "var.api.token.secret=plain-token\n", "plaintext is kept without -plain")
+	assert.Contains(t, string(propsData), "var.vault.secret=vault:secret/db#pw\n")
+	assert.Contains(t, string(propsData), " # legacy\n")
+	info, _ := os.Stat(props)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	entries, err := readSecretEntries(props)
+	require.NoError(t, err)
+	for _, e := range entries {
+		if e.key == "var.db.password.secret" {
+			assert.True(t, strings.HasPrefix(e.value, "native2:k2:"))
+			assert.Equal(t, "legacy-pw", crypt.ResolveSecret(e.value))
+		}
+	}
+
+	entries, err = readSecretEntries(yml)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "b64-pw", crypt.ResolveSecret(entries[0].value))
+	assert.Equal(t, "legacy-pw", crypt.ResolveSecret(entries[1].value))
+	for _, e := range entries {
+		assert.True(t, strings.HasPrefix(e.value, "native2:k2:"))
+	}
+
+	// -plain encrypts plaintext values
+	out.Reset()
+	code = run([]string{"rotate", "-keyfile", keyfile, "-plain", props}, nil, &out, &errOut)
+	require.Equal(t, exitOk, code, errOut.String())
+	out.Reset()
+	code = run([]string{"audit", props, yml}, nil, &out, &errOut)
+	assert.Equal(t, exitOk, code, out.String())
+}
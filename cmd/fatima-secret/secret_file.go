@@ -0,0 +1,198 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 21. 오후 3:00
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const secretKeySuffix = ".secret"
+
+// secretEntry '.secret' key in config file
+type secretEntry struct {
+	key   string
+	value string
+}
+
+// secretEditFunc return new value of secret entry. changed false means leaving value as it is
+type secretEditFunc func(entry secretEntry) (value string, changed bool, err error)
+
+// readSecretEntries find every '.secret' key in config file (properties or yaml)
+func readSecretEntries(path string) ([]secretEntry, error) {
+	entries := make([]secretEntry, 0)
+	_, _, err := editSecretFile(path, func(entry secretEntry) (string, bool, error) {
+		entries = append(entries, entry)
+		return entry.value, false, nil
+	})
+	return entries, err
+}
+
+// editSecretFile apply edit to every '.secret' key and return edited file content.
+// edited is false when nothing changed
+func editSecretFile(path string, edit secretEditFunc) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".properties":
+		return editSecretProperties(data, edit)
+	case ".yaml", ".yml":
+		return editSecretYaml(data, edit)
+	}
+	return nil, false, fmt.Errorf("unsupported file format : %s", path)
+}
+
+// editSecretProperties edit properties line by line. layout and comments are preserved
+func editSecretProperties(data []byte, edit secretEditFunc) ([]byte, bool, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	edited := false
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, "\r\n")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "@") {
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx < 1 {
+			continue
+		}
+		key := strings.TrimSpace(line[:idx])
+		if !strings.HasSuffix(key, secretKeySuffix) {
+			continue
+		}
+
+		rest := line[idx+1:]
+		comment := ""
+		if c := strings.Index(rest, " #"); c >= 0 {
+			comment = rest[c:]
+			rest = rest[:c]
+		}
+		value := strings.TrimSpace(rest)
+		leading := rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+
+		v, changed, err := edit(secretEntry{key: key, value: value})
+		if err != nil {
+			return nil, false, fmt.Errorf("%s : %w", key, err)
+		}
+		if !changed {
+			continue
+		}
+		lines[i] = line[:idx+1] + leading + v + comment + raw[len(line):]
+		edited = true
+	}
+	return []byte(strings.Join(lines, "")), edited, nil
+}
+
+// editSecretYaml edit yaml documents. key is flattened with '.' like application config loader
+func editSecretYaml(data []byte, edit secretEditFunc) ([]byte, bool, error) {
+	docs := make([]*yaml.Node, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		docs = append(docs, &doc)
+	}
+
+	edited := false
+	for _, doc := range docs {
+		changed, err := editSecretYamlNode("", doc, edit)
+		if err != nil {
+			return nil, false, err
+		}
+		edited = edited || changed
+	}
+	if !edited {
+		return data, false, nil
+	}
+
+	var buff bytes.Buffer
+	enc := yaml.NewEncoder(&buff)
+	enc.SetIndent(2)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, false, err
+	}
+	return buff.Bytes(), true, nil
+}
+
+func editSecretYamlNode(prefix string, node *yaml.Node, edit secretEditFunc) (bool, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		edited := false
+		for _, c := range node.Content {
+			changed, err := editSecretYamlNode(prefix, c, edit)
+			if err != nil {
+				return false, err
+			}
+			edited = edited || changed
+		}
+		return edited, nil
+	case yaml.MappingNode:
+		edited := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if len(prefix) > 0 {
+				key = prefix + "." + key
+			}
+			value := node.Content[i+1]
+			if value.Kind == yaml.ScalarNode {
+				if !strings.HasSuffix(key, secretKeySuffix) {
+					continue
+				}
+				v, changed, err := edit(secretEntry{key: key, value: value.Value})
+				if err != nil {
+					return false, fmt.Errorf("%s : %w", key, err)
+				}
+				if changed {
+					value.Value = v
+					value.Style = 0
+					edited = true
+				}
+				continue
+			}
+			changed, err := editSecretYamlNode(key, value, edit)
+			if err != nil {
+				return false, err
+			}
+			edited = edited || changed
+		}
+		return edited, nil
+	}
+	return false, nil
+}
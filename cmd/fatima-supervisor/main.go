@@ -0,0 +1,323 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 25. 오후 4:00
+ */
+
+// fatima-supervisor runs processes of fatima package ($FATIMA_HOME/conf/fatima-package.yaml) without juno.
+// FATIMA_HOME should be set because builder package is loaded at init
+//
+//	fatima-supervisor run    [-ha active|standby] [-ps primary|secondary]
+//	fatima-supervisor start  [-ha ...] [-ps ...] <all|group|process>
+//	fatima-supervisor stop   <all|group|process>
+//	fatima-supervisor status [-json] [all|group|process]
+//	fatima-supervisor plan   [-ha ...] [-ps ...] <start|stop> [group]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-core/supervisor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	exitOk    = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	var err error
+	switch args[0] {
+	case "run":
+		err = runSupervise(args[1:])
+	case "start":
+		err = runStart(args[1:])
+	case "stop":
+		err = runStop(args[1:])
+	case "status":
+		err = runStatus(args[1:], stdout)
+	case "plan":
+		err = runPlan(args[1:], stdout)
+	case "help", "-h", "--help":
+		usage(stdout)
+		return exitOk
+	default:
+		fmt.Fprintf(stderr, "unknown command : %s\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitUsage
+		}
+		fmt.Fprintf(stderr, "%s : %s\n", args[0], err.Error())
+		return exitFail
+	}
+	return exitOk
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `usage : fatima-supervisor <command> [options]
+
+commands
+  run     start every process and supervise (restart crashed process) until SIGINT/SIGTERM
+  start   start all, group or process and exit
+  stop    stop all, group or process
+  status  print process status
+  plan    print start/stop plan (dry-run)`)
+}
+
+// commonOptions options to load package and system status
+type commonOptions struct {
+	home string
+	ha   string
+	ps   string
+}
+
+func (o *commonOptions) bind(fs *flag.FlagSet) {
+	fs.StringVar(&o.ha, "ha", "", "HA status (active, standby) for startmode 2 processes")
+	fs.StringVar(&o.ps, "ps", "", "PS status (primary, secondary) for startmode 3 processes")
+}
+
+func (o *commonOptions) load(detach bool) (*supervisor.Supervisor, *builder.YamlFatimaPackageConfig, error) {
+	o.home = os.Getenv(fatima.ENV_FATIMA_HOME)
+	if len(o.home) == 0 {
+		return nil, nil, errors.New("FATIMA_HOME is not set")
+	}
+	config, err := builder.LoadYamlFatimaPackageConfig(builder.NewPackageFatimaEnv(o.home))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, err := o.status()
+	if err != nil {
+		return nil, nil, err
+	}
+	return supervisor.New(o.home, config, supervisor.Option{Status: status, Detach: detach}), config, nil
+}
+
+// status return system status given by -ha, -ps. nil when both are not given
+func (o *commonOptions) status() (monitor.FatimaSystemStatus, error) {
+	if len(o.ha) == 0 && len(o.ps) == 0 {
+		return nil, nil
+	}
+	status := systemStatus{}
+	switch o.ha {
+	case "", "standby":
+		status.ha = monitor.HA_STATUS_STANDBY
+	case "active":
+		status.ha = monitor.HA_STATUS_ACTIVE
+	default:
+		return nil, fmt.Errorf("invalid ha status : %s", o.ha)
+	}
+	switch o.ps {
+	case "", "secondary":
+		status.ps = monitor.PS_STATUS_SECONDARY
+	case "primary":
+		status.ps = monitor.PS_STATUS_PRIMARY
+	default:
+		return nil, fmt.Errorf("invalid ps status : %s", o.ps)
+	}
+	return status, nil
+}
+
+// systemStatus static system status given by command line
+type systemStatus struct {
+	ha monitor.HAStatus
+	ps monitor.PSStatus
+}
+
+func (s systemStatus) GetPSStatus() monitor.PSStatus { return s.ps }
+func (s systemStatus) GetHAStatus() monitor.HAStatus { return s.ha }
+func (s systemStatus) IsActive() bool                { return s.ha == monitor.HA_STATUS_ACTIVE }
+func (s systemStatus) IsPrimary() bool               { return s.ps == monitor.PS_STATUS_PRIMARY }
+
+func targetArg(fs *flag.FlagSet, required bool) (string, error) {
+	if fs.NArg() == 0 {
+		if required {
+			return "", errors.New("target (all, group or process) is required")
+		}
+		return supervisor.TargetAll, nil
+	}
+	return fs.Arg(0), nil
+}
+
+func runSupervise(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	var opts commonOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, _, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err = s.Start(ctx, supervisor.TargetAll); err != nil {
+		log.Warn("%s", err.Error())
+	}
+	_ = s.Run(ctx)
+
+	log.Warn("supervisor is stopping")
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer stopCancel()
+	s.Close()
+	return s.Stop(stopCtx, supervisor.TargetAll)
+}
+
+func runStart(args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	var opts commonOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target, err := targetArg(fs, true)
+	if err != nil {
+		return err
+	}
+	s, _, err := opts.load(true)
+	if err != nil {
+		return err
+	}
+	return s.Start(context.Background(), target)
+}
+
+func runStop(args []string) error {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	var opts commonOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target, err := targetArg(fs, true)
+	if err != nil {
+		return err
+	}
+	s, _, err := opts.load(true)
+	if err != nil {
+		return err
+	}
+	return s.Stop(context.Background(), target)
+}
+
+func runStatus(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	var opts commonOptions
+	opts.bind(fs)
+	asJson := fs.Bool("json", false, "print as json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target, err := targetArg(fs, false)
+	if err != nil {
+		return err
+	}
+	s, _, err := opts.load(true)
+	if err != nil {
+		return err
+	}
+	list, err := s.Status(target)
+	if err != nil {
+		return err
+	}
+
+	if *asJson {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tGROUP\tPID\tSTATE")
+	for _, st := range list {
+		pid := "-"
+		if st.Pid > 0 {
+			pid = fmt.Sprintf("%d", st.Pid)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.Name, st.Group, pid, st.State)
+	}
+	return w.Flush()
+}
+
+func runPlan(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var opts commonOptions
+	opts.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("start or stop is required")
+	}
+	_, config, err := opts.load(true)
+	if err != nil {
+		return err
+	}
+
+	status, err := opts.status()
+	if err != nil {
+		return err
+	}
+	option := builder.ProcessPlanOption{Status: status}
+	if fs.NArg() > 1 && fs.Arg(1) != supervisor.TargetAll {
+		option.Group = fs.Arg(1)
+	}
+
+	var plan builder.ProcessPlan
+	switch fs.Arg(0) {
+	case builder.ProcessPlanStart:
+		plan, err = config.PlanStart(option)
+	case builder.ProcessPlanStop:
+		plan, err = config.PlanStop(option)
+	default:
+		return fmt.Errorf("unknown plan : %s", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, plan.String())
+	return nil
+}
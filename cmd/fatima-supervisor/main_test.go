@@ -0,0 +1,144 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 25. 오후 4:30
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareHome(t *testing.T) string {
+	home := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "conf"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "conf", "fatima-package.yaml"), []byte(`group:
+  - id: 1
+    name: OPM
+  - id: 2
+    name: SVC
+process:
+  - gid: 1
+    name: jupiter
+    startsec: 2
+  - gid: 2
+    name: api
+  - gid: 2
+    name: ha-worker
+    startmode: 2
+`), 0644))
+	t.Setenv(fatima.ENV_FATIMA_HOME, home)
+	return home
+}
+
+func TestPlan(t *testing.T) {
+	prepareHome(t)
+
+	var out, errOut bytes.Buffer
+	require.Equal(t, exitOk, run([]string{"plan", "start"}, &out, &errOut), errOut.String())
+	assert.Equal(t, "start plan : 2 wave(s)\n"+
+		"wave 1 : jupiter(OPM) [wait 2s]\n"+
+		"wave 2 : api(SVC)\n"+
+		"skip ha-worker : startmode HA. system status unknown\n", out.String())
+
+	out.Reset()
+	require.Equal(t, exitOk, run([]string{"plan", "-ha", "active", "start", "svc"}, &out, &errOut), errOut.String())
+	assert.Equal(t, "start plan : 1 wave(s)\nwave 1 : api(SVC), ha-worker(SVC)\n", out.String())
+
+	assert.Equal(t, exitFail, run([]string{"plan", "-ha", "busy", "start"}, &out, &errOut))
+	assert.Equal(t, exitFail, run([]string{"plan", "restart"}, &out, &errOut))
+}
+
+func TestStatus(t *testing.T) {
+	prepareHome(t)
+
+	var out, errOut bytes.Buffer
+	require.Equal(t, exitOk, run([]string{"status", "-json", "svc"}, &out, &errOut), errOut.String())
+	var list []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, "api", list[0]["name"])
+	assert.Equal(t, "stopped", list[0]["state"])
+
+	assert.Equal(t, exitFail, run([]string{"status", "nothing"}, &out, &errOut))
+	assert.Equal(t, exitFail, run([]string{"stop"}, &out, &errOut), "target is required")
+	assert.Equal(t, exitUsage, run([]string{"unknown"}, &out, &errOut))
+}
+
+// envSupervisorMain makes the test binary act as fatima-supervisor when it is re-executed by a test
+const envSupervisorMain = "FATIMA_SUPERVISOR_TEST_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envSupervisorMain) == "1" {
+		os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	os.Exit(m.Run())
+}
+
+// supervisorCommand re-execute test binary as fatima-supervisor. executable is linked
+// with short name so that /proc comm (15 chars) matches program name as the real binary
+func supervisorCommand(t *testing.T, args ...string) *exec.Cmd {
+	executable := filepath.Join(t.TempDir(), "supervisor")
+	require.NoError(t, os.Symlink(os.Args[0], executable))
+	cmd := exec.Command(executable, args...)
+	cmd.Env = append(os.Environ(), envSupervisorMain+"=1")
+	return cmd
+}
+
+func TestStatusWhileRunning(t *testing.T) {
+	prepareHome(t)
+
+	supervise := supervisorCommand(t, "run")
+	require.NoError(t, supervise.Start())
+	exited := make(chan error, 1)
+	go func() { exited <- supervise.Wait() }()
+	defer func() {
+		_ = supervise.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			_ = supervise.Process.Kill()
+		}
+	}()
+
+	// give run command time to be active
+	select {
+	case err := <-exited:
+		t.Fatalf("run exited early : %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	var out, errOut bytes.Buffer
+	status := supervisorCommand(t, "status", "svc")
+	status.Stdout, status.Stderr = &out, &errOut
+	require.NoError(t, status.Run(), errOut.String())
+	assert.NotContains(t, errOut.String(), "already")
+	assert.Contains(t, out.String(), "NAME")
+	assert.Contains(t, out.String(), "api")
+}
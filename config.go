@@ -20,6 +20,8 @@
 
 package fatima
 
+import "time"
+
 type Predefines interface {
 	ResolvePredefine(value string) string
 	GetDefine(key string) (string, bool)
@@ -29,8 +31,26 @@ type Config interface {
 	GetValue(key string) (string, bool)
 	GetString(key string) (string, error)
 	GetInt(key string) (int, error)
+	GetInt64(key string) (int64, error)
+	GetFloat(key string) (float64, error)
+	// GetBool true/yes/y/on/1 or false/no/n/off/0 (case-insensitive). other value is error
 	GetBool(key string) (bool, error)
+	// GetDuration e.g) 500ms, 30s, 1h30m, 7d
+	GetDuration(key string) (time.Duration, error)
+	// GetBytes byte size. e.g) 512, 64KB, 30MB, 1.5GB (KB=1024)
+	GetBytes(key string) (int64, error)
 	GetList(key string) ([]string, error)
+	// GetStringMap key/values under prefix. prefix is removed from key. e.g) db => host, port
+	GetStringMap(prefix string) map[string]string
+
+	// Get*OrDefault return defaultValue if key is not found or value is invalid
+	GetStringOrDefault(key string, defaultValue string) string
+	GetIntOrDefault(key string, defaultValue int) int
+	GetInt64OrDefault(key string, defaultValue int64) int64
+	GetFloatOrDefault(key string, defaultValue float64) float64
+	GetBoolOrDefault(key string, defaultValue bool) bool
+	GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration
+	GetBytesOrDefault(key string, defaultValue int64) int64
 }
 
 type Packaging interface {
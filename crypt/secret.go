@@ -25,6 +25,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -45,56 +46,42 @@ func CreateSecretBase64(src string) string {
 }
 
 // CreateSecretNative native 스킴으로 암호화
+//
+// Deprecated: native 스킴은 profile 에서 유도한 고정 key/iv 를 사용한다. CreateSecretNative2 를 사용
 func CreateSecretNative(src string) string {
 	return fmt.Sprintf("%s:%s", SecretSchemeNative, secretEncryptNative(src))
 }
 
-// ResolveSecret resolve secret(with scheme) string
+// ResolveSecret resolve secret(with scheme) string using registered SecretProvider.
+// src is returned as it is if there is no scheme or scheme is not registered
 func ResolveSecret(src string) string {
 	if len(src) == 0 {
 		return src
 	}
 
-	idx := strings.Index(src, ":")
-	if idx < 1 || (len(src) <= idx+1) {
-		// empty scheme or content
-		return src
-	}
-
-	scheme := src[:idx]
-	secret := src[idx+1:]
-
-	switch scheme {
-	case SecretSchemeNative:
-		return secretDecryptFuncNative(secret)
-	case SecretSchemeB64:
-		return secretDecryptFuncB64(secret)
-	case SecretSchemeAWS:
-		return secretDecryptFuncAWS(secret)
+	v, err := ResolveSecretWithError(src)
+	if err != nil {
+		if err != errSecretSchemeNotFound {
+			log.Warn("%s", err.Error())
+		}
 	}
-
-	log.Warn("scheme %s is not supported", scheme)
-	return src
+	return v
 }
 
 type SecretDecryptFunc func(string) string
 
-var secretDecryptFuncNative = secretDecryptNative
-var secretDecryptFuncB64 = secretDecryptB64
-var secretDecryptFuncAWS = secretDecryptAWS
-
+// SetSecretDecryptFunc replace decrypt function of builtin scheme (native, b64, aws)
+//
+// Deprecated: use RegisterSecretProvider
 func SetSecretDecryptFunc(schemeName string, decryptFunc SecretDecryptFunc) error {
 	switch schemeName {
-	case SecretSchemeNative:
-		secretDecryptFuncNative = decryptFunc
-	case SecretSchemeB64:
-		secretDecryptFuncB64 = decryptFunc
-	case SecretSchemeAWS:
-		secretDecryptFuncAWS = decryptFunc
+	case SecretSchemeNative, SecretSchemeB64, SecretSchemeAWS:
 	default:
 		return fmt.Errorf("invalid secret scheme %s", schemeName)
 	}
-	return nil
+	return RegisterSecretProvider(schemeName, SecretProviderFunc(func(secret string) (string, error) {
+		return decryptFunc(secret), nil
+	}))
 }
 
 var cipherKeyByteFromProfile []byte
@@ -111,50 +98,101 @@ func init() {
 	if len(envProfile) == 0 {
 		envProfile = "LOCAL"
 	}
-	profile := []byte(strings.ToLower(envProfile))
-	cipherKeyByteFromProfile = make([]byte, CipherKeyBytesLength)
-	validLen := len(profile)
+	cipherKeyByteFromProfile = nativeCipherKey(envProfile)
+	cipherIVKeyByteFromProfile = cipherKeyByteFromProfile[:CipherIVKeyLen]
+}
+
+// nativeCipherKey derive legacy native scheme key from profile
+func nativeCipherKey(profile string) []byte {
+	b := []byte(strings.ToLower(profile))
+	key := make([]byte, CipherKeyBytesLength)
+	validLen := len(b)
 	if validLen > CipherKeyBytesLength {
 		validLen = CipherKeyBytesLength
 	}
-	copy(cipherKeyByteFromProfile, profile[:validLen])
-	cipherIVKeyByteFromProfile = cipherKeyByteFromProfile[:CipherIVKeyLen]
+	copy(key, b[:validLen])
+	return key
+}
+
+// CreateSecretNativeForProfile native 스킴으로 암호화 (지정한 profile 의 key 사용)
+//
+// Deprecated: CreateSecretNative2 를 사용
+func CreateSecretNativeForProfile(src, profile string) (string, error) {
+	key := nativeCipherKey(profile)
+	encrypted, err := encryptNativeWithKey(src, key, key[:CipherIVKeyLen])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", SecretSchemeNative, encrypted), nil
+}
+
+// DecryptSecretNativeForProfile decrypt native scheme content (without scheme) with key of profile
+func DecryptSecretNativeForProfile(secret, profile string) (string, error) {
+	key := nativeCipherKey(profile)
+	return decryptNativeWithKey(secret, key, key[:CipherIVKeyLen])
 }
 
 func secretDecryptNative(src string) string {
-	ciphertextBytes, err := base64.StdEncoding.DecodeString(src)
+	v, err := decryptNative(src)
 	if err != nil {
-		log.Warn("cipher [%s] is invalid base64 format : %s", src, err.Error())
+		log.Warn("%s", err.Error())
 		return src
 	}
+	return v
+}
+
+func decryptNative(src string) (string, error) {
+	return decryptNativeWithKey(src, cipherKeyByteFromProfile, cipherIVKeyByteFromProfile)
+}
+
+func decryptNativeWithKey(src string, key, iv []byte) (string, error) {
+	ciphertextBytes, err := base64.StdEncoding.DecodeString(src)
+	if err != nil {
+		return "", fmt.Errorf("cipher [%s] is invalid base64 format : %s", src, err.Error())
+	}
 
-	cipherBlock, err := aes.NewCipher(cipherKeyByteFromProfile)
+	cipherBlock, err := aes.NewCipher(key)
 	if err != nil {
-		log.Warn("creating cipher error. key=%v, error=%s", cipherKeyByteFromProfile, err.Error())
-		return src
+		return "", fmt.Errorf("creating cipher error : %s", err.Error())
+	}
+
+	if len(ciphertextBytes) == 0 || len(ciphertextBytes)%cipherBlock.BlockSize() != 0 {
+		return "", fmt.Errorf("cipher [%s] is not multiple of block size", src)
 	}
 
 	//goland:noinspection SpellCheckingInspection
-	cbcDecryptor := cipher.NewCBCDecrypter(cipherBlock, cipherIVKeyByteFromProfile)
+	cbcDecryptor := cipher.NewCBCDecrypter(cipherBlock, iv)
 	plaintextBytes := make([]byte, len(ciphertextBytes))
 
 	cbcDecryptor.CryptBlocks(plaintextBytes, ciphertextBytes)
-	return string(unpad(plaintextBytes))
+	unpadded, err := unpad(plaintextBytes)
+	if err != nil {
+		return "", fmt.Errorf("cipher [%s] decryption error : %s", src, err.Error())
+	}
+	return string(unpadded), nil
 }
 
 func secretEncryptNative(src string) string {
-	cipherBlock, err := aes.NewCipher(cipherKeyByteFromProfile)
+	encrypted, err := encryptNativeWithKey(src, cipherKeyByteFromProfile, cipherIVKeyByteFromProfile)
 	if err != nil {
-		log.Warn("NewCipher error [%s] : %s", cipherKeyByteFromProfile, err.Error())
+		log.Warn("NewCipher error : %s", err.Error())
 		return src
 	}
+	return encrypted
+}
+
+func encryptNativeWithKey(src string, key, iv []byte) (string, error) {
+	cipherBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
 
-	cbcEncryptor := cipher.NewCBCEncrypter(cipherBlock, cipherIVKeyByteFromProfile)
+	cbcEncryptor := cipher.NewCBCEncrypter(cipherBlock, iv)
 	paddedPlaintextBytes := pad([]byte(src), cbcEncryptor.BlockSize())
 
 	ciphertextBytes := make([]byte, len(paddedPlaintextBytes))
 	cbcEncryptor.CryptBlocks(ciphertextBytes, paddedPlaintextBytes)
-	return base64.StdEncoding.EncodeToString(ciphertextBytes)
+	return base64.StdEncoding.EncodeToString(ciphertextBytes), nil
 }
 
 func pad(blocks []byte, blockSize int) []byte {
@@ -163,26 +201,35 @@ func pad(blocks []byte, blockSize int) []byte {
 	return append(blocks, padBlocks...)
 }
 
-func unpad(blocks []byte) []byte {
+func unpad(blocks []byte) ([]byte, error) {
 	blockLen := len(blocks)
+	if blockLen == 0 {
+		return nil, errors.New("empty blocks")
+	}
 	paddedLen := int(blocks[blockLen-1])
-	return blocks[:(blockLen - paddedLen)]
+	if paddedLen == 0 || paddedLen > blockLen {
+		return nil, errors.New("invalid padding")
+	}
+	return blocks[:(blockLen - paddedLen)], nil
 }
 
 func secretDecryptB64(src string) string {
-	decoded, err := base64.StdEncoding.DecodeString(src)
+	v, err := decryptB64(src)
 	if err != nil {
-		log.Warn("cipher [%s] base64 decoding error : %s", src, err.Error())
+		log.Warn("%s", err.Error())
 		return src
 	}
-	return string(decoded)
+	return v
 }
 
-func secretEncryptB64(src string) string {
-	return base64.StdEncoding.EncodeToString([]byte(src))
+func decryptB64(src string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(src)
+	if err != nil {
+		return "", fmt.Errorf("cipher [%s] base64 decoding error : %s", src, err.Error())
+	}
+	return string(decoded), nil
 }
 
-func secretDecryptAWS(src string) string {
-	log.Warn("not support aws secret manager")
-	return src
+func secretEncryptB64(src string) string {
+	return base64.StdEncoding.EncodeToString([]byte(src))
 }
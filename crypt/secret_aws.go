@@ -0,0 +1,219 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오후 4:20
+ */
+
+package crypt
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsSecretsManagerService = "secretsmanager"
+	awsSigningAlgorithm      = "AWS4-HMAC-SHA256"
+	awsTargetGetSecretValue  = "secretsmanager.GetSecretValue"
+)
+
+// awsCredentials static credentials from environment
+type awsCredentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// AWSSecretProvider AWS Secrets Manager provider. secret format is 'secretId' or 'secretId#jsonKey'.
+// region, credentials and endpoint are read from standard AWS environment variables
+// (AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_ENDPOINT_URL_SECRETS_MANAGER).
+//
+// only static credentials of environment variables are supported. shared config/credentials files,
+// EC2 instance profile (IMDSv2), ECS task role and web identity credentials are not resolved.
+// on such hosts, export temporary credentials (e.g. 'aws configure export-credentials --format env') before start
+type AWSSecretProvider struct {
+	Client *http.Client
+	now    func() time.Time
+}
+
+func NewAWSSecretProvider() *AWSSecretProvider {
+	return &AWSSecretProvider{
+		Client: &http.Client{Timeout: secretHttpTimeout},
+		now:    time.Now,
+	}
+}
+
+func (p *AWSSecretProvider) Resolve(secret string) (string, error) {
+	secretId, field, _ := strings.Cut(secret, secretFieldSeparator)
+
+	region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	if len(region) == 0 {
+		return "", errors.New("aws region is not configured. set AWS_REGION")
+	}
+	cred := awsCredentials{
+		accessKey:    firstEnv("AWS_ACCESS_KEY_ID"),
+		secretKey:    firstEnv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: firstEnv("AWS_SESSION_TOKEN"),
+	}
+	if len(cred.accessKey) == 0 || len(cred.secretKey) == 0 {
+		return "", errors.New("aws credentials are not configured. set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (instance profile and task role are not supported)")
+	}
+	endpoint := firstEnv("AWS_ENDPOINT_URL_SECRETS_MANAGER", "AWS_ENDPOINT_URL")
+	if len(endpoint) == 0 {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsSecretsManagerService, region)
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": secretId})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", awsTargetGetSecretValue)
+	signAWSRequestV4(req, payload, cred, region, awsSecretsManagerService, p.now())
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, secretHttpMaxBodyBytes))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("aws secrets manager responded %d for %s : %s", resp.StatusCode, secretId, string(body))
+	}
+
+	var result struct {
+		SecretString *string
+		SecretBinary []byte // base64 encoded in json
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("invalid aws secrets manager response : %w", err)
+	}
+
+	var value string
+	switch {
+	case result.SecretString != nil:
+		value = *result.SecretString
+	case result.SecretBinary != nil:
+		value = string(result.SecretBinary)
+	default:
+		return "", fmt.Errorf("aws secret %s has no value", secretId)
+	}
+
+	if len(field) == 0 {
+		return value, nil
+	}
+	var doc map[string]any
+	if err = json.Unmarshal([]byte(value), &doc); err != nil {
+		return "", fmt.Errorf("key %s requested but aws secret %s is not json object", field, secretId)
+	}
+	v, ok := doc[field]
+	if !ok {
+		return "", fmt.Errorf("not found key %s in aws secret %s", field, secretId)
+	}
+	return stringifySecretValue(v), nil
+}
+
+// signAWSRequestV4 sign request with AWS signature version 4
+func signAWSRequestV4(req *http.Request, payload []byte, cred awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if len(cred.sessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", cred.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{awsSigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cred.secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgorithm, cred.accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	// url.Values.Encode sorts by key
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오후 2:40
+ */
+
+package crypt
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/fatima-go/fatima-log"
+)
+
+// DefaultSecretCacheTTL cache ttl for remote secret store (vault, aws)
+const DefaultSecretCacheTTL = 5 * time.Minute
+
+// DefaultSecretCacheMaxStale how long expired secret is used while fetching fails
+const DefaultSecretCacheMaxStale = 30 * time.Minute
+
+type cachedSecret struct {
+	value     string
+	expiredAt time.Time
+}
+
+// secretFetch fetching of one secret shared by concurrent resolves
+type secretFetch struct {
+	done  chan struct{}
+	value string
+	err   error
+}
+
+// CachedSecretProvider cache resolved secret for ttl.
+// expired secret is fetched again on next resolve. if fetching fails, stale value is used up to max stale.
+// secret is fetched without lock so that slow store does not block other secrets. same secret is fetched once at a time
+type CachedSecretProvider struct {
+	provider SecretProvider
+	ttl      time.Duration
+	maxStale time.Duration
+	now      func() time.Time
+
+	mutex    sync.Mutex
+	entries  map[string]cachedSecret
+	inflight map[string]*secretFetch
+}
+
+func NewCachedSecretProvider(provider SecretProvider, ttl time.Duration) *CachedSecretProvider {
+	return &CachedSecretProvider{
+		provider: provider,
+		ttl:      ttl,
+		maxStale: DefaultSecretCacheMaxStale,
+		now:      time.Now,
+		entries:  make(map[string]cachedSecret),
+		inflight: make(map[string]*secretFetch),
+	}
+}
+
+// SetMaxStale how long expired secret is used while fetching fails. 0 means stale value is not used
+func (c *CachedSecretProvider) SetMaxStale(maxStale time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.maxStale = max(maxStale, 0)
+}
+
+func (c *CachedSecretProvider) Resolve(secret string) (string, error) {
+	c.mutex.Lock()
+	now := c.now()
+	entry, cached := c.entries[secret]
+	if cached && now.Before(entry.expiredAt) {
+		c.mutex.Unlock()
+		return entry.value, nil
+	}
+	maxStale := c.maxStale
+	fetch, running := c.inflight[secret]
+	if !running {
+		fetch = &secretFetch{done: make(chan struct{})}
+		c.inflight[secret] = fetch
+	}
+	c.mutex.Unlock()
+
+	if running {
+		<-fetch.done
+	} else {
+		c.fetch(secret, fetch)
+	}
+	if fetch.err == nil {
+		return fetch.value, nil
+	}
+	if cached && now.Before(entry.expiredAt.Add(maxStale)) {
+		log.Warn("fail to refresh secret. use stale value : %s", fetch.err.Error())
+		return entry.value, nil
+	}
+	return "", fetch.err
+}
+
+// fetch resolve secret from provider and wake up resolves waiting same secret
+func (c *CachedSecretProvider) fetch(secret string, fetch *secretFetch) {
+	defer func() {
+		if r := recover(); r != nil {
+			fetch.value, fetch.err = "", fmt.Errorf("panic to resolve secret : %v", r)
+		}
+		c.mutex.Lock()
+		if fetch.err == nil {
+			c.entries[secret] = cachedSecret{value: fetch.value, expiredAt: c.now().Add(c.ttl)}
+		}
+		delete(c.inflight, secret)
+		c.mutex.Unlock()
+		close(fetch.done)
+	}()
+	fetch.value, fetch.err = c.provider.Resolve(secret)
+}
+
+// Refresh drop every cached secret
+func (c *CachedSecretProvider) Refresh() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]cachedSecret)
+}
+
+// Invalidate drop cached secret
+func (c *CachedSecretProvider) Invalidate(secret string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, secret)
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오후 3:05
+ */
+
+package crypt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	EnvSecretVaultAddr  = "FATIMA_VAULT_ADDR"  // e.g) https://vault.example.com:8200
+	EnvSecretVaultToken = "FATIMA_VAULT_TOKEN" // VAULT_TOKEN is used if not set
+
+	secretFieldSeparator   = "#"
+	secretHttpTimeout      = 10 * time.Second
+	secretHttpMaxBodyBytes = 1 << 20
+)
+
+// HTTPSecretProvider generic http secret store provider.
+// secret format is 'path#field'. GET {BaseURL}/{PathPrefix}/{path} and pick field from json response.
+// field lookup order : data.data.{field} (vault kv v2), data.{field} (vault kv v1), {field}.
+// if field is omitted and there is only one field, the value is used. non json response body is used as it is
+type HTTPSecretProvider struct {
+	BaseURL     func() string // resolved on every request
+	Token       func() string
+	TokenHeader string // e.g) X-Vault-Token
+	PathPrefix  string // e.g) v1
+	Client      *http.Client
+}
+
+// NewVaultSecretProvider vault compatible http provider. address and token are read from environment
+func NewVaultSecretProvider() *HTTPSecretProvider {
+	return &HTTPSecretProvider{
+		BaseURL: func() string {
+			return firstEnv(EnvSecretVaultAddr, "VAULT_ADDR")
+		},
+		Token: func() string {
+			return firstEnv(EnvSecretVaultToken, "VAULT_TOKEN")
+		},
+		TokenHeader: "X-Vault-Token",
+		PathPrefix:  "v1",
+		Client:      &http.Client{Timeout: secretHttpTimeout},
+	}
+}
+
+func (p *HTTPSecretProvider) Resolve(secret string) (string, error) {
+	base := strings.TrimRight(p.BaseURL(), "/")
+	if len(base) == 0 {
+		return "", errors.New("secret store address is not configured")
+	}
+
+	secretPath, field, _ := strings.Cut(secret, secretFieldSeparator)
+	url := base
+	if len(p.PathPrefix) > 0 {
+		url = url + "/" + strings.Trim(p.PathPrefix, "/")
+	}
+	url = url + "/" + strings.TrimLeft(secretPath, "/")
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Token != nil && len(p.TokenHeader) > 0 {
+		if token := p.Token(); len(token) > 0 {
+			req.Header.Set(p.TokenHeader, token)
+		}
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, secretHttpMaxBodyBytes))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret store responded %d for %s", resp.StatusCode, secretPath)
+	}
+
+	return pickSecretField(body, field)
+}
+
+// pickSecretField find field from json body. see HTTPSecretProvider
+func pickSecretField(body []byte, field string) (string, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		if len(field) > 0 {
+			return "", fmt.Errorf("field %s requested but response is not json object", field)
+		}
+		return strings.TrimRight(string(body), "\r\n"), nil
+	}
+
+	candidates := []map[string]any{doc}
+	if data, ok := doc["data"].(map[string]any); ok {
+		candidates = append([]map[string]any{data}, candidates...)
+		if inner, ok := data["data"].(map[string]any); ok {
+			candidates = append([]map[string]any{inner}, candidates...)
+		}
+	}
+
+	if len(field) == 0 {
+		if len(candidates[0]) == 1 {
+			for _, v := range candidates[0] {
+				return stringifySecretValue(v), nil
+			}
+		}
+		return "", errors.New("secret has multiple fields. specify field with path#field")
+	}
+
+	for _, c := range candidates {
+		if v, ok := c[field]; ok {
+			return stringifySecretValue(v), nil
+		}
+	}
+	return "", fmt.Errorf("not found field %s in secret", field)
+}
+
+func stringifySecretValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
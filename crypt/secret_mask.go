@@ -0,0 +1,196 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 22. 오전 10:20
+ */
+
+package crypt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatima-go/fatima-core"
+)
+
+const (
+	SecretMask = "******"
+	// minimum length of plaintext to be redacted from text. too short value (e.g. 'y') is not redacted
+	secretRedactMinLength = 4
+)
+
+// Secret plaintext secret value. printed (fmt, json, text) as SecretMask. use Reveal to get plaintext
+type Secret string
+
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	return SecretMask
+}
+
+func (s Secret) GoString() string {
+	return SecretMask
+}
+
+// Format masks every verb (%s, %v, %q, %x, ...)
+func (s Secret) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, SecretMask)
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(SecretMask)
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(SecretMask), nil
+}
+
+// SecretConfig config which returns value as Secret. e.g) builder.PropertyConfigReader
+type SecretConfig interface {
+	GetSecret(key string) (Secret, error)
+}
+
+// GetSecret value of key as Secret. value is wrapped by Secret when config is not SecretConfig
+func GetSecret(config fatima.Config, key string) (Secret, error) {
+	if c, ok := config.(SecretConfig); ok {
+		return c.GetSecret(key)
+	}
+	v, err := config.GetString(key)
+	return Secret(v), err
+}
+
+var (
+	secretRedactLock   sync.RWMutex
+	secretKeyPatterns  = make([]string, 0)
+	secretValues       = make(map[string]struct{})
+	secretValuesSorted []string // longest first
+)
+
+// RegisterSecretKeyPattern register config key pattern (path.Match) treated as secret in addition to '*.secret'.
+// e.g) *.password, db.*.token
+func RegisterSecretKeyPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid secret key pattern [%s] : %w", pattern, err)
+	}
+	secretRedactLock.Lock()
+	defer secretRedactLock.Unlock()
+	secretKeyPatterns = append(secretKeyPatterns, pattern)
+	return nil
+}
+
+// IsSecretKey whether config key holds secret value ('.secret' suffix or registered pattern)
+func IsSecretKey(key string) bool {
+	if strings.HasSuffix(key, ".secret") {
+		return true
+	}
+	secretRedactLock.RLock()
+	defer secretRedactLock.RUnlock()
+	for _, p := range secretKeyPatterns {
+		if matched, _ := path.Match(p, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskValue return SecretMask if key is secret key. otherwise value is returned as it is
+func MaskValue(key, value string) string {
+	if IsSecretKey(key) {
+		return SecretMask
+	}
+	return value
+}
+
+// MaskValues return copy of values with secret keys masked. used for config dump
+func MaskValues(values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for k, v := range values {
+		masked[k] = MaskValue(k, v)
+	}
+	return masked
+}
+
+// RegisterSecretValue register plaintext to be redacted by Redact.
+// resolved secrets (ResolveSecret) are registered automatically
+func RegisterSecretValue(plaintext string) {
+	if len(plaintext) < secretRedactMinLength {
+		return
+	}
+	secretRedactLock.Lock()
+	defer secretRedactLock.Unlock()
+	if _, ok := secretValues[plaintext]; ok {
+		return
+	}
+	secretValues[plaintext] = struct{}{}
+	secretValuesSorted = append(secretValuesSorted, plaintext)
+	sort.Slice(secretValuesSorted, func(i, j int) bool {
+		return len(secretValuesSorted[i]) > len(secretValuesSorted[j])
+	})
+}
+
+// Redact replace every registered secret plaintext in s with SecretMask.
+// used for error message, notify payload, etc
+func Redact(s string) string {
+	secretRedactLock.RLock()
+	defer secretRedactLock.RUnlock()
+	for _, v := range secretValuesSorted {
+		if strings.Contains(s, v) {
+			s = strings.ReplaceAll(s, v, SecretMask)
+		}
+	}
+	return s
+}
+
+// RedactError return error whose message is redacted. nil is returned as it is
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := Redact(msg)
+	if redacted == msg {
+		return err
+	}
+	return redactedError{msg: redacted, cause: err}
+}
+
+type redactedError struct {
+	msg   string
+	cause error
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap return redacted copy of wrapped error so that plaintext is not exposed by unwrapping
+func (e redactedError) Unwrap() error {
+	return RedactError(errors.Unwrap(e.cause))
+}
+
+// Is match errors of original chain (e.g. errors.Is(err, cause)) without exposing them
+func (e redactedError) Is(target error) bool {
+	return errors.Is(e.cause, target)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 22. 오전 11:40
+ */
+
+package crypt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFormat(t *testing.T) {
+	s := Secret("hunter2-password")
+	type holder struct {
+		User     string
+		Password Secret
+	}
+	h := holder{User: "fatima", Password: s}
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%10s"} {
+		assert.NotContains(t, fmt.Sprintf(format, s), "hunter2", format)
+		assert.NotContains(t, fmt.Sprintf(format, h), "hunter2", format)
+	}
+	b, err := json.Marshal(h)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"User":"fatima","Password":"******"}`, string(b))
+	assert.Equal(t, "hunter2-password", s.Reveal())
+}
+
+func TestIsSecretKey(t *testing.T) {
+	require.NoError(t, RegisterSecretKeyPattern("*.password"))
+	assert.Error(t, RegisterSecretKeyPattern("[invalid"))
+
+	assert.True(t, IsSecretKey("db.password.secret"))
+	assert.True(t, IsSecretKey("db.password"))
+	assert.False(t, IsSecretKey("db.host"))
+
+	masked := MaskValues(map[string]string{"db.host": "localhost", "db.password": "pw", "api.key.secret": "key"})
+	assert.Equal(t, map[string]string{"db.host": "localhost", "db.password": SecretMask, "api.key.secret": SecretMask}, masked)
+}
+
+func TestRedact(t *testing.T) {
+	_, err := ResolveSecretWithError(CreateSecretBase64("resolved-plaintext"))
+	require.NoError(t, err)
+	RegisterSecretValue("abc") // too short. not redacted
+
+	assert.Equal(t, "password is ****** (abc)", Redact("password is resolved-plaintext (abc)"))
+
+	cause := errors.New("connect failed with resolved-plaintext")
+	redacted := RedactError(fmt.Errorf("wrapped : %w", cause))
+	assert.Equal(t, "wrapped : connect failed with ******", redacted.Error())
+	assert.ErrorIs(t, redacted, cause)
+	for err := errors.Unwrap(redacted); err != nil; err = errors.Unwrap(err) {
+		assert.NotContains(t, err.Error(), "resolved-plaintext")
+	}
+	assert.Nil(t, RedactError(nil))
+}
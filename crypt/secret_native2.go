@@ -0,0 +1,346 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 21. 오전 10:40
+ */
+
+package crypt
+
+/*
+native2 secret scheme
+
+	native2:<keyId>:<base64(nonce + aes-256-gcm ciphertext)>
+
+key id is used as additional authenticated data. keys are loaded from
+  - FATIMA_SECRET_KEY environment variable : <keyId>:<base64 32 bytes key> (becomes active key)
+  - keyfile : FATIMA_SECRET_KEYFILE or $FATIMA_HOME/conf/fatima-secret.keys
+
+keyfile format (should not be accessible by group or others, e.g. 0600)
+
+	# active key is used for encryption. every key is used for decryption
+	active=k2
+	k1=<base64 32 bytes key>
+	k2=<base64 32 bytes key>
+*/
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+)
+
+const (
+	SecretSchemeNative2 = "native2"
+
+	EnvSecretKey            = "FATIMA_SECRET_KEY"     // <keyId>:<base64 key>
+	EnvSecretKeyfile        = "FATIMA_SECRET_KEYFILE" // keyfile path
+	SecretKeyfileName       = "fatima-secret.keys"
+	secretKeyfileActiveProp = "active"
+)
+
+var secretKeyIdPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// SecretKeyring AES-256 keys for native2 scheme
+type SecretKeyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+func NewSecretKeyring() *SecretKeyring {
+	return &SecretKeyring{keys: make(map[string][]byte)}
+}
+
+// AddKey add key. the first key becomes active key
+func (k *SecretKeyring) AddKey(keyId string, key []byte) error {
+	if !secretKeyIdPattern.MatchString(keyId) {
+		return fmt.Errorf("invalid secret key id [%s]", keyId)
+	}
+	if len(key) != CipherKeyBytesLength {
+		return fmt.Errorf("secret key %s should be %d bytes but %d", keyId, CipherKeyBytesLength, len(key))
+	}
+	k.keys[keyId] = key
+	if len(k.active) == 0 {
+		k.active = keyId
+	}
+	return nil
+}
+
+// SetActive set key which is used for encryption
+func (k *SecretKeyring) SetActive(keyId string) error {
+	if _, ok := k.keys[keyId]; !ok {
+		return fmt.Errorf("not found secret key %s", keyId)
+	}
+	k.active = keyId
+	return nil
+}
+
+func (k *SecretKeyring) GetActive() string {
+	return k.active
+}
+
+// GetKeyIds return sorted key ids
+func (k *SecretKeyring) GetKeyIds() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Encrypt encrypt src with active key. result is native2 secret string (with scheme)
+func (k *SecretKeyring) Encrypt(src string) (string, error) {
+	if len(k.active) == 0 {
+		return "", errors.New("no secret key. create keyfile or set " + EnvSecretKey)
+	}
+	gcm, err := newGCM(k.keys[k.active])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(src), []byte(k.active))
+	return fmt.Sprintf("%s:%s:%s", SecretSchemeNative2, k.active, base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// Decrypt decrypt native2 secret content (without scheme). <keyId>:<base64>
+func (k *SecretKeyring) Decrypt(secret string) (string, error) {
+	keyId, encoded, ok := strings.Cut(secret, ":")
+	if !ok {
+		return "", errors.New("invalid native2 secret format. expects <keyId>:<cipher>")
+	}
+	key, ok := k.keys[keyId]
+	if !ok {
+		return "", fmt.Errorf("not found secret key %s", keyId)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 format : %s", err.Error())
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("cipher is too short")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(keyId))
+	if err != nil {
+		return "", fmt.Errorf("fail to decrypt with key %s : %s", keyId, err.Error())
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateSecretKey create random AES-256 key (base64 encoded) for keyfile
+func GenerateSecretKey() (string, error) {
+	key := make([]byte, CipherKeyBytesLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// GetSecretKeyfilePath FATIMA_SECRET_KEYFILE or $FATIMA_HOME/conf/fatima-secret.keys
+func GetSecretKeyfilePath() string {
+	if v := os.Getenv(EnvSecretKeyfile); len(v) > 0 {
+		return v
+	}
+	home := os.Getenv(fatima.ENV_FATIMA_HOME)
+	if len(home) == 0 {
+		return ""
+	}
+	return filepath.Join(home, "conf", SecretKeyfileName)
+}
+
+// LoadSecretKeyring load keys from keyfile and FATIMA_SECRET_KEY environment variable.
+// key from environment variable becomes active key
+func LoadSecretKeyring() (*SecretKeyring, error) {
+	keyring := NewSecretKeyring()
+
+	path := GetSecretKeyfilePath()
+	if len(path) > 0 {
+		if _, err := os.Stat(path); err == nil {
+			if err = keyring.loadKeyfile(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if v := os.Getenv(EnvSecretKey); len(v) > 0 {
+		keyId, encoded, ok := strings.Cut(v, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s should be <keyId>:<base64 key>", EnvSecretKey)
+		}
+		if err := keyring.addEncodedKey(keyId, encoded); err != nil {
+			return nil, err
+		}
+		keyring.active = keyId
+	}
+
+	return keyring, nil
+}
+
+func (k *SecretKeyring) addEncodedKey(keyId, encoded string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return fmt.Errorf("secret key %s is invalid base64 format", keyId)
+	}
+	return k.AddKey(keyId, key)
+}
+
+func (k *SecretKeyring) loadKeyfile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("secret keyfile %s permission %04o is too open. use 0600", path, info.Mode().Perm())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	active := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("invalid keyfile line : %s", line)
+		}
+		name = strings.TrimSpace(name)
+		if name == secretKeyfileActiveProp {
+			active = strings.TrimSpace(value)
+			continue
+		}
+		if err = k.addEncodedKey(name, value); err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(active) > 0 {
+		return k.SetActive(active)
+	}
+	return nil
+}
+
+var (
+	secretKeyringLock sync.Mutex
+	secretKeyring     *SecretKeyring
+	secretKeyringSet  bool      // keyring is given by SetSecretKeyring. it is not reloaded
+	secretKeyfile     string    // keyfile path of loaded keyring
+	secretKeyfileTime time.Time // keyfile modification time of loaded keyring
+)
+
+// SetSecretKeyring replace keyring used by native2 scheme. nil means reloading on next use
+func SetSecretKeyring(keyring *SecretKeyring) {
+	secretKeyringLock.Lock()
+	defer secretKeyringLock.Unlock()
+	secretKeyring = keyring
+	secretKeyringSet = keyring != nil
+}
+
+// GetSecretKeyring return keyring used by native2 scheme. loaded keyring is reloaded when keyfile is changed.
+// empty keyring (e.g. keyfile is not created yet) is not cached
+func GetSecretKeyring() (*SecretKeyring, error) {
+	secretKeyringLock.Lock()
+	defer secretKeyringLock.Unlock()
+	if secretKeyringSet {
+		return secretKeyring, nil
+	}
+
+	path := GetSecretKeyfilePath()
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	if secretKeyring != nil && secretKeyfile == path && secretKeyfileTime.Equal(modTime) {
+		return secretKeyring, nil
+	}
+
+	keyring, err := LoadSecretKeyring()
+	if err != nil {
+		return nil, err
+	}
+	if len(keyring.keys) == 0 {
+		secretKeyring = nil
+		return keyring, nil
+	}
+	secretKeyring, secretKeyfile, secretKeyfileTime = keyring, path, modTime
+	return secretKeyring, nil
+}
+
+// CreateSecretNative2 native2 스킴으로 암호화 (active key 사용)
+func CreateSecretNative2(src string) (string, error) {
+	keyring, err := GetSecretKeyring()
+	if err != nil {
+		return "", err
+	}
+	return keyring.Encrypt(src)
+}
+
+func decryptNative2(secret string) (string, error) {
+	keyring, err := GetSecretKeyring()
+	if err != nil {
+		return "", err
+	}
+	return keyring.Decrypt(secret)
+}
+
+// ReencryptSecret resolve secret of any scheme (e.g. legacy native) and encrypt again with active native2 key
+func ReencryptSecret(src string) (string, error) {
+	plain, err := ResolveSecretWithError(src)
+	if err != nil {
+		return "", err
+	}
+	return CreateSecretNative2(plain)
+}
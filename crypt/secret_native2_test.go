@@ -0,0 +1,192 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 21. 오전 11:50
+ */
+
+package crypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, CipherKeyBytesLength)
+}
+
+func TestSecretKeyringRotation(t *testing.T) {
+	keyring := NewSecretKeyring()
+	require.NoError(t, keyring.AddKey("k1", testKey(1)))
+
+	old, err := keyring.Encrypt(sample)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(old, "native2:k1:"))
+
+	again, err := keyring.Encrypt(sample)
+	require.NoError(t, err)
+	assert.NotEqual(t, old, again, "nonce should be random")
+
+	require.NoError(t, keyring.AddKey("k2", testKey(2)))
+	require.NoError(t, keyring.SetActive("k2"))
+	rotated, err := keyring.Encrypt(sample)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rotated, "native2:k2:"))
+
+	for _, v := range []string{old, rotated} {
+		plain, err := keyring.Decrypt(strings.TrimPrefix(v, SecretSchemeNative2+":"))
+		assert.NoError(t, err)
+		assert.Equal(t, sample, plain)
+	}
+	assert.Equal(t, []string{"k1", "k2"}, keyring.GetKeyIds())
+}
+
+func TestSecretKeyringDecryptError(t *testing.T) {
+	keyring := NewSecretKeyring()
+	require.NoError(t, keyring.AddKey("k1", testKey(1)))
+	encrypted, err := keyring.Encrypt(sample)
+	require.NoError(t, err)
+	_, content, _ := strings.Cut(encrypted, ":")
+	_, cipherText, _ := strings.Cut(content, ":")
+
+	raw, _ := base64.StdEncoding.DecodeString(cipherText)
+	raw[len(raw)-1] ^= 0xff
+	tampered := base64.StdEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{name: "no_key_id", secret: cipherText},
+		{name: "unknown_key_id", secret: "k9:" + cipherText},
+		{name: "key_id_swapped", secret: "k2:" + cipherText},
+		{name: "tampered", secret: "k1:" + tampered},
+		{name: "invalid_base64", secret: "k1:%%%"},
+		{name: "too_short", secret: "k1:AAAA"},
+	}
+
+	require.NoError(t, keyring.AddKey("k2", testKey(1)))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := keyring.Decrypt(tt.secret)
+			assert.Error(t, err)
+		})
+	}
+
+	assert.Error(t, keyring.AddKey("bad id", testKey(1)))
+	assert.Error(t, keyring.AddKey("short", []byte("short")))
+	assert.Error(t, NewSecretKeyring().SetActive("none"))
+	_, err = NewSecretKeyring().Encrypt(sample)
+	assert.Error(t, err)
+}
+
+func TestLoadSecretKeyring(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(fatima.ENV_FATIMA_HOME, home)
+	t.Setenv(EnvSecretKeyfile, "")
+	t.Setenv(EnvSecretKey, "")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "conf"), 0755))
+	keyfile := filepath.Join(home, "conf", SecretKeyfileName)
+
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+	require.NoError(t, os.WriteFile(keyfile, []byte("# keys\nactive=k1\nk1="+k1+"\nk2 = "+k2+"\n"), 0600))
+
+	keyring, err := LoadSecretKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyring.GetActive())
+	assert.Equal(t, []string{"k1", "k2"}, keyring.GetKeyIds())
+
+	// key from environment becomes active
+	t.Setenv(EnvSecretKey, "k3:"+base64.StdEncoding.EncodeToString(testKey(3)))
+	keyring, err = LoadSecretKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, "k3", keyring.GetActive())
+
+	require.NoError(t, os.Chmod(keyfile, 0644))
+	_, err = LoadSecretKeyring()
+	assert.ErrorContains(t, err, "too open")
+}
+
+func TestGetSecretKeyringReload(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(fatima.ENV_FATIMA_HOME, home)
+	t.Setenv(EnvSecretKeyfile, "")
+	t.Setenv(EnvSecretKey, "")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "conf"), 0755))
+	keyfile := filepath.Join(home, "conf", SecretKeyfileName)
+	SetSecretKeyring(nil)
+	t.Cleanup(func() { SetSecretKeyring(nil) })
+
+	// missing keyfile is not cached
+	keyring, err := GetSecretKeyring()
+	require.NoError(t, err)
+	assert.Empty(t, keyring.GetKeyIds())
+
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	require.NoError(t, os.WriteFile(keyfile, []byte("k1="+k1+"\n"), 0600))
+	keyring, err = GetSecretKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1"}, keyring.GetKeyIds())
+	cached, _ := GetSecretKeyring()
+	assert.Same(t, keyring, cached)
+
+	// changed keyfile is reloaded
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+	require.NoError(t, os.WriteFile(keyfile, []byte("active=k2\nk1="+k1+"\nk2="+k2+"\n"), 0600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(keyfile, later, later))
+	keyring, err = GetSecretKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyring.GetActive())
+
+	// keyring given by SetSecretKeyring is not reloaded
+	given := NewSecretKeyring()
+	SetSecretKeyring(given)
+	keyring, _ = GetSecretKeyring()
+	assert.Same(t, given, keyring)
+}
+
+func TestResolveSecretNative2(t *testing.T) {
+	log.Initialize(log.NewPreference(""))
+	keyring := NewSecretKeyring()
+	require.NoError(t, keyring.AddKey("k1", testKey(1)))
+	SetSecretKeyring(keyring)
+	defer SetSecretKeyring(nil)
+	registerBuiltinSecretProviders()
+
+	encrypted, err := CreateSecretNative2(sample)
+	require.NoError(t, err)
+	assert.Equal(t, sample, ResolveSecret(encrypted))
+
+	// legacy native value is re-encrypted with active native2 key
+	legacy := SecretSchemeNative + ":" + secretEncryptNative(sample)
+	migrated, err := ReencryptSecret(legacy)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(migrated, "native2:k1:"))
+	assert.Equal(t, sample, ResolveSecret(migrated))
+}
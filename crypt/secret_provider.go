@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오후 2:10
+ */
+
+package crypt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	SecretSchemeFile  = "file"
+	SecretSchemeEnv   = "env"
+	SecretSchemeVault = "vault"
+)
+
+// SecretProvider resolve secret content (without scheme) to plain value
+type SecretProvider interface {
+	Resolve(secret string) (string, error)
+}
+
+// SecretProviderFunc adapter to use ordinary function as SecretProvider
+type SecretProviderFunc func(secret string) (string, error)
+
+func (f SecretProviderFunc) Resolve(secret string) (string, error) {
+	return f(secret)
+}
+
+var (
+	secretProviderLock sync.RWMutex
+	secretProviders    = make(map[string]SecretProvider)
+)
+
+func init() {
+	registerBuiltinSecretProviders()
+}
+
+func registerBuiltinSecretProviders() {
+	_ = RegisterSecretProvider(SecretSchemeNative, SecretProviderFunc(decryptNative))
+	_ = RegisterSecretProvider(SecretSchemeNative2, SecretProviderFunc(decryptNative2))
+	_ = RegisterSecretProvider(SecretSchemeB64, SecretProviderFunc(decryptB64))
+	_ = RegisterSecretProvider(SecretSchemeFile, SecretProviderFunc(resolveFileSecret))
+	_ = RegisterSecretProvider(SecretSchemeEnv, SecretProviderFunc(resolveEnvSecret))
+	_ = RegisterSecretProvider(SecretSchemeVault, NewCachedSecretProvider(NewVaultSecretProvider(), DefaultSecretCacheTTL))
+	_ = RegisterSecretProvider(SecretSchemeAWS, NewCachedSecretProvider(NewAWSSecretProvider(), DefaultSecretCacheTTL))
+}
+
+// RegisterSecretProvider register (or replace) provider for scheme. e.g) 'myvault' for myvault:path/to/secret
+func RegisterSecretProvider(scheme string, provider SecretProvider) error {
+	if len(scheme) == 0 || strings.Contains(scheme, ":") {
+		return fmt.Errorf("invalid secret scheme [%s]", scheme)
+	}
+	if provider == nil {
+		return fmt.Errorf("nil secret provider for scheme %s", scheme)
+	}
+
+	secretProviderLock.Lock()
+	defer secretProviderLock.Unlock()
+	secretProviders[scheme] = provider
+	return nil
+}
+
+// GetSecretProvider find provider registered for scheme
+func GetSecretProvider(scheme string) (SecretProvider, bool) {
+	secretProviderLock.RLock()
+	defer secretProviderLock.RUnlock()
+	p, ok := secretProviders[scheme]
+	return p, ok
+}
+
+// RefreshSecrets drop every cached secret. next resolve fetches secret from the store again
+func RefreshSecrets() {
+	secretProviderLock.RLock()
+	defer secretProviderLock.RUnlock()
+	for _, p := range secretProviders {
+		if c, ok := p.(*CachedSecretProvider); ok {
+			c.Refresh()
+		}
+	}
+}
+
+// splitSecretScheme split 'scheme:content'. ok is false when scheme or content is empty
+func splitSecretScheme(src string) (string, string, bool) {
+	idx := strings.Index(src, ":")
+	if idx < 1 || (len(src) <= idx+1) {
+		return "", "", false
+	}
+	return src[:idx], src[idx+1:], true
+}
+
+var errSecretSchemeNotFound = errors.New("secret scheme not found")
+
+// ResolveSecretWithError resolve secret(with scheme) string and report failure as error
+func ResolveSecretWithError(src string) (string, error) {
+	scheme, secret, ok := splitSecretScheme(src)
+	if !ok {
+		return src, errSecretSchemeNotFound
+	}
+
+	provider, ok := GetSecretProvider(scheme)
+	if !ok {
+		return src, fmt.Errorf("scheme %s is not supported", scheme)
+	}
+
+	v, err := provider.Resolve(secret)
+	if err != nil {
+		return secret, fmt.Errorf("fail to resolve %s secret : %w", scheme, err)
+	}
+	RegisterSecretValue(v)
+	return v, nil
+}
+
+// resolveFileSecret read secret from file. file should not be accessible by group or others (e.g. 0600)
+func resolveFileSecret(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is directory", path)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("secret file %s permission %04o is too open. use 0600", path, info.Mode().Perm())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// resolveEnvSecret read secret from os environment variable
+func resolveEnvSecret(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 20. 오후 5:30
+ */
+
+package crypt
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveFileAndEnvSecret(t *testing.T) {
+	log.Initialize(log.NewPreference(""))
+	dir := t.TempDir()
+	private := filepath.Join(dir, "private")
+	require.NoError(t, os.WriteFile(private, []byte("s3cret\n"), 0600))
+	open := filepath.Join(dir, "open")
+	require.NoError(t, os.WriteFile(open, []byte("s3cret\n"), 0644))
+	t.Setenv("FATIMA_TEST_SECRET", "from-env")
+
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{name: "file", src: "file:" + private, want: "s3cret"},
+		{name: "file_too_open", src: "file:" + open, wantErr: true},
+		{name: "file_not_found", src: "file:" + filepath.Join(dir, "none"), wantErr: true},
+		{name: "env", src: "env:FATIMA_TEST_SECRET", want: "from-env"},
+		{name: "env_not_set", src: "env:FATIMA_TEST_SECRET_NONE", wantErr: true},
+		{name: "unknown_scheme", src: "unknown:abc", want: "unknown:abc", wantErr: true},
+		{name: "no_scheme", src: "plain", want: "plain", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSecretWithError(tt.src)
+			assert.Equal(t, tt.wantErr, err != nil, "err : %v", err)
+			if !tt.wantErr || tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	log.Initialize(log.NewPreference(""))
+	assert.Error(t, RegisterSecretProvider("", SecretProviderFunc(decryptB64)))
+	assert.Error(t, RegisterSecretProvider("a:b", SecretProviderFunc(decryptB64)))
+	assert.Error(t, RegisterSecretProvider("custom", nil))
+
+	require.NoError(t, RegisterSecretProvider("custom", SecretProviderFunc(func(secret string) (string, error) {
+		return strings.ToUpper(secret), nil
+	})))
+	assert.Equal(t, "HELLO", ResolveSecret("custom:hello"))
+}
+
+func TestVaultSecretProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token-1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"pw","user":"fatima"},"metadata":{"version":1}}}`))
+		case "/v1/secret/single":
+			_, _ = w.Write([]byte(`{"data":{"value":"only"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(EnvSecretVaultAddr, server.URL)
+	t.Setenv(EnvSecretVaultToken, "token-1")
+
+	tests := []struct {
+		name    string
+		secret  string
+		want    string
+		wantErr bool
+	}{
+		{name: "kv2_field", secret: "secret/data/db#password", want: "pw"},
+		{name: "kv1_single_field", secret: "secret/single", want: "only"},
+		{name: "multiple_fields", secret: "secret/data/db", wantErr: true},
+		{name: "unknown_field", secret: "secret/data/db#none", wantErr: true},
+		{name: "not_found", secret: "secret/none#x", wantErr: true},
+	}
+
+	provider := NewVaultSecretProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.Resolve(tt.secret)
+			assert.Equal(t, tt.wantErr, err != nil, "err : %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAWSSecretProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != awsTargetGetSecretValue ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), awsSigningAlgorithm+" Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req["SecretId"] {
+		case "prod/db":
+			_, _ = w.Write([]byte(`{"Name":"prod/db","SecretString":"{\"password\":\"pw\",\"port\":5432}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException"}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_REGION", "ap-northeast-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+
+	provider := NewAWSSecretProvider()
+	v, err := provider.Resolve("prod/db")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"pw","port":5432}`, v)
+
+	v, err = provider.Resolve("prod/db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, "pw", v)
+
+	v, err = provider.Resolve("prod/db#port")
+	assert.NoError(t, err)
+	assert.Equal(t, "5432", v)
+
+	_, err = provider.Resolve("prod/none")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestAWSSigningKey(t *testing.T) {
+	// example from aws signature version 4 documentation
+	key := hmacSHA256([]byte("AWS4wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"), "20120215")
+	key = hmacSHA256(key, "us-east-1")
+	key = hmacSHA256(key, "iam")
+	key = hmacSHA256(key, "aws4_request")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestCachedSecretProvider(t *testing.T) {
+	log.Initialize(log.NewPreference(""))
+	calls := 0
+	fail := false
+	provider := NewCachedSecretProvider(SecretProviderFunc(func(secret string) (string, error) {
+		calls++
+		if fail {
+			return "", errors.New("store down")
+		}
+		return secret + "-v" + string(rune('0'+calls)), nil
+	}), time.Minute)
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	v, _ := provider.Resolve("db")
+	assert.Equal(t, "db-v1", v)
+	v, _ = provider.Resolve("db")
+	assert.Equal(t, "db-v1", v, "cached within ttl")
+
+	now = now.Add(2 * time.Minute)
+	v, _ = provider.Resolve("db")
+	assert.Equal(t, "db-v2", v, "fetched again after ttl")
+
+	now = now.Add(2 * time.Minute)
+	fail = true
+	v, err := provider.Resolve("db")
+	assert.NoError(t, err)
+	assert.Equal(t, "db-v2", v, "stale value is used when refresh fails")
+
+	now = now.Add(DefaultSecretCacheMaxStale)
+	_, err = provider.Resolve("db")
+	assert.Error(t, err, "stale value is not used over max stale")
+
+	provider.Refresh()
+	_, err = provider.Resolve("db")
+	assert.Error(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestCachedSecretProviderConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	provider := NewCachedSecretProvider(SecretProviderFunc(func(secret string) (string, error) {
+		calls.Add(1)
+		if secret == "slow" {
+			<-release
+		}
+		return secret + "-value", nil
+	}), time.Minute)
+
+	// same secret is fetched once while other secrets are not blocked by slow fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := provider.Resolve("slow")
+			assert.NoError(t, err)
+			assert.Equal(t, "slow-value", v)
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	v, err := provider.Resolve("fast")
+	require.NoError(t, err)
+	assert.Equal(t, "fast-value", v)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), calls.Load())
+}
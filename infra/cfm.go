@@ -23,10 +23,12 @@ package infra
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/fatima-go/fatima-core"
 	"github.com/fatima-go/fatima-core/monitor"
@@ -38,8 +40,16 @@ type logLevelItem struct {
 	level   string
 }
 
+const (
+	cfmFileHA        = "system.ha"
+	cfmFilePS        = "system.ps"
+	cfmFileLogLevels = "loglevels"
+)
+
 type CentralFilebaseManagement struct {
-	env fatima.FatimaEnv
+	env     fatima.FatimaEnv
+	mutex   sync.Mutex
+	watcher io.Closer
 }
 
 func newCentralFilebaseManagement(env fatima.FatimaEnv) *CentralFilebaseManagement {
@@ -49,12 +59,7 @@ func newCentralFilebaseManagement(env fatima.FatimaEnv) *CentralFilebaseManageme
 }
 
 func (c *CentralFilebaseManagement) GetPSStatus() (monitor.PSStatus, bool) {
-	filePath := filepath.Join(
-		c.env.GetFolderGuide().GetFatimaHome(),
-		"package",
-		"cfm",
-		"ha",
-		"system.ps")
+	filePath := filepath.Join(c.haFolder(), cfmFilePS)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -71,12 +76,7 @@ func (c *CentralFilebaseManagement) GetPSStatus() (monitor.PSStatus, bool) {
 }
 
 func (c *CentralFilebaseManagement) GetHAStatus() (monitor.HAStatus, bool) {
-	filePath := filepath.Join(
-		c.env.GetFolderGuide().GetFatimaHome(),
-		"package",
-		"cfm",
-		"ha",
-		"system.ha")
+	filePath := filepath.Join(c.haFolder(), cfmFileHA)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -93,11 +93,7 @@ func (c *CentralFilebaseManagement) GetHAStatus() (monitor.HAStatus, bool) {
 }
 
 func (c *CentralFilebaseManagement) GetLogLevel() (log.LogLevel, bool) {
-	filePath := filepath.Join(
-		c.env.GetFolderGuide().GetFatimaHome(),
-		"package",
-		"cfm",
-		"loglevels")
+	filePath := filepath.Join(c.cfmFolder(), cfmFileLogLevels)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		fmt.Println("read file fail", err)
@@ -125,3 +121,53 @@ func (c *CentralFilebaseManagement) GetLogLevel() (log.LogLevel, bool) {
 
 	return loglevel, true
 }
+
+func (c *CentralFilebaseManagement) cfmFolder() string {
+	return filepath.Join(c.env.GetFolderGuide().GetFatimaHome(), "package", "cfm")
+}
+
+func (c *CentralFilebaseManagement) haFolder() string {
+	return filepath.Join(c.cfmFolder(), "ha")
+}
+
+// Watch watch cfm folders (inotify). changed is called when system.ha, system.ps or loglevels is changed
+func (c *CentralFilebaseManagement) Watch(changed func()) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.watcher != nil {
+		return nil
+	}
+
+	files := map[string]bool{cfmFileHA: true, cfmFilePS: true, cfmFileLogLevels: true}
+	var watcher io.Closer
+	watcher, err := watchFolders([]string{c.cfmFolder(), c.haFolder()}, files, changed, func(err error) {
+		log.Warn("cfm watch is stopped. polling status : %s", err.Error())
+		c.mutex.Lock()
+		if c.watcher == watcher {
+			c.watcher = nil
+		}
+		c.mutex.Unlock()
+	})
+	if err != nil {
+		return err
+	}
+	c.watcher = watcher
+	return nil
+}
+
+func (c *CentralFilebaseManagement) Watching() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.watcher != nil
+}
+
+func (c *CentralFilebaseManagement) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.watcher == nil {
+		return nil
+	}
+	err := c.watcher.Close()
+	c.watcher = nil
+	return err
+}
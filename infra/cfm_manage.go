@@ -0,0 +1,302 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오후 2:00
+ */
+
+package infra
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/lib"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	cfmFileLock      = ".cfm.lock"
+	cfmFileAudit     = "audit.log"
+	cfmAuditMaxSize  = 1024 * 1024
+	cfmLockTimeout   = 3 * time.Second
+	cfmUnknownActor  = "unknown"
+	cfmStatusUnknown = "Unknown"
+)
+
+// NewSystemStatusManager return cfm (package/cfm) based SystemStatusManager
+func NewSystemStatusManager(env fatima.FatimaEnv) monitor.SystemStatusManager {
+	return newCentralFilebaseManagement(env)
+}
+
+func (c *CentralFilebaseManagement) SetHAStatus(status monitor.HAStatus, actor string) error {
+	return c.SetSystemStatus(monitor.SystemStatusChange{HA: &status}, actor)
+}
+
+func (c *CentralFilebaseManagement) SetPSStatus(status monitor.PSStatus, actor string) error {
+	return c.SetSystemStatus(monitor.SystemStatusChange{PS: &status}, actor)
+}
+
+func (c *CentralFilebaseManagement) SetLogLevel(process string, level log.LogLevel, actor string) error {
+	return c.SetSystemStatus(monitor.SystemStatusChange{Process: process, LogLevel: &level}, actor)
+}
+
+// cfmWrite file content to write and audit record of it
+type cfmWrite struct {
+	path   string
+	name   string
+	data   []byte
+	origin []byte // nil if file does not exist
+	record monitor.StatusAuditRecord
+}
+
+// SetSystemStatus change HA, PS and log level under one cfm lock. every file is written before audit.
+// files already written are restored when other file cannot be written
+func (c *CentralFilebaseManagement) SetSystemStatus(change monitor.SystemStatusChange, actor string) error {
+	if change.HA != nil && *change.HA == monitor.HA_STATUS_UNKNOWN {
+		return errors.New("unknown HA status")
+	}
+	if change.PS != nil && *change.PS == monitor.PS_STATUS_UNKNOWN {
+		return errors.New("unknown PS status")
+	}
+	levelValue := ""
+	if change.LogLevel != nil {
+		if len(change.Process) == 0 {
+			return errors.New("empty process name")
+		}
+		if *change.LogLevel != log.LOG_NONE {
+			levelValue = log.ConvertLogLevelToHexa(change.LogLevel.String())
+			if levelValue == "0x0" {
+				return fmt.Errorf("invalid log level [%d]", *change.LogLevel)
+			}
+		}
+	}
+
+	lock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	writes := make([]cfmWrite, 0, 3)
+	if change.HA != nil {
+		writes = c.appendStatusWrite(writes, cfmFileHA, strconv.Itoa(int(*change.HA)), actor, func(value string) string {
+			v, _ := strconv.Atoi(value)
+			return monitor.ToHAStatus(v).String()
+		})
+	}
+	if change.PS != nil {
+		writes = c.appendStatusWrite(writes, cfmFilePS, strconv.Itoa(int(*change.PS)), actor, func(value string) string {
+			v, _ := strconv.Atoi(value)
+			return monitor.ToPSStatus(v).String()
+		})
+	}
+	if change.LogLevel != nil {
+		if writes, err = c.appendLogLevelWrite(writes, change.Process, levelValue, actor); err != nil {
+			return err
+		}
+	}
+
+	for i, w := range writes {
+		if err = lib.WriteFileAtomic(w.path, w.data, 0644); err != nil {
+			restoreCfmWrites(writes[:i])
+			return fmt.Errorf("fail to write %s : %s", w.name, err.Error())
+		}
+	}
+	for _, w := range writes {
+		c.audit(w.record)
+	}
+	return nil
+}
+
+// appendStatusWrite append write of status file in ha folder when value is changed
+func (c *CentralFilebaseManagement) appendStatusWrite(writes []cfmWrite, name, value, actor string, display func(string) string) []cfmWrite {
+	path := filepath.Join(c.haFolder(), name)
+	old := cfmStatusUnknown
+	origin, err := os.ReadFile(path)
+	if err == nil {
+		old = display(strings.Trim(string(origin), "\r\n"))
+	}
+	if old == display(value) {
+		return writes
+	}
+	return append(writes, cfmWrite{path: path, name: name, data: []byte(value), origin: origin,
+		record: monitor.StatusAuditRecord{Actor: actor, Target: name, From: old, To: display(value)}})
+}
+
+// appendLogLevelWrite append write of loglevels when level of process is changed. empty value removes process
+func (c *CentralFilebaseManagement) appendLogLevelWrite(writes []cfmWrite, process, value, actor string) ([]cfmWrite, error) {
+	items, err := c.readLogLevels()
+	if err != nil {
+		return writes, err
+	}
+	old := items[process]
+	if old == value {
+		return writes, nil
+	}
+	if len(value) == 0 {
+		delete(items, process)
+	} else {
+		items[process] = value
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return writes, err
+	}
+	path := filepath.Join(c.cfmFolder(), cfmFileLogLevels)
+	origin, _ := os.ReadFile(path)
+	return append(writes, cfmWrite{path: path, name: cfmFileLogLevels, data: data, origin: origin,
+		record: monitor.StatusAuditRecord{Actor: actor, Target: cfmFileLogLevels, Process: process,
+			From: logLevelName(old), To: logLevelName(value)}}), nil
+}
+
+// restoreCfmWrites restore content of written files. it is called with cfm lock
+func restoreCfmWrites(writes []cfmWrite) {
+	for _, w := range writes {
+		var err error
+		if w.origin == nil {
+			err = os.Remove(w.path)
+		} else {
+			err = lib.WriteFileAtomic(w.path, w.origin, 0644)
+		}
+		if err != nil {
+			log.Warn("fail to restore %s : %s", w.name, err.Error())
+		}
+	}
+}
+
+func (c *CentralFilebaseManagement) readLogLevels() (map[string]string, error) {
+	items := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(c.cfmFolder(), cfmFileLogLevels))
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(strings.TrimSpace(string(data))) == 0) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid %s : %s", cfmFileLogLevels, err.Error())
+	}
+	if items == nil {
+		items = make(map[string]string)
+	}
+	return items, nil
+}
+
+// logLevelName cfm hexa value to name. e.g) 0x2F -> DEBUG
+func logLevelName(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+	level, err := log.ConvertHexaToLogLevel(value)
+	if err != nil || level == log.LOG_NONE {
+		return value
+	}
+	return level.String()
+}
+
+func (c *CentralFilebaseManagement) GetSystemStatusSnapshot() (monitor.SystemStatusSnapshot, error) {
+	snapshot := monitor.SystemStatusSnapshot{}
+	ha, _ := c.GetHAStatus()
+	ps, _ := c.GetPSStatus()
+	snapshot.HA, snapshot.PS = ha.String(), ps.String()
+
+	items, err := c.readLogLevels()
+	if err != nil {
+		return snapshot, err
+	}
+	if len(items) > 0 {
+		snapshot.LogLevels = make(map[string]string, len(items))
+		for process, value := range items {
+			snapshot.LogLevels[process] = logLevelName(value)
+		}
+	}
+	return snapshot, nil
+}
+
+// lock serialize cfm changes among processes and tools
+func (c *CentralFilebaseManagement) lock() (*lib.FileLock, error) {
+	if err := os.MkdirAll(c.haFolder(), 0755); err != nil {
+		return nil, err
+	}
+	lock, err := lib.LockFile(filepath.Join(c.cfmFolder(), cfmFileLock), cfmLockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("fail to lock cfm : %s", err.Error())
+	}
+	return lock, nil
+}
+
+// audit append record to audit.log (json lines). it is called with cfm lock
+func (c *CentralFilebaseManagement) audit(record monitor.StatusAuditRecord) {
+	record.Time = time.Now()
+	if len(record.Actor) == 0 {
+		record.Actor = cfmUnknownActor
+	}
+	log.Warn("cfm %s %s changed. %s -> %s by %s", record.Target, record.Process, record.From, record.To, record.Actor)
+
+	path := filepath.Join(c.cfmFolder(), cfmFileAudit)
+	if info, err := os.Stat(path); err == nil && info.Size() > cfmAuditMaxSize {
+		_ = os.Rename(path, path+".1")
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		log.Warn("fail to write cfm audit : %s", err.Error())
+		return
+	}
+	defer file.Close()
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		log.Warn("fail to write cfm audit : %s", err.Error())
+	}
+}
+
+func (c *CentralFilebaseManagement) GetAuditRecords(limit int) ([]monitor.StatusAuditRecord, error) {
+	records := make([]monitor.StatusAuditRecord, 0)
+	file, err := os.Open(filepath.Join(c.cfmFolder(), cfmFileAudit))
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := monitor.StatusAuditRecord{}
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		records = append(records, record)
+		if limit > 0 && len(records) > limit {
+			records = records[1:]
+		}
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오후 2:00
+ */
+
+package infra
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCfmSetStatus(t *testing.T) {
+	home := t.TempDir()
+	cfm := newCentralFilebaseManagement(testCfmEnv{home: home})
+
+	require.NoError(t, cfm.SetHAStatus(monitor.HA_STATUS_ACTIVE, "operator"))
+	require.NoError(t, cfm.SetPSStatus(monitor.PS_STATUS_PRIMARY, "operator"))
+	// same value is not recorded
+	require.NoError(t, cfm.SetHAStatus(monitor.HA_STATUS_ACTIVE, "operator"))
+	require.NoError(t, cfm.SetHAStatus(monitor.HA_STATUS_STANDBY, ""))
+	assert.Error(t, cfm.SetHAStatus(monitor.HA_STATUS_UNKNOWN, "operator"))
+
+	ha, ok := cfm.GetHAStatus()
+	assert.True(t, ok)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_STANDBY), ha)
+	ps, ok := cfm.GetPSStatus()
+	assert.True(t, ok)
+	assert.Equal(t, monitor.PSStatus(monitor.PS_STATUS_PRIMARY), ps)
+
+	records, err := cfm.GetAuditRecords(0)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, monitor.StatusAuditRecord{Time: records[0].Time, Actor: "operator", Target: cfmFileHA, From: "Unknown", To: "Active"}, records[0])
+	assert.Equal(t, cfmFilePS, records[1].Target)
+	assert.Equal(t, monitor.StatusAuditRecord{Time: records[2].Time, Actor: cfmUnknownActor, Target: cfmFileHA, From: "Active", To: "Standby"}, records[2])
+
+	last, err := cfm.GetAuditRecords(1)
+	require.NoError(t, err)
+	assert.Equal(t, records[2:], last)
+}
+
+func TestCfmSetSystemStatus(t *testing.T) {
+	home := t.TempDir()
+	cfm := newCentralFilebaseManagement(testCfmEnv{home: home})
+	require.NoError(t, cfm.SetHAStatus(monitor.HA_STATUS_STANDBY, "operator"))
+
+	ha, ps, level := monitor.HAStatus(monitor.HA_STATUS_ACTIVE), monitor.PSStatus(monitor.PS_STATUS_PRIMARY), log.LogLevel(log.LOG_DEBUG)
+	change := monitor.SystemStatusChange{HA: &ha, PS: &ps, Process: "mypgm", LogLevel: &level}
+
+	// system.ps cannot be written. HA change is restored and nothing is audited
+	psPath := filepath.Join(cfm.haFolder(), cfmFilePS)
+	require.NoError(t, os.MkdirAll(filepath.Join(psPath, "blocked"), 0755))
+	assert.Error(t, cfm.SetSystemStatus(change, "operator"))
+	current, _ := cfm.GetHAStatus()
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_STANDBY), current)
+	_, err := os.Stat(filepath.Join(cfm.cfmFolder(), cfmFileLogLevels))
+	assert.True(t, os.IsNotExist(err))
+	records, err := cfm.GetAuditRecords(0)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	require.NoError(t, os.RemoveAll(psPath))
+	require.NoError(t, cfm.SetSystemStatus(change, "operator"))
+	snapshot, err := cfm.GetSystemStatusSnapshot()
+	require.NoError(t, err)
+	assert.Equal(t, monitor.SystemStatusSnapshot{HA: "Active", PS: "Primary", LogLevels: map[string]string{"mypgm": "DEBUG"}}, snapshot)
+	records, err = cfm.GetAuditRecords(0)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{cfmFileHA, cfmFilePS, cfmFileLogLevels}, []string{records[1].Target, records[2].Target, records[3].Target})
+}
+
+func TestCfmSetLogLevel(t *testing.T) {
+	home := t.TempDir()
+	cfm := newCentralFilebaseManagement(testCfmEnv{home: home})
+	require.NoError(t, os.MkdirAll(cfm.cfmFolder(), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(cfm.cfmFolder(), cfmFileLogLevels), []byte(`{"other":"0x1F"}`), 0644))
+
+	// concurrent changes of different processes are not lost
+	var wg sync.WaitGroup
+	for _, proc := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, cfm.SetLogLevel(proc, log.LOG_DEBUG, "operator"))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, cfm.SetLogLevel("other", log.LOG_NONE, "operator"))
+	assert.Error(t, cfm.SetLogLevel("", log.LOG_DEBUG, "operator"))
+
+	data, err := os.ReadFile(filepath.Join(cfm.cfmFolder(), cfmFileLogLevels))
+	require.NoError(t, err)
+	items := map[string]string{}
+	require.NoError(t, json.Unmarshal(data, &items))
+	assert.Equal(t, map[string]string{"a": "0x2F", "b": "0x2F", "c": "0x2F", "d": "0x2F"}, items)
+
+	snapshot, err := cfm.GetSystemStatusSnapshot()
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", snapshot.LogLevels["a"])
+
+	records, err := cfm.GetAuditRecords(0)
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, "other", records[4].Process)
+	assert.Equal(t, "INFO", records[4].From)
+	assert.Equal(t, "", records[4].To)
+}
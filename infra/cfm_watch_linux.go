@@ -0,0 +1,101 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 27. 오전 10:00
+ */
+
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	inotifyWatchMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM |
+		syscall.IN_DELETE | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+)
+
+// watchFolders watch files of folders with inotify. changed is called when one of files is changed
+// and failed is called (once) when watch is broken (e.g. folder is removed)
+func watchFolders(folders []string, files map[string]bool, changed func(), failed func(error)) (io.Closer, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init : %s", err.Error())
+	}
+	for _, folder := range folders {
+		if _, err = syscall.InotifyAddWatch(fd, folder, inotifyWatchMask); err != nil {
+			_ = syscall.Close(fd)
+			return nil, fmt.Errorf("inotify watch %s : %s", folder, err.Error())
+		}
+	}
+
+	// non-blocking fd is registered to runtime poller so that Close unblocks Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	go readInotify(file, files, changed, failed)
+	return file, nil
+}
+
+func readInotify(file *os.File, files map[string]bool, changed func(), failed func(error)) {
+	buff := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := file.Read(buff)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				failed(fmt.Errorf("inotify read : %s", err.Error()))
+			}
+			return
+		}
+
+		notify := false
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buff[offset]))
+			nameBytes := buff[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			switch {
+			case event.Mask&syscall.IN_Q_OVERFLOW != 0:
+				notify = true
+			case event.Mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF|syscall.IN_IGNORED) != 0:
+				_ = file.Close()
+				failed(errors.New("watching folder is removed"))
+				return
+			case files[trimNull(nameBytes)]:
+				notify = true
+			}
+		}
+		if notify {
+			changed()
+		}
+	}
+}
+
+func trimNull(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,70 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 27. 오전 11:30
+ */
+
+package infra
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCfmWatch(t *testing.T) {
+	home := t.TempDir()
+	haFolder := filepath.Join(home, "package", "cfm", "ha")
+	require.NoError(t, os.MkdirAll(haFolder, 0755))
+
+	cfm := newCentralFilebaseManagement(testCfmEnv{home: home})
+	var changes atomic.Int32
+	require.NoError(t, cfm.Watch(func() { changes.Add(1) }))
+	defer cfm.Close()
+	assert.True(t, cfm.Watching())
+
+	// other files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(haFolder, "other"), []byte("1"), 0644))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), changes.Load())
+
+	require.NoError(t, os.WriteFile(filepath.Join(haFolder, cfmFileHA), []byte("1\n"), 0644))
+	require.Eventually(t, func() bool { return changes.Load() > 0 }, time.Second, 5*time.Millisecond)
+	ha, ok := cfm.GetHAStatus()
+	assert.True(t, ok)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_ACTIVE), ha)
+
+	// atomic replace (rename) is detected
+	before := changes.Load()
+	tmp := filepath.Join(home, "system.ps.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("1"), 0644))
+	require.NoError(t, os.Rename(tmp, filepath.Join(haFolder, cfmFilePS)))
+	require.Eventually(t, func() bool { return changes.Load() > before }, time.Second, 5*time.Millisecond)
+
+	// removing folder stops watch
+	require.NoError(t, os.RemoveAll(haFolder))
+	require.Eventually(t, func() bool { return !cfm.Watching() }, time.Second, 5*time.Millisecond)
+}
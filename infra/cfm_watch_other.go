@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 27. 오전 10:00
+ */
+
+package infra
+
+import (
+	"errors"
+	"io"
+)
+
+// watchFolders file watch is supported only on linux (inotify). status is polled on other platforms
+func watchFolders(folders []string, files map[string]bool, changed func(), failed func(error)) (io.Closer, error) {
+	return nil, errors.New("file watch is not supported on this platform")
+}
@@ -23,6 +23,7 @@ package infra
 import (
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatima-go/fatima-core"
@@ -36,38 +37,60 @@ type ProcessCoreWorker interface {
 	Process()
 }
 
-var oneSecondTickWorkers []ProcessCoreWorker
-var fiveSecondTickWorkers []ProcessCoreWorker
+var oneSecondTickWorkers []*tickWorker
+var measureTickWorkers []*tickWorker
 
 func init() {
-	oneSecondTickWorkers = make([]ProcessCoreWorker, 0)
-	fiveSecondTickWorkers = make([]ProcessCoreWorker, 0)
+	oneSecondTickWorkers = make([]*tickWorker, 0)
+	measureTickWorkers = make([]*tickWorker, 0)
+}
+
+// systemStatusMonitorProvider runtime builder which provides custom SystemStatusMonitor
+type systemStatusMonitorProvider interface {
+	GetSystemStatusMonitor() monitor.SystemStatusMonitor
 }
 
 type DefaultProcessInteractor struct {
 	runtimeProcess *builder.FatimaRuntimeProcess
 	awareManager   *SystemAwareManagement
 	monitor        monitor.SystemStatusMonitor
+	statusManager  monitor.SystemStatusManager
 	measurement    *SystemMeasureManagement
 	readers        []fatima.FatimaIOReader
+	metricsServer  *http.Server
 }
 
 func NewProcessInteractor(runtimeProcess *builder.FatimaRuntimeProcess) *DefaultProcessInteractor {
 	instance := new(DefaultProcessInteractor)
 	instance.runtimeProcess = runtimeProcess
-	// monitor : Active/Standby, Primary/Secondary
-	instance.monitor = newCentralFilebaseManagement(runtimeProcess.GetEnv())
+	// monitor : Active/Standby, Primary/Secondary. cfm files are used unless builder provides monitor
+	if provider, ok := runtimeProcess.GetBuilder().(systemStatusMonitorProvider); ok && provider.GetSystemStatusMonitor() != nil {
+		instance.monitor = provider.GetSystemStatusMonitor()
+	} else {
+		instance.monitor = newCentralFilebaseManagement(runtimeProcess.GetEnv())
+	}
+	// cfm status is changed by monitor itself when it supports (e.g. LeaderElectionMonitor)
+	if manager, ok := instance.monitor.(monitor.SystemStatusManager); ok {
+		instance.statusManager = manager
+	} else {
+		instance.statusManager = newCentralFilebaseManagement(runtimeProcess.GetEnv())
+	}
 	instance.awareManager = newSystemAwareManagement(runtimeProcess, instance.monitor)
+	instance.awareManager.notifyHandler = runtimeProcess.GetSystemNotifyHandler()
+	if config := runtimeProcess.GetConfig(); config != nil {
+		instance.awareManager.SetHATransitionTimeout(config.GetDurationOrDefault(builder.GofatimaHATransitionTimeout, defaultHATransitionTimeout))
+	}
 	// special type of FatimaComponent. usually we need create 'Reader' type first
 	instance.readers = make([]fatima.FatimaIOReader, 0)
 	instance.measurement = newSystemMeasureManagement(runtimeProcess)
+	instance.measurement.registry.RegisterCollector(newRuntimeMetricCollector(runtimeProcess.GetSystemNotifyHandler()))
 
-	// check HA/PS status every 1 second
-	oneSecondTickWorkers = append(oneSecondTickWorkers, instance.awareManager)
-	// process mgmt every 5 seconds
-	fiveSecondTickWorkers = append(fiveSecondTickWorkers, instance.measurement)
+	// check HA/PS status every 1 second (when status is not watched)
+	oneSecondTickWorkers = append(oneSecondTickWorkers, newTickWorker("system aware", instance.awareManager))
+	// process mgmt every measure interval (default 5 seconds)
+	measureTickWorkers = append(measureTickWorkers, newTickWorker("measurement", instance.measurement))
 
-	startTickers()
+	startTickers(instance.measurement.interval)
 	return instance
 }
 
@@ -79,6 +102,10 @@ func (i *DefaultProcessInteractor) Register(component fatima.FatimaComponent) {
 		i.RegisterSystemHAAware(comp)
 	}
 
+	if comp, ok := component.(monitor.FatimaSystemHATransitionAware); ok {
+		i.awareManager.RegisterSystemHATransitionAware(comp)
+	}
+
 	if comp, ok := component.(monitor.FatimaSystemPSAware); ok {
 		i.RegisterSystemPSAware(comp)
 	}
@@ -96,6 +123,11 @@ func (i *DefaultProcessInteractor) RegisterSystemPSAware(aware monitor.FatimaSys
 	i.awareManager.RegisterSystemPSAware(aware)
 }
 
+// GetSystemStatusManager return manager which changes HA, PS status and log levels in cfm
+func (i *DefaultProcessInteractor) GetSystemStatusManager() monitor.SystemStatusManager {
+	return i.statusManager
+}
+
 func (i *DefaultProcessInteractor) Initialize() bool {
 	return initializeComponent()
 }
@@ -126,6 +158,8 @@ func (i *DefaultProcessInteractor) Run() {
 
 	// start pprof service if relative property exists
 	i.pprofService()
+	// start metrics service if relative property exists
+	i.metricsService()
 	if i.runtimeProcess.GetBuilder().GetProcessType() == fatima.PROCESS_TYPE_GENERAL {
 		message := fmt.Sprintf("%s process started", i.runtimeProcess.GetEnv().GetSystemProc().GetProgramName())
 		i.runtimeProcess.GetSystemNotifyHandler().SendAlarm(monitor.AlarmLevelMinor, monitor.ActionProcessStartup, message)
@@ -142,6 +176,8 @@ func (i *DefaultProcessInteractor) Shutdown() {
 		i.runtimeProcess.GetSystemNotifyHandler().SendAlarm(monitor.AlamLevelMajor, monitor.ActionProcessShutdown, message)
 	}
 	lib.StopCron()
+	i.stopMetricsService()
+	i.awareManager.close()
 	shutdownComponent(i.runtimeProcess.GetEnv().GetSystemProc().GetProgramName())
 }
 
@@ -149,6 +185,10 @@ func (i *DefaultProcessInteractor) RegisterMeasureUnit(unit monitor.SystemMeasur
 	i.measurement.registerUnit(unit)
 }
 
+func (i *DefaultProcessInteractor) GetMetricRegistry() monitor.MetricRegistry {
+	return i.measurement.registry
+}
+
 func (i *DefaultProcessInteractor) pprofService() {
 	addr, ok := i.runtimeProcess.GetConfig().GetValue(builder.GofatimaPropPprofAddress)
 	if ok {
@@ -162,27 +202,53 @@ func (i *DefaultProcessInteractor) pprofService() {
 	}
 }
 
-func startTickers() {
+func startTickers(measureInterval time.Duration) {
 	oneSecondTick := time.NewTicker(time.Second * 1)
 	go func() {
 		for range oneSecondTick.C {
 			iterateWorkers(oneSecondTickWorkers)
 		}
 	}()
-	fiveSecondTick := time.NewTicker(time.Second * 5)
+	measureTick := time.NewTicker(measureInterval)
 	go func() {
-		for range fiveSecondTick.C {
-			iterateWorkers(fiveSecondTickWorkers)
+		for range measureTick.C {
+			iterateWorkers(measureTickWorkers)
 		}
 	}()
 }
 
-func iterateWorkers(workers []ProcessCoreWorker) {
-	if workers == nil || len(workers) < 1 {
-		return
+// iterateWorkers every worker runs on its own goroutine so that slow worker cannot delay others
+func iterateWorkers(workers []*tickWorker) {
+	for _, v := range workers {
+		v.tick()
 	}
+}
 
-	for _, v := range workers {
-		v.Process()
+func newTickWorker(name string, worker ProcessCoreWorker) *tickWorker {
+	return &tickWorker{name: name, worker: worker}
+}
+
+// tickWorker tick is skipped while previous Process is running
+type tickWorker struct {
+	name    string
+	worker  ProcessCoreWorker
+	running atomic.Bool
+	skipped atomic.Uint64
+}
+
+func (w *tickWorker) tick() {
+	if !w.running.CompareAndSwap(false, true) {
+		w.skipped.Add(1)
+		log.Debug("worker [%s] is still running. skip tick", w.name)
+		return
 	}
+	go func() {
+		defer w.running.Store(false)
+		defer func() {
+			if r := recover(); r != nil {
+				log.Warn("panic in worker [%s] : %v", w.name, r)
+			}
+		}()
+		w.worker.Process()
+	}()
 }
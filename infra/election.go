@@ -0,0 +1,355 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 28. 오전 10:00
+ */
+
+package infra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/lib"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	defaultLeaseTTL         = 10 * time.Second
+	defaultLeaseLockTimeout = time.Second
+)
+
+var errPSOwnedByElection = errors.New("PS status is decided by leader election")
+
+// LeaderElectionOption option of leader election
+type LeaderElectionOption struct {
+	// LeaseFile lease file on storage shared by peer hosts (e.g. NFS). lock file (LeaseFile + ".lock") is created in same folder
+	LeaseFile string
+	// Id candidate id. default hostname/pid so that processes on one machine are different candidates
+	Id string
+	// TTL lease is taken over when it is not renewed for TTL. default 10s
+	TTL time.Duration
+	// RenewInterval heartbeat interval. default TTL/3
+	RenewInterval time.Duration
+	// LockTimeout timeout to acquire lock of lease file. default 1s
+	LockTimeout time.Duration
+	// UpdateCfm write elected PS status to cfm system.ps for juno and other processes
+	UpdateCfm bool
+}
+
+// leaderLease content of lease file
+type leaderLease struct {
+	Holder  string `json:"holder"`
+	Token   uint64 `json:"token"`
+	Renewal uint64 `json:"renewal"`
+	Time    string `json:"time"` // informational. expiry is decided by local clock of each candidate
+}
+
+// LeaderElectionMonitor SystemStatusMonitor which decides PS status by leader election among peers sharing lease file.
+// HA status and log level come from cfm files.
+//
+// holder renews lease every RenewInterval. other candidates take over lease when they observe it unchanged for TTL
+// (by their own clock, so clock skew between hosts does not matter). holder which fails to renew steps down
+// before others can take over. fencing token is increased whenever holder is changed
+type LeaderElectionMonitor struct {
+	*CentralFilebaseManagement
+	option     LeaderElectionOption
+	mutex      sync.Mutex
+	primary    bool
+	token      uint64
+	renewedAt  time.Time
+	observed   leaderLease
+	observedAt time.Time
+	stepDown   *time.Timer
+	changed    func()
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewLeaderElectionMonitor create leader election monitor. election starts immediately and process is SECONDARY until elected.
+// set it with runtime.SetSystemStatusMonitor before getting fatima runtime
+func NewLeaderElectionMonitor(env fatima.FatimaEnv, option LeaderElectionOption) (*LeaderElectionMonitor, error) {
+	if len(option.LeaseFile) == 0 {
+		return nil, errors.New("lease file is not defined")
+	}
+	if len(option.Id) == 0 {
+		hostname, _ := os.Hostname()
+		option.Id = fmt.Sprintf("%s/%d", hostname, os.Getpid())
+	}
+	if option.TTL <= 0 {
+		option.TTL = defaultLeaseTTL
+	}
+	if option.RenewInterval <= 0 {
+		option.RenewInterval = option.TTL / 3
+	}
+	if option.RenewInterval >= option.TTL {
+		return nil, fmt.Errorf("renew interval %s should be less than ttl %s", option.RenewInterval, option.TTL)
+	}
+	if option.LockTimeout <= 0 {
+		option.LockTimeout = defaultLeaseLockTimeout
+	}
+	if err := os.MkdirAll(filepath.Dir(option.LeaseFile), 0755); err != nil {
+		return nil, err
+	}
+
+	m := &LeaderElectionMonitor{
+		CentralFilebaseManagement: newCentralFilebaseManagement(env),
+		option:                    option,
+		stop:                      make(chan struct{}),
+		done:                      make(chan struct{}),
+	}
+	m.elect()
+	go m.run()
+	return m, nil
+}
+
+func (m *LeaderElectionMonitor) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.option.RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.elect()
+		}
+	}
+}
+
+// elect read lease and renew, take over or follow it
+func (m *LeaderElectionMonitor) elect() {
+	lock, err := lib.LockFile(m.option.LeaseFile+".lock", m.option.LockTimeout)
+	if err != nil {
+		m.setRole(false, 0, fmt.Errorf("fail to lock lease : %s", err.Error()))
+		return
+	}
+	defer lock.Unlock()
+
+	lease, err := readLeaderLease(m.option.LeaseFile)
+	if err != nil {
+		m.setRole(false, 0, err)
+		return
+	}
+
+	now := time.Now()
+	m.mutex.Lock()
+	if lease != m.observed || m.observedAt.IsZero() {
+		m.observed, m.observedAt = lease, now
+	}
+	expired := now.Sub(m.observedAt) >= m.option.TTL
+	m.mutex.Unlock()
+
+	switch {
+	case lease.Holder == m.option.Id:
+		lease.Renewal++
+	case len(lease.Holder) == 0 || expired:
+		if len(lease.Holder) > 0 {
+			log.Warn("leader lease of %s is expired. take over", lease.Holder)
+		}
+		lease = leaderLease{Holder: m.option.Id, Token: lease.Token + 1}
+	default:
+		m.setRole(false, 0, nil)
+		return
+	}
+
+	lease.Time = now.Format(time.RFC3339)
+	if err = writeLeaderLease(m.option.LeaseFile, lease); err != nil {
+		m.setRole(false, 0, fmt.Errorf("fail to write lease : %s", err.Error()))
+		return
+	}
+	m.mutex.Lock()
+	m.observed, m.observedAt, m.renewedAt = lease, now, now
+	m.armStepDown(now)
+	m.mutex.Unlock()
+	m.setRole(true, lease.Token, nil)
+}
+
+// armStepDown step down when lease is not renewed until others may take it over (e.g. slow storage, delayed tick).
+// it is called with mutex
+func (m *LeaderElectionMonitor) armStepDown(renewedAt time.Time) {
+	if m.stepDown != nil {
+		m.stepDown.Stop()
+	}
+	m.stepDown = time.AfterFunc(m.option.TTL-m.option.RenewInterval, func() {
+		m.mutex.Lock()
+		stale := m.primary && m.renewedAt.Equal(renewedAt)
+		if stale {
+			m.primary, m.token = false, 0
+		}
+		notify := m.changed
+		m.mutex.Unlock()
+
+		if stale {
+			log.Warn("leader election : lease is not renewed in %s", m.option.TTL-m.option.RenewInterval)
+			m.roleChanged(false, 0, notify)
+		}
+	})
+}
+
+func (m *LeaderElectionMonitor) setRole(primary bool, token uint64, err error) {
+	if err != nil {
+		log.Warn("leader election : %s", err.Error())
+	}
+
+	m.mutex.Lock()
+	changed := m.primary != primary
+	m.primary, m.token = primary, token
+	notify := m.changed
+	m.mutex.Unlock()
+
+	if changed {
+		m.roleChanged(primary, token, notify)
+	}
+}
+
+// roleChanged log, write cfm and notify changed role
+func (m *LeaderElectionMonitor) roleChanged(primary bool, token uint64, notify func()) {
+	if primary {
+		log.Warn("elected as PS primary. id=%s, token=%d", m.option.Id, token)
+	} else {
+		log.Warn("PS primary is lost. id=%s", m.option.Id)
+	}
+	if m.option.UpdateCfm {
+		m.writeCfmPS(primary)
+	}
+	if notify != nil {
+		notify()
+	}
+}
+
+// isPrimary primary only while lease is surely not taken over by others
+func (m *LeaderElectionMonitor) isPrimary() bool {
+	return m.primary && time.Since(m.renewedAt) < m.option.TTL-m.option.RenewInterval
+}
+
+func (m *LeaderElectionMonitor) writeCfmPS(primary bool) {
+	var status monitor.PSStatus = monitor.PS_STATUS_SECONDARY
+	if primary {
+		status = monitor.PS_STATUS_PRIMARY
+	}
+	err := m.CentralFilebaseManagement.SetPSStatus(status, "election:"+m.option.Id)
+	if err != nil {
+		log.Warn("fail to write cfm PS status : %s", err.Error())
+	}
+}
+
+// SetPSStatus PS status is owned by election. system.ps written by operator would be ignored
+func (m *LeaderElectionMonitor) SetPSStatus(status monitor.PSStatus, actor string) error {
+	return errPSOwnedByElection
+}
+
+// SetSystemStatus change HA and log level. PS status is owned by election
+func (m *LeaderElectionMonitor) SetSystemStatus(change monitor.SystemStatusChange, actor string) error {
+	if change.PS != nil {
+		return errPSOwnedByElection
+	}
+	return m.CentralFilebaseManagement.SetSystemStatus(change, actor)
+}
+
+// GetPSStatus PRIMARY if this process holds lease
+func (m *LeaderElectionMonitor) GetPSStatus() (monitor.PSStatus, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.isPrimary() {
+		return monitor.PS_STATUS_PRIMARY, true
+	}
+	return monitor.PS_STATUS_SECONDARY, true
+}
+
+func (m *LeaderElectionMonitor) GetFencingToken() (uint64, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.isPrimary() {
+		return m.token, true
+	}
+	return 0, false
+}
+
+// Watch notify election result and cfm (HA, loglevel) changes
+func (m *LeaderElectionMonitor) Watch(changed func()) error {
+	m.mutex.Lock()
+	m.changed = changed
+	m.mutex.Unlock()
+	return m.CentralFilebaseManagement.Watch(changed)
+}
+
+// Close stop election and release lease if this process is primary
+func (m *LeaderElectionMonitor) Close() error {
+	select {
+	case <-m.stop:
+		return nil
+	default:
+		close(m.stop)
+	}
+	<-m.done
+	_ = m.CentralFilebaseManagement.Close()
+
+	m.mutex.Lock()
+	primary := m.primary
+	if m.stepDown != nil {
+		m.stepDown.Stop()
+	}
+	m.mutex.Unlock()
+	if !primary {
+		return nil
+	}
+
+	lock, err := lib.LockFile(m.option.LeaseFile+".lock", m.option.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	lease, err := readLeaderLease(m.option.LeaseFile)
+	if err == nil && lease.Holder == m.option.Id {
+		// keep token so that next holder gets greater token
+		lease.Holder, lease.Time = "", time.Now().Format(time.RFC3339)
+		err = writeLeaderLease(m.option.LeaseFile, lease)
+	}
+	m.setRole(false, 0, nil)
+	return err
+}
+
+func readLeaderLease(path string) (leaderLease, error) {
+	lease := leaderLease{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return lease, nil
+	}
+	if err != nil {
+		return lease, fmt.Errorf("fail to read lease : %s", err.Error())
+	}
+	if err = json.Unmarshal(data, &lease); err != nil {
+		return lease, fmt.Errorf("invalid lease %s : %s", path, err.Error())
+	}
+	return lease, nil
+}
+
+func writeLeaderLease(path string, lease leaderLease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+	return lib.WriteFileAtomic(path, data, 0644)
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 28. 오전 11:00
+ */
+
+package infra
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/lib"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	envElectionHelper = "FATIMA_TEST_ELECTION_LEASE"
+	testLeaseTTL      = 300 * time.Millisecond
+	testLeaseRenew    = 50 * time.Millisecond
+)
+
+func newTestElection(t *testing.T, home, lease, id string) *LeaderElectionMonitor {
+	m, err := NewLeaderElectionMonitor(testCfmEnv{home: home}, LeaderElectionOption{
+		LeaseFile:     lease,
+		Id:            id,
+		TTL:           testLeaseTTL,
+		RenewInterval: testLeaseRenew,
+		LockTimeout:   testLeaseRenew,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+func isPrimary(m *LeaderElectionMonitor) bool {
+	ps, _ := m.GetPSStatus()
+	return ps == monitor.PS_STATUS_PRIMARY
+}
+
+func TestLeaderElection(t *testing.T) {
+	home := t.TempDir()
+	lease := filepath.Join(home, "shared", "leader.lease")
+
+	first := newTestElection(t, home, lease, "first")
+	second := newTestElection(t, home, lease, "second")
+	assert.True(t, isPrimary(first))
+	assert.False(t, isPrimary(second))
+	token, ok := first.GetFencingToken()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), token)
+	_, ok = second.GetFencingToken()
+	assert.False(t, ok)
+
+	// holder keeps lease by renewing
+	time.Sleep(2 * testLeaseTTL)
+	assert.True(t, isPrimary(first))
+	assert.False(t, isPrimary(second))
+
+	// released lease is taken immediately with greater token
+	changed := make(chan struct{}, 4)
+	_ = second.Watch(func() { changed <- struct{}{} })
+	require.NoError(t, first.Close())
+	assert.False(t, isPrimary(first))
+	require.Eventually(t, func() bool { return isPrimary(second) }, testLeaseTTL, 10*time.Millisecond)
+	token, _ = second.GetFencingToken()
+	assert.Equal(t, uint64(2), token)
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("election change is not notified")
+	}
+}
+
+func TestLeaderElectionOwnsPS(t *testing.T) {
+	home := t.TempDir()
+	m := newTestElection(t, home, filepath.Join(home, "leader.lease"), "first")
+
+	assert.ErrorIs(t, m.SetPSStatus(monitor.PS_STATUS_SECONDARY, "operator"), errPSOwnedByElection)
+	ha, ps := monitor.HAStatus(monitor.HA_STATUS_ACTIVE), monitor.PSStatus(monitor.PS_STATUS_SECONDARY)
+	assert.ErrorIs(t, m.SetSystemStatus(monitor.SystemStatusChange{HA: &ha, PS: &ps}, "operator"), errPSOwnedByElection)
+	current, _ := m.GetHAStatus()
+	assert.NotEqual(t, ha, current)
+
+	require.NoError(t, m.SetHAStatus(ha, "operator"))
+	current, _ = m.GetHAStatus()
+	assert.Equal(t, ha, current)
+	assert.True(t, isPrimary(m))
+}
+
+func TestLeaderElectionStepDown(t *testing.T) {
+	home := t.TempDir()
+	lease := filepath.Join(home, "leader.lease")
+	m, err := NewLeaderElectionMonitor(testCfmEnv{home: home}, LeaderElectionOption{
+		LeaseFile:     lease,
+		Id:            "first",
+		TTL:           testLeaseTTL,
+		RenewInterval: testLeaseRenew,
+		LockTimeout:   10 * testLeaseTTL,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	require.True(t, isPrimary(m))
+	changed := make(chan struct{}, 4)
+	_ = m.Watch(func() { changed <- struct{}{} })
+
+	// renewal is stalled (e.g. slow storage). primary steps down before lock timeout
+	lock, err := lib.LockFile(lease+".lock", time.Second)
+	require.NoError(t, err)
+	select {
+	case <-changed:
+	case <-time.After(2 * testLeaseTTL):
+		t.Fatal("step down is not notified")
+	}
+	m.mutex.Lock()
+	assert.False(t, m.primary)
+	m.mutex.Unlock()
+	assert.False(t, isPrimary(m))
+	_, ok := m.GetFencingToken()
+	assert.False(t, ok)
+
+	// elected again after storage is recovered
+	require.NoError(t, lock.Unlock())
+	require.Eventually(t, func() bool { return isPrimary(m) }, 20*testLeaseTTL, 10*time.Millisecond)
+}
+
+func TestLeaderElectionStaleLease(t *testing.T) {
+	home := t.TempDir()
+	lease := filepath.Join(home, "leader.lease")
+	require.NoError(t, writeLeaderLease(lease, leaderLease{Holder: "dead", Token: 5}))
+
+	m := newTestElection(t, home, lease, "alive")
+	assert.False(t, isPrimary(m), "lease is not expired yet")
+	require.Eventually(t, func() bool { return isPrimary(m) }, 3*testLeaseTTL, 10*time.Millisecond)
+	token, _ := m.GetFencingToken()
+	assert.Equal(t, uint64(6), token)
+
+	_, err := NewLeaderElectionMonitor(testCfmEnv{home: home}, LeaderElectionOption{})
+	assert.Error(t, err)
+	_, err = NewLeaderElectionMonitor(testCfmEnv{home: home}, LeaderElectionOption{LeaseFile: lease, TTL: time.Second, RenewInterval: time.Second})
+	assert.Error(t, err)
+}
+
+// TestLeaderElectionHelperProcess candidate process for TestLeaderElectionProcesses
+func TestLeaderElectionHelperProcess(t *testing.T) {
+	lease := os.Getenv(envElectionHelper)
+	if len(lease) == 0 {
+		t.Skip("helper process")
+	}
+	home := filepath.Dir(lease)
+	m, err := NewLeaderElectionMonitor(testCfmEnv{home: home}, LeaderElectionOption{
+		LeaseFile:     lease,
+		TTL:           testLeaseTTL,
+		RenewInterval: testLeaseRenew,
+		UpdateCfm:     false,
+	})
+	if err != nil {
+		os.Exit(1)
+	}
+	// report role until killed
+	role := filepath.Join(home, fmt.Sprintf("%d.role", os.Getpid()))
+	for {
+		token, ok := m.GetFencingToken()
+		_ = os.WriteFile(role, []byte(fmt.Sprintf("%t %d", ok, token)), 0644)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestLeaderElectionProcesses local test mode : candidates are processes on this machine sharing lease file
+func TestLeaderElectionProcesses(t *testing.T) {
+	if os.Getenv(envElectionHelper) != "" {
+		t.Skip("in helper process")
+	}
+	home := t.TempDir()
+	lease := filepath.Join(home, "leader.lease")
+
+	// each candidate has own program name. fatima process refuses to run 2 instances of same program
+	binary, err := os.Executable()
+	require.NoError(t, err)
+	candidates := make(map[int]*exec.Cmd)
+	for i := 0; i < 3; i++ {
+		program := filepath.Join(home, fmt.Sprintf("candidate%d", i))
+		require.NoError(t, os.Symlink(binary, program))
+		cmd := exec.Command(program, "-test.run=^TestLeaderElectionHelperProcess$")
+		cmd.Env = append(os.Environ(), envElectionHelper+"="+lease)
+		require.NoError(t, cmd.Start())
+		candidates[cmd.Process.Pid] = cmd
+	}
+	t.Cleanup(func() {
+		for _, cmd := range candidates {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}
+	})
+
+	primaries := func() map[int]string {
+		found := make(map[int]string)
+		for pid := range candidates {
+			b, _ := os.ReadFile(filepath.Join(home, fmt.Sprintf("%d.role", pid)))
+			if strings.HasPrefix(string(b), "true ") {
+				found[pid] = strings.TrimPrefix(string(b), "true ")
+			}
+		}
+		return found
+	}
+
+	var leader int
+	require.Eventually(t, func() bool {
+		p := primaries()
+		for pid := range p {
+			leader = pid
+		}
+		return len(p) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "1", primaries()[leader])
+
+	// crashed primary (no release) is taken over after ttl by one of others
+	require.NoError(t, candidates[leader].Process.Signal(syscall.SIGKILL))
+	_ = candidates[leader].Wait()
+	delete(candidates, leader)
+	require.Eventually(t, func() bool {
+		p := primaries()
+		return len(p) == 1 && p[leader] == ""
+	}, 10*time.Second, 10*time.Millisecond)
+	for _, token := range primaries() {
+		assert.Equal(t, "2", token)
+	}
+}
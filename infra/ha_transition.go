@@ -0,0 +1,300 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오전 10:00
+ */
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/lib"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	defaultHATransitionTimeout = 30 * time.Second
+	haTransitionFileSuffix     = ".transition"
+
+	haPhasePrepare = "prepare"
+	haPhaseCommit  = "commit"
+	haPhaseAbort   = "abort"
+)
+
+// transitHA run prepare -> commit (or abort) on transition participants and notify HA aware components.
+// runtime HA status is changed after commit phase. it is called by delivery goroutine so transitions are serialized
+func (s *SystemAwareManagement) transitHA(to monitor.HAStatus) {
+	currentStatus := s.runtimeProcess.GetSystemStatus().(*builder.FatimaPackageSystemStatus)
+	from := currentStatus.GetHAStatus()
+	if from == to {
+		s.clearPendingHA(to)
+		return
+	}
+
+	result := s.runHATransition(from, to)
+
+	s.haMutex.Lock()
+	if result.Outcome == monitor.HATransitionAborted {
+		// process stays in previous status until cfm status is changed again
+		s.abortedHA, s.haAborted = to, true
+	} else {
+		currentStatus.SetHAStatus(to)
+	}
+	if s.haPending && s.pendingHA == to {
+		s.haPending = false
+	}
+	s.haMutex.Unlock()
+
+	if result.Outcome != monitor.HATransitionAborted {
+		s.SystemHAStatusChanged(to)
+	}
+	s.reportHATransition(result)
+}
+
+func (s *SystemAwareManagement) clearPendingHA(to monitor.HAStatus) {
+	s.haMutex.Lock()
+	defer s.haMutex.Unlock()
+	if s.haPending && s.pendingHA == to {
+		s.haPending = false
+	}
+}
+
+func (s *SystemAwareManagement) runHATransition(from, to monitor.HAStatus) monitor.HATransitionResult {
+	result := monitor.HATransitionResult{
+		Process: s.programName(),
+		From:    from.String(),
+		To:      to.String(),
+		Start:   time.Now(),
+	}
+	participants := s.awareTransition
+	if len(participants) == 0 {
+		result.Outcome = monitor.HATransitionCommitted
+		result.End = time.Now()
+		return result
+	}
+
+	log.Warn("HA transition %s -> %s. participants=%d", from, to, len(participants))
+	result.Participants = make([]monitor.HATransitionParticipant, len(participants))
+	for i, p := range participants {
+		result.Participants[i].Name = participantName(p)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.transitionTimeout)
+	defer cancel()
+
+	finished, cause := runHAPhase(ctx, participants, result.Participants, haPhasePrepare, func(ctx context.Context, p monitor.FatimaSystemHATransitionAware) error {
+		return p.PrepareHATransition(ctx, from, to)
+	})
+	if cause != nil {
+		s.abortHATransition(participants, result.Participants, finished, from, to, cause)
+		result.Outcome = monitor.HATransitionAborted
+		result.Error = cause.Error()
+		result.End = time.Now()
+		return result
+	}
+
+	_, cause = runHAPhase(ctx, participants, result.Participants, haPhaseCommit, func(ctx context.Context, p monitor.FatimaSystemHATransitionAware) error {
+		return p.CommitHATransition(ctx, from, to)
+	})
+	if cause != nil {
+		result.Outcome = monitor.HATransitionFailed
+		result.Error = cause.Error()
+	} else {
+		result.Outcome = monitor.HATransitionCommitted
+	}
+	result.End = time.Now()
+	return result
+}
+
+// abortHATransition abort participants whose prepare returned. abort is bounded by transition timeout.
+// participant still in prepare is aborted after its prepare returns
+func (s *SystemAwareManagement) abortHATransition(participants []monitor.FatimaSystemHATransitionAware, results []monitor.HATransitionParticipant,
+	finished []chan struct{}, from, to monitor.HAStatus, cause error) {
+	abort := func(_ context.Context, p monitor.FatimaSystemHATransitionAware) error {
+		p.AbortHATransition(from, to, cause)
+		return nil
+	}
+
+	indexes := make([]int, 0, len(participants))
+	for i, p := range participants {
+		select {
+		case <-finished[i]:
+			indexes = append(indexes, i)
+		default:
+			go func() {
+				<-finished[i]
+				if err := callHAPhase(context.Background(), p, abort); err != nil {
+					log.Warn("fail to abort HA transition of %s : %s", results[i].Name, err.Error())
+				}
+			}()
+		}
+	}
+	if len(indexes) == 0 {
+		return
+	}
+
+	aborting := make([]monitor.FatimaSystemHATransitionAware, len(indexes))
+	abortResults := make([]monitor.HATransitionParticipant, len(indexes))
+	for j, i := range indexes {
+		aborting[j] = participants[i]
+		abortResults[j].Name = results[i].Name
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.transitionTimeout)
+	defer cancel()
+	runHAPhase(ctx, aborting, abortResults, haPhaseAbort, abort)
+
+	for j, i := range indexes {
+		if results[i].Error != "" {
+			continue
+		}
+		results[i].Phase = haPhaseAbort
+		results[i].Elapsed += abortResults[j].Elapsed
+		results[i].Error = abortResults[j].Error
+	}
+}
+
+// runHAPhase call fn on every participant concurrently and wait until all are done or ctx is done.
+// return channels which are closed when fn of each participant returns and first error (or timeout)
+func runHAPhase(ctx context.Context, participants []monitor.FatimaSystemHATransitionAware, results []monitor.HATransitionParticipant,
+	phase string, fn func(context.Context, monitor.FatimaSystemHATransitionAware) error) ([]chan struct{}, error) {
+	type phaseResult struct {
+		index   int
+		err     error
+		elapsed time.Duration
+	}
+
+	done := make(chan phaseResult, len(participants))
+	finished := make([]chan struct{}, len(participants))
+	for i, p := range participants {
+		results[i].Phase = phase
+		finished[i] = make(chan struct{})
+		go func() {
+			start := time.Now()
+			err := callHAPhase(ctx, p, fn)
+			close(finished[i])
+			done <- phaseResult{index: i, err: err, elapsed: time.Since(start)}
+		}()
+	}
+
+	var first error
+	pending := make(map[int]bool, len(participants))
+	for i := range participants {
+		pending[i] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case r := <-done:
+			delete(pending, r.index)
+			results[r.index].Elapsed += r.elapsed
+			if r.err != nil {
+				results[r.index].Error = r.err.Error()
+				if first == nil {
+					first = fmt.Errorf("%s %s : %w", results[r.index].Name, phase, r.err)
+				}
+			}
+		case <-ctx.Done():
+			for i := range pending {
+				results[i].Error = ctx.Err().Error()
+			}
+			if first == nil {
+				first = fmt.Errorf("%s timeout : %w", phase, ctx.Err())
+			}
+			return finished, first
+		}
+	}
+	return finished, first
+}
+
+// callHAPhase recover panic of participant
+func callHAPhase(ctx context.Context, p monitor.FatimaSystemHATransitionAware, fn func(context.Context, monitor.FatimaSystemHATransitionAware) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic : %v", r)
+		}
+	}()
+	return fn(ctx, p)
+}
+
+// reportHATransition write result to cfm ha folder and send alarm
+func (s *SystemAwareManagement) reportHATransition(result monitor.HATransitionResult) {
+	if err := s.writeHATransition(result); err != nil {
+		log.Warn("fail to write HA transition result : %s", err.Error())
+	}
+
+	var level monitor.AlarmLevel = monitor.AlarmLevelMinor
+	message := fmt.Sprintf("HA transition %s -> %s %s", result.From, result.To, result.Outcome)
+	if result.Outcome != monitor.HATransitionCommitted {
+		level = monitor.AlamLevelMajor
+		message = fmt.Sprintf("%s : %s", message, result.Error)
+	}
+	log.Warn("%s", message)
+	if s.notifyHandler != nil {
+		s.notifyHandler.SendAlarm(level, monitor.ActionHATransition, message)
+	}
+}
+
+func (s *SystemAwareManagement) writeHATransition(result monitor.HATransitionResult) error {
+	if len(s.haFolder) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.haFolder, 0755); err != nil {
+		return err
+	}
+	return lib.WriteFileAtomic(filepath.Join(s.haFolder, result.Process+haTransitionFileSuffix), data, 0644)
+}
+
+// ReadHATransitionResult read last HA transition result of process in cfm ha folder
+func ReadHATransitionResult(haFolder, process string) (monitor.HATransitionResult, error) {
+	var result monitor.HATransitionResult
+	data, err := os.ReadFile(filepath.Join(haFolder, process+haTransitionFileSuffix))
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func (s *SystemAwareManagement) programName() string {
+	env := s.runtimeProcess.GetEnv()
+	if env == nil || env.GetSystemProc() == nil {
+		return "unknown"
+	}
+	return env.GetSystemProc().GetProgramName()
+}
+
+// participantName GetName() of participant or type name. e.g) pkg.Component
+func participantName(p any) string {
+	if named, ok := p.(interface{ GetName() string }); ok {
+		return named.GetName()
+	}
+	return strings.TrimPrefix(reflect.TypeOf(p).String(), "*")
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오전 10:00
+ */
+
+package infra
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTransitionAware record called phases
+type testTransitionAware struct {
+	name       string
+	mutex      sync.Mutex
+	phases     []string
+	prepareErr error
+	block      bool
+	// prepareHold, abortHold block the phase (ignoring ctx) until closed
+	prepareHold chan struct{}
+	abortHold   chan struct{}
+}
+
+func (a *testTransitionAware) GetName() string {
+	return a.name
+}
+
+func (a *testTransitionAware) PrepareHATransition(ctx context.Context, from, to monitor.HAStatus) error {
+	a.record(haPhasePrepare)
+	if a.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if a.prepareHold != nil {
+		<-a.prepareHold
+	}
+	return a.prepareErr
+}
+
+func (a *testTransitionAware) CommitHATransition(ctx context.Context, from, to monitor.HAStatus) error {
+	a.record(haPhaseCommit)
+	return nil
+}
+
+func (a *testTransitionAware) AbortHATransition(from, to monitor.HAStatus, cause error) {
+	if a.abortHold != nil {
+		<-a.abortHold
+	}
+	a.record(haPhaseAbort)
+}
+
+func (a *testTransitionAware) record(phase string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.phases = append(a.phases, phase)
+}
+
+func (a *testTransitionAware) get() []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]string(nil), a.phases...)
+}
+
+// testAlarmHandler record alarms
+type testAlarmHandler struct {
+	monitor.SystemNotifyHandler
+	mutex  sync.Mutex
+	alarms []monitor.AlarmLevel
+}
+
+func (h *testAlarmHandler) SendAlarm(level monitor.AlarmLevel, action monitor.ActionType, message string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.alarms = append(h.alarms, level)
+}
+
+func (h *testAlarmHandler) get() []monitor.AlarmLevel {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]monitor.AlarmLevel(nil), h.alarms...)
+}
+
+func newTestTransition(t *testing.T, participants ...monitor.FatimaSystemHATransitionAware) (*SystemAwareManagement, *testStatusWatcher, *slowHAAware, *testAlarmHandler) {
+	// temp dir is removed after close (cleanup runs in reverse order) so that running transition can write outcome
+	haFolder := t.TempDir()
+	watcher := &testStatusWatcher{ha: monitor.HA_STATUS_STANDBY, ps: monitor.PS_STATUS_SECONDARY}
+	s := newSystemAwareManagement(builder.NewFatimaRuntime(), watcher)
+	t.Cleanup(s.close)
+	s.haFolder = haFolder
+	alarm := &testAlarmHandler{}
+	s.notifyHandler = alarm
+	s.SetHATransitionTimeout(100 * time.Millisecond)
+	aware := &slowHAAware{}
+	s.RegisterSystemHAAware(aware)
+	for _, p := range participants {
+		s.RegisterSystemHATransitionAware(p)
+	}
+	return s, watcher, aware, alarm
+}
+
+func TestHATransition(t *testing.T) {
+	testCases := []struct {
+		name         string
+		participants []*testTransitionAware
+		outcome      string
+		phases       [][]string
+		status       monitor.HAStatus
+		alarm        monitor.AlarmLevel
+	}{
+		{
+			name:         "committed",
+			participants: []*testTransitionAware{{name: "a"}, {name: "b"}},
+			outcome:      monitor.HATransitionCommitted,
+			phases:       [][]string{{haPhasePrepare, haPhaseCommit}, {haPhasePrepare, haPhaseCommit}},
+			status:       monitor.HA_STATUS_ACTIVE,
+			alarm:        monitor.AlarmLevelMinor,
+		},
+		{
+			name:         "prepare error",
+			participants: []*testTransitionAware{{name: "a"}, {name: "b", prepareErr: errors.New("not ready")}},
+			outcome:      monitor.HATransitionAborted,
+			phases:       [][]string{{haPhasePrepare, haPhaseAbort}, {haPhasePrepare, haPhaseAbort}},
+			status:       monitor.HA_STATUS_STANDBY,
+			alarm:        monitor.AlamLevelMajor,
+		},
+		{
+			name:         "prepare timeout",
+			participants: []*testTransitionAware{{name: "a"}, {name: "b", block: true}},
+			outcome:      monitor.HATransitionAborted,
+			phases:       [][]string{{haPhasePrepare, haPhaseAbort}, {haPhasePrepare, haPhaseAbort}},
+			status:       monitor.HA_STATUS_STANDBY,
+			alarm:        monitor.AlamLevelMajor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			participants := make([]monitor.FatimaSystemHATransitionAware, len(tc.participants))
+			for i, p := range tc.participants {
+				participants[i] = p
+			}
+			s, watcher, aware, alarm := newTestTransition(t, participants...)
+
+			watcher.setHA(monitor.HA_STATUS_ACTIVE)
+			s.check()
+
+			var result monitor.HATransitionResult
+			require.Eventually(t, func() bool {
+				var err error
+				result, err = ReadHATransitionResult(s.haFolder, s.programName())
+				return err == nil
+			}, time.Second, 5*time.Millisecond)
+
+			assert.Equal(t, tc.outcome, result.Outcome)
+			assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_STANDBY).String(), result.From)
+			assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_ACTIVE).String(), result.To)
+			require.Len(t, result.Participants, len(tc.participants))
+			for i, p := range tc.participants {
+				assert.Equal(t, p.name, result.Participants[i].Name)
+				// participant timed out in prepare is aborted after its prepare returns
+				require.Eventually(t, func() bool { return assert.ObjectsAreEqual(tc.phases[i], p.get()) }, time.Second, 5*time.Millisecond)
+			}
+			require.Eventually(t, func() bool { return len(alarm.get()) == 1 }, time.Second, 5*time.Millisecond)
+			assert.Equal(t, tc.alarm, alarm.get()[0])
+			assert.Equal(t, tc.status, s.runtimeProcess.GetSystemStatus().GetHAStatus())
+
+			if tc.outcome == monitor.HATransitionCommitted {
+				require.Eventually(t, func() bool { return len(aware.get()) == 1 }, time.Second, 5*time.Millisecond)
+				return
+			}
+
+			// aborted transition is not retried while cfm status is not changed
+			assert.NotEmpty(t, result.Error)
+			s.check()
+			time.Sleep(20 * time.Millisecond)
+			assert.Len(t, tc.participants[0].get(), 2)
+			assert.Empty(t, aware.get())
+
+			// status flip resets abort
+			watcher.setHA(monitor.HA_STATUS_STANDBY)
+			s.check()
+			watcher.setHA(monitor.HA_STATUS_ACTIVE)
+			s.check()
+			require.Eventually(t, func() bool { return len(tc.participants[0].get()) == 4 }, time.Second, 5*time.Millisecond)
+			// second transition is finished (outcome written and alarmed)
+			require.Eventually(t, func() bool { return len(alarm.get()) == 2 }, time.Second, 5*time.Millisecond)
+		})
+	}
+}
+
+func TestHATransitionHungParticipant(t *testing.T) {
+	a := &testTransitionAware{name: "a", abortHold: make(chan struct{})}
+	b := &testTransitionAware{name: "b", prepareHold: make(chan struct{})}
+	s, watcher, aware, _ := newTestTransition(t, a, b)
+
+	watcher.setHA(monitor.HA_STATUS_ACTIVE)
+	s.check()
+
+	// runtime status is not changed while participants prepare
+	require.Eventually(t, func() bool { return len(b.get()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_STANDBY), s.runtimeProcess.GetSystemStatus().GetHAStatus())
+	s.check()
+
+	// hung abort and hung prepare do not block delivery
+	var result monitor.HATransitionResult
+	require.Eventually(t, func() bool {
+		var err error
+		result, err = ReadHATransitionResult(s.haFolder, s.programName())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, monitor.HATransitionAborted, result.Outcome)
+	assert.Equal(t, haPhaseAbort, result.Participants[0].Phase)
+	assert.Equal(t, context.DeadlineExceeded.Error(), result.Participants[0].Error)
+	assert.Equal(t, haPhasePrepare, result.Participants[1].Phase)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_STANDBY), s.runtimeProcess.GetSystemStatus().GetHAStatus())
+	assert.Empty(t, aware.get())
+
+	// b is not aborted while its prepare is running
+	assert.Equal(t, []string{haPhasePrepare}, b.get())
+	close(b.prepareHold)
+	require.Eventually(t, func() bool { return len(b.get()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{haPhasePrepare, haPhaseAbort}, b.get())
+
+	close(a.abortHold)
+	// check during prepare did not deliver the transition again
+	require.Eventually(t, func() bool { return len(a.get()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{haPhasePrepare, haPhaseAbort}, a.get())
+}
+
+func TestHATransitionCheckNotBlocked(t *testing.T) {
+	a := &testTransitionAware{name: "a", prepareHold: make(chan struct{})}
+	s, watcher, _, _ := newTestTransition(t, a)
+	defer close(a.prepareHold)
+
+	// flips more than queue size while first transition hangs
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		for i := 0; i < 2*deliveryQueueSize; i++ {
+			if i%2 == 0 {
+				watcher.setHA(monitor.HA_STATUS_ACTIVE)
+			} else {
+				watcher.setHA(monitor.HA_STATUS_STANDBY)
+			}
+			s.check()
+		}
+	}()
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("check is blocked by hung transition")
+	}
+	require.Eventually(t, func() bool { return len(a.get()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{haPhasePrepare}, a.get())
+}
@@ -21,10 +21,20 @@
 package infra
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/fatima-go/fatima-core"
 	"github.com/fatima-go/fatima-core/builder"
 	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	defaultMeasureInterval  = 5 * time.Second
+	defaultActivityInterval = time.Minute
 )
 
 // newSystemMeasureManagement process measure. e.g) Heap size, gc cycle count, ....
@@ -32,53 +42,220 @@ func newSystemMeasureManagement(runtimeProcess *builder.FatimaRuntimeProcess) *S
 	// mgmt : operate process management
 	mgmt := new(SystemMeasureManagement)
 	mgmt.runtimeProcess = runtimeProcess
-	mgmt.writer = newMeasureFileWriter(runtimeProcess.GetEnv())
-	mgmt.units = make([]monitor.SystemMeasurable, 0)
+	mgmt.config = runtimeProcess.GetConfig()
+	mgmt.writer = newMeasureFileWriter(runtimeProcess.GetEnv(), mgmt.config)
+	mgmt.units = make([]*measureUnit, 0)
+	mgmt.registry = newMetricRegistry()
+	mgmt.interval = defaultMeasureInterval
+	mgmt.activityInterval = defaultActivityInterval
+	if mgmt.config != nil {
+		mgmt.interval = max(mgmt.config.GetDurationOrDefault(builder.GofatimaMeasureInterval, mgmt.interval), time.Second)
+		mgmt.activityInterval = max(mgmt.config.GetDurationOrDefault(builder.GofatimaActivityInterval, mgmt.activityInterval), mgmt.interval)
+	}
 
 	// currently only process measurement provided
-	mgmt.registerUnit(newProcessMeasurement())
+	mgmt.registerUnit(newProcessMeasurement(runtimeProcess.GetSystemNotifyHandler()))
 
 	return mgmt
 }
 
 type SystemMeasureManagement struct {
-	runtimeProcess *builder.FatimaRuntimeProcess
-	units          []monitor.SystemMeasurable
-	writer         MeasurementWriter
+	runtimeProcess   *builder.FatimaRuntimeProcess
+	config           fatima.Config
+	interval         time.Duration
+	activityInterval time.Duration
+	lastActivity     time.Time
+	unitMutex        sync.Mutex
+	units            []*measureUnit
+	registry         *metricRegistry
+	writer           MeasurementWriter
+	legacyMutex      sync.Mutex
+	legacy           []monitor.MetricFamily // adapted from text of last sampling
 }
 
+// registerUnit unit which implements MetricCollector provides metrics. otherwise numeric values in text are adapted.
+// unit which has own interval (SystemMeasureScheduled or config) is measured in background
 func (s *SystemMeasureManagement) registerUnit(unit monitor.SystemMeasurable) {
-	s.units = append(s.units, unit)
+	u := &measureUnit{unit: unit}
+	if scheduled, ok := unit.(monitor.SystemMeasureScheduled); ok {
+		u.interval = scheduled.GetMeasureInterval()
+	}
+	if s.config != nil {
+		key := fmt.Sprintf(builder.GofatimaMeasureUnitInterval, measureUnitConfigName(unit.GetKeyName()))
+		u.interval = s.config.GetDurationOrDefault(key, u.interval)
+	}
+	if u.interval > 0 {
+		log.Info("measure unit [%s] is measured every %s", unit.GetKeyName(), u.interval)
+	}
+
+	s.unitMutex.Lock()
+	s.units = append(s.units, u)
+	s.unitMutex.Unlock()
+	if collector, ok := unit.(monitor.MetricCollector); ok {
+		s.registry.RegisterCollector(collector)
+	}
 }
 
-var measureTick uint64 = 0
+// measureUnitConfigName e.g) "fatima process" -> "fatima_process"
+func measureUnitConfigName(keyName string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(keyName)), " ", "_")
+}
 
 func (s *SystemMeasureManagement) Process() {
-	msr := measurement{eventTime: time.Now()}
-	msr.items = make([]measureItem, 0)
-	for _, v := range s.units {
-		msr.items = append(msr.items, measureItem{v.GetKeyName(), v.GetMeasure()})
-	}
+	start := time.Now()
+	msr := s.sample()
 	s.writer.write(msr)
+	if elapsed := time.Since(start); s.interval > 0 && elapsed > s.interval {
+		log.Warn("measurement took %s. longer than interval %s", elapsed, s.interval)
+	}
 
-	measureTick += 1
+	// collect measurement every interval and send one time every activity interval
+	if !s.activityDue(msr.eventTime) {
+		return
+	}
+	sendActivity(s.runtimeProcess.GetSystemNotifyHandler(), msr)
+}
 
-	// collect (every 5 seconds) measurement and send one time (every 1 min)
-	if measureTick%12 == 0 {
-		activity := make(map[string]string)
-		for _, v := range msr.items {
-			activity[v.keyName] = v.value
+// sendActivity send texts of units as activity. structured numbers are sent as separate message
+// so that activity format (map of unit texts) is not changed
+func sendActivity(notifyHandler monitor.SystemNotifyHandler, msr measurement) {
+	activity := make(map[string]string)
+	for _, v := range msr.activity {
+		activity[v.keyName] = v.value
+	}
+	notifyHandler.SendActivity(activity)
+	if handler, ok := notifyHandler.(monitor.MetricNotifyHandler); ok && len(msr.families) > 0 {
+		handler.SendMetrics(msr.families)
+	}
+}
+
+// activityDue first activity is sent after activity interval. half interval is tolerated for tick jitter
+func (s *SystemMeasureManagement) activityDue(now time.Time) bool {
+	if s.lastActivity.IsZero() {
+		s.lastActivity = now
+		return false
+	}
+	if now.Sub(s.lastActivity) < s.activityInterval-s.interval/2 {
+		return false
+	}
+	s.lastActivity = now
+	return true
+}
+
+// sample measure units and metrics. text of unit is read once because unit may reset value at GetMeasure (e.g. ResponseMarker).
+// scheduled unit is sampled only after its new measurement completes. its last text is kept for activity
+// and its last gauges are kept for gather (e.g. /metrics)
+func (s *SystemMeasureManagement) sample() measurement {
+	msr := measurement{eventTime: time.Now()}
+	msr.items = make([]measureItem, 0)
+	msr.activity = make([]measureItem, 0)
+	sampled := make([]monitor.MetricFamily, 0)
+	legacy := make([]monitor.MetricFamily, 0)
+	s.unitMutex.Lock()
+	units := append([]*measureUnit(nil), s.units...)
+	s.unitMutex.Unlock()
+	for _, v := range units {
+		value, ok := v.measure(msr.eventTime)
+		if !ok {
+			// scheduled unit is not measured since last sampling
+			if last, measured := v.lastText(); measured {
+				msr.activity = append(msr.activity, measureItem{v.unit.GetKeyName(), last})
+			}
+			legacy = append(legacy, v.families...)
+			continue
+		}
+		item := measureItem{v.unit.GetKeyName(), value}
+		msr.items = append(msr.items, item)
+		msr.activity = append(msr.activity, item)
+		if _, ok := v.unit.(monitor.MetricCollector); !ok {
+			v.families = measurableFamilies(item.keyName, item.value)
+			sampled = append(sampled, v.families...)
+			legacy = append(legacy, v.families...)
 		}
-		s.runtimeProcess.GetSystemNotifyHandler().SendActivity(activity)
 	}
+	s.legacyMutex.Lock()
+	s.legacy = legacy
+	s.legacyMutex.Unlock()
+
+	msr.families = mergeMetricFamilies(s.registry.Gather(), sampled)
+	return msr
+}
+
+// gather current metrics and metrics of legacy units at last sampling. legacy units are not measured here
+// because measuring may reset value of unit
+func (s *SystemMeasureManagement) gather() []monitor.MetricFamily {
+	s.legacyMutex.Lock()
+	legacy := s.legacy
+	s.legacyMutex.Unlock()
+	return mergeMetricFamilies(s.registry.Gather(), legacy)
 }
 
 type measurement struct {
 	eventTime time.Time
-	items     []measureItem
+	items     []measureItem // texts measured for this sampling
+	activity  []measureItem // items and last texts of scheduled units not measured for this sampling
+	families  []monitor.MetricFamily
 }
 
 type measureItem struct {
 	keyName string
 	value   string
 }
+
+// measureUnit unit which has interval is measured in background not to delay other units
+type measureUnit struct {
+	unit     monitor.SystemMeasurable
+	interval time.Duration // 0 means every measurement
+	mutex    sync.Mutex
+	running  bool
+	fresh    bool // measured in background and not sampled yet
+	measured bool // last is measured at least once
+	last     string
+	lastTime time.Time
+	families []monitor.MetricFamily // gauges of last text. accessed by sampling only
+}
+
+// measure return text of unit. scheduled unit returns text only once after each measure completes
+func (u *measureUnit) measure(now time.Time) (string, bool) {
+	if u.interval <= 0 {
+		return u.unit.GetMeasure(), true
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if !u.running && now.Sub(u.lastTime) >= u.interval {
+		u.running = true
+		u.lastTime = now
+		go u.measureBackground()
+	}
+	if !u.fresh {
+		return "", false
+	}
+	u.fresh = false
+	return u.last, true
+}
+
+// lastText return last measured text of scheduled unit
+func (u *measureUnit) lastText() (string, bool) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return u.last, u.measured
+}
+
+func (u *measureUnit) measureBackground() {
+	value, ok := "", false
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warn("panic to measure [%s] : %v", u.unit.GetKeyName(), r)
+		}
+		u.mutex.Lock()
+		u.running = false
+		if ok {
+			u.last = value
+			u.fresh, u.measured = true, true
+		}
+		u.mutex.Unlock()
+	}()
+	value = u.unit.GetMeasure()
+	ok = true
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package infra
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowMeasurable scheduled unit which blocks until released
+type slowMeasurable struct {
+	release chan struct{}
+	count   atomic.Int32
+}
+
+func (m *slowMeasurable) GetKeyName() string {
+	return "slow unit"
+}
+
+func (m *slowMeasurable) GetMeasure() string {
+	<-m.release
+	m.count.Add(1)
+	return ":: slow=1"
+}
+
+func (m *slowMeasurable) GetMeasureInterval() time.Duration {
+	return time.Minute
+}
+
+func TestScheduledMeasureUnit(t *testing.T) {
+	mgmt := &SystemMeasureManagement{registry: newMetricRegistry()}
+	fast := &testMeasurable{}
+	slow := &slowMeasurable{release: make(chan struct{})}
+	mgmt.registerUnit(fast)
+	mgmt.registerUnit(slow)
+
+	// slow unit does not delay sampling. it is skipped before first measure
+	msr := mgmt.sample()
+	require.Len(t, msr.items, 1)
+	assert.Equal(t, "legacy unit", msr.items[0].keyName)
+	assert.Len(t, msr.activity, 1)
+
+	close(slow.release)
+	assert.Eventually(t, func() bool {
+		return len(mgmt.sample().items) == 2
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// measured text is sampled once. it is not sampled again until interval passes
+	msr = mgmt.sample()
+	require.Len(t, msr.items, 1)
+	assert.Equal(t, int32(1), slow.count.Load())
+	for _, family := range msr.families {
+		assert.NotContains(t, family.Name, "slow_unit")
+	}
+	// last text is still sent as activity
+	require.Len(t, msr.activity, 2)
+	assert.Equal(t, measureItem{"slow unit", ":: slow=1"}, msr.activity[1])
+	handler := &testActivityHandler{}
+	sendActivity(handler, msr)
+	assert.Equal(t, ":: slow=1", handler.activity.(map[string]string)["slow unit"])
+	// last gauges are still gathered
+	gathered := false
+	for _, family := range mgmt.gather() {
+		gathered = gathered || strings.Contains(family.Name, "slow_unit")
+	}
+	assert.True(t, gathered)
+}
+
+func TestMeasureActivityDue(t *testing.T) {
+	mgmt := &SystemMeasureManagement{interval: 5 * time.Second, activityInterval: time.Minute}
+	now := time.Now()
+	assert.False(t, mgmt.activityDue(now))
+
+	sent := 0
+	for i := 1; i <= 36; i++ {
+		if mgmt.activityDue(now.Add(time.Duration(i) * 5 * time.Second)) {
+			sent++
+		}
+	}
+	// 3 minutes
+	assert.Equal(t, 3, sent)
+}
+
+func TestMeasureUnitConfigName(t *testing.T) {
+	assert.Equal(t, "fatima_process", measureUnitConfigName("fatima process"))
+	assert.Equal(t, "restclient", measureUnitConfigName(" RestClient "))
+}
+
+// blockingWorker worker which blocks until released
+type blockingWorker struct {
+	release chan struct{}
+	count   atomic.Int32
+}
+
+func (w *blockingWorker) Process() {
+	w.count.Add(1)
+	<-w.release
+}
+
+func TestTickWorkerSkip(t *testing.T) {
+	slow := &blockingWorker{release: make(chan struct{})}
+	fast := &blockingWorker{release: make(chan struct{})}
+	close(fast.release)
+	workers := []*tickWorker{newTickWorker("slow", slow), newTickWorker("fast", fast)}
+
+	for i := 0; i < 3; i++ {
+		iterateWorkers(workers)
+		assert.Eventually(t, func() bool {
+			return fast.count.Load() == int32(i+1) && !workers[1].running.Load()
+		}, time.Second, time.Millisecond)
+	}
+
+	// slow worker runs once and other ticks are skipped
+	assert.Equal(t, int32(1), slow.count.Load())
+	assert.Equal(t, uint64(2), workers[0].skipped.Load())
+	close(slow.release)
+	assert.Eventually(t, func() bool {
+		return !workers[0].running.Load()
+	}, time.Second, time.Millisecond)
+}
+
+// testActivityHandler record activity and metrics
+type testActivityHandler struct {
+	monitor.SystemNotifyHandler
+	activity interface{}
+	families []monitor.MetricFamily
+}
+
+func (h *testActivityHandler) SendActivity(json interface{}) {
+	h.activity = json
+}
+
+func (h *testActivityHandler) SendMetrics(families []monitor.MetricFamily) {
+	h.families = families
+}
+
+func TestSendActivity(t *testing.T) {
+	msr := measurement{
+		items:    []measureItem{{"legacy unit", ":: count=3"}},
+		activity: []measureItem{{"legacy unit", ":: count=3"}},
+		families: []monitor.MetricFamily{{Name: "a_total", Type: monitor.MetricCounter, Metrics: []monitor.Metric{{Value: 1}}}},
+	}
+	handler := &testActivityHandler{}
+	sendActivity(handler, msr)
+
+	// activity format is not changed by metrics
+	assert.Equal(t, map[string]string{"legacy unit": ":: count=3"}, handler.activity)
+	assert.Equal(t, msr.families, handler.families)
+}
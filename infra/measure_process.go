@@ -22,47 +22,318 @@ package infra
 
 import (
 	"fmt"
-	"runtime"
+	"math"
+	"runtime/metrics"
+	"sync"
 	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
 )
 
-func newProcessMeasurement() *ProcessMeasurement {
-	measure := new(ProcessMeasurement)
+const (
+	processTrendWindow     = 60 // samples. e.g) 5 minutes when measured every 5 seconds (gofatima.measure.interval)
+	goroutineLeakMinGrowth = 100
+	fdLeakMinGrowth        = 50
+)
+
+const (
+	metricGoroutines   = "/sched/goroutines:goroutines"
+	metricGcCycles     = "/gc/cycles/total:gc-cycles"
+	metricHeapObjects  = "/memory/classes/heap/objects:bytes"
+	metricMemoryTotal  = "/memory/classes/total:bytes"
+	metricGcPauses     = "/sched/pauses/total/gc:seconds"
+	metricSchedLatency = "/sched/latencies:seconds"
+)
 
+func newProcessMeasurement(notifyHandler monitor.SystemNotifyHandler) *ProcessMeasurement {
+	measure := new(ProcessMeasurement)
+	measure.notifyHandler = notifyHandler
+	measure.readResource = readProcessResource
+	measure.samples = []metrics.Sample{
+		{Name: metricGoroutines},
+		{Name: metricGcCycles},
+		{Name: metricHeapObjects},
+		{Name: metricMemoryTotal},
+		{Name: metricGcPauses},
+		{Name: metricSchedLatency},
+	}
+	measure.goroutineTrend = newLeakTrend("goroutine", processTrendWindow, goroutineLeakMinGrowth)
+	measure.fdTrend = newLeakTrend("fd", processTrendWindow, fdLeakMinGrowth)
 	return measure
 }
 
+// ProcessMeasurement go runtime (runtime/metrics) and OS resources (/proc/self) of process.
+// WARN alarm is raised when goroutines or open fds keep growing
 type ProcessMeasurement struct {
+	mutex          sync.Mutex
+	notifyHandler  monitor.SystemNotifyHandler
+	readResource   func(resource *processResource) error
+	samples        []metrics.Sample
+	lastPauses     []uint64 // gc pause histogram counts of last measure
+	lastLatencies  []uint64 // scheduler latency histogram counts of last measure
+	last           *processSnapshot
+	goroutineTrend *leakTrend
+	fdTrend        *leakTrend
+}
+
+type processSnapshot struct {
+	goroutines   uint64
+	gcCycles     uint64
+	heapBytes    uint64
+	totalBytes   uint64
+	gcPause      latencySummary // since last measure
+	schedLatency latencySummary // since last measure
+	resource     processResource
+	hasResource  bool
+}
+
+// latencySummary seconds
+type latencySummary struct {
+	p50 float64
+	p99 float64
+	max float64
+}
+
+// processResource OS resources of process
+type processResource struct {
+	rss     uint64
+	threads int
+	fds     int
+	fdLimit uint64
+	cpuUser time.Duration
+	cpuSys  time.Duration
 }
 
 func (p *ProcessMeasurement) GetKeyName() string {
 	return "fatima process"
 }
 
-var lastGcCount uint32
-
 func (p *ProcessMeasurement) GetMeasure() string {
-	var mem runtime.MemStats
-	runtime.ReadMemStats(&mem)
-	if mem.LastGC == 0 {
-		return fmt.Sprintf(" :: Alloc=%s, TotalAlloc=%s, Sys=%s, NumGC=%d",
-			expressBytes(mem.Alloc),
-			expressBytes(mem.TotalAlloc),
-			expressBytes(mem.Sys),
-			mem.NumGC)
-	}
-
-	pauseMs := 0
-	if lastGcCount != mem.NumGC {
-		pauseMs = int(mem.PauseNs[(mem.NumGC+255)%256] / 1000)
-		lastGcCount = mem.NumGC
-	}
-
-	return fmt.Sprintf(" :: Alloc=%s, Sys=%s, TotalGC=%d, LastPause=%dms, LastGC=%s",
-		expressBytes(mem.Alloc),
-		//expressBytes(mem.TotalAlloc),
-		expressBytes(mem.Sys),
-		mem.NumGC,
-		pauseMs,
-		time.Unix(0, int64(mem.LastGC)).Format("2006-01-02 15:04:05"))
+	p.mutex.Lock()
+	snapshot := p.measure()
+	p.mutex.Unlock()
+
+	p.detectLeak(snapshot)
+
+	text := fmt.Sprintf(" :: Goroutines=%d, Heap=%s, Sys=%s, TotalGC=%d, GCPause(p50/p99/max)=%s/%s/%s, SchedLatency(p50/p99/max)=%s/%s/%s",
+		snapshot.goroutines,
+		expressBytes(snapshot.heapBytes),
+		expressBytes(snapshot.totalBytes),
+		snapshot.gcCycles,
+		expressSeconds(snapshot.gcPause.p50), expressSeconds(snapshot.gcPause.p99), expressSeconds(snapshot.gcPause.max),
+		expressSeconds(snapshot.schedLatency.p50), expressSeconds(snapshot.schedLatency.p99), expressSeconds(snapshot.schedLatency.max))
+	if !snapshot.hasResource {
+		return text
+	}
+	resource := snapshot.resource
+	return text + fmt.Sprintf(", Threads=%d, FD=%d/%d, RSS=%s, CPU(user/sys)=%s/%s",
+		resource.threads,
+		resource.fds,
+		resource.fdLimit,
+		expressBytes(resource.rss),
+		resource.cpuUser.Round(time.Millisecond),
+		resource.cpuSys.Round(time.Millisecond))
+}
+
+// CollectMetrics metrics of last measure. percentiles are observations between last two measures
+func (p *ProcessMeasurement) CollectMetrics() []monitor.MetricFamily {
+	p.mutex.Lock()
+	snapshot := p.last
+	if snapshot == nil {
+		snapshot = p.measure()
+	}
+	p.mutex.Unlock()
+
+	gauge := func(name, help string, value float64) monitor.MetricFamily {
+		return monitor.MetricFamily{Name: name, Help: help, Type: monitor.MetricGauge, Metrics: []monitor.Metric{{Value: value}}}
+	}
+	counter := func(name, help string, value float64) monitor.MetricFamily {
+		return monitor.MetricFamily{Name: name, Help: help, Type: monitor.MetricCounter, Metrics: []monitor.Metric{{Value: value}}}
+	}
+	percentiles := func(name, help string, summary latencySummary) monitor.MetricFamily {
+		return monitor.MetricFamily{Name: name, Help: help, Type: monitor.MetricGauge, Metrics: []monitor.Metric{
+			{Labels: monitor.Labels{"percentile": "p50"}, Value: summary.p50},
+			{Labels: monitor.Labels{"percentile": "p99"}, Value: summary.p99},
+			{Labels: monitor.Labels{"percentile": "max"}, Value: summary.max},
+		}}
+	}
+
+	families := []monitor.MetricFamily{
+		gauge("fatima_process_heap_alloc_bytes", "bytes of allocated heap objects", float64(snapshot.heapBytes)),
+		gauge("fatima_process_sys_bytes", "bytes of memory obtained from OS", float64(snapshot.totalBytes)),
+		gauge("fatima_process_goroutines", "number of goroutines", float64(snapshot.goroutines)),
+		counter("fatima_process_gc_total", "number of completed GC cycles", float64(snapshot.gcCycles)),
+		percentiles("fatima_process_gc_pause_seconds", "GC stop-the-world pause between measures", snapshot.gcPause),
+		percentiles("fatima_process_sched_latency_seconds", "time goroutines spent runnable before running between measures", snapshot.schedLatency),
+	}
+	if !snapshot.hasResource {
+		return families
+	}
+	resource := snapshot.resource
+	return append(families,
+		gauge("fatima_process_threads", "number of OS threads", float64(resource.threads)),
+		gauge("fatima_process_open_fds", "number of open file descriptors", float64(resource.fds)),
+		gauge("fatima_process_max_fds", "limit of open file descriptors", float64(resource.fdLimit)),
+		gauge("fatima_process_resident_memory_bytes", "resident memory size", float64(resource.rss)),
+		counter("fatima_process_cpu_user_seconds_total", "user CPU time", resource.cpuUser.Seconds()),
+		counter("fatima_process_cpu_system_seconds_total", "system CPU time", resource.cpuSys.Seconds()),
+	)
+}
+
+// measure read runtime metrics and OS resources. caller should hold mutex
+func (p *ProcessMeasurement) measure() *processSnapshot {
+	metrics.Read(p.samples)
+
+	snapshot := new(processSnapshot)
+	for _, sample := range p.samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			value := sample.Value.Uint64()
+			switch sample.Name {
+			case metricGoroutines:
+				snapshot.goroutines = value
+			case metricGcCycles:
+				snapshot.gcCycles = value
+			case metricHeapObjects:
+				snapshot.heapBytes = value
+			case metricMemoryTotal:
+				snapshot.totalBytes = value
+			}
+		case metrics.KindFloat64Histogram:
+			histogram := sample.Value.Float64Histogram()
+			switch sample.Name {
+			case metricGcPauses:
+				snapshot.gcPause = summarizeLatency(histogram, p.lastPauses)
+				p.lastPauses = append(p.lastPauses[:0], histogram.Counts...)
+			case metricSchedLatency:
+				snapshot.schedLatency = summarizeLatency(histogram, p.lastLatencies)
+				p.lastLatencies = append(p.lastLatencies[:0], histogram.Counts...)
+			}
+		}
+	}
+
+	if p.readResource != nil {
+		err := p.readResource(&snapshot.resource)
+		snapshot.hasResource = err == nil
+	}
+	p.last = snapshot
+	return snapshot
+}
+
+// detectLeak raise WARN alarm when goroutines or fds keep growing
+func (p *ProcessMeasurement) detectLeak(snapshot *processSnapshot) {
+	if growth, leak := p.goroutineTrend.add(float64(snapshot.goroutines)); leak {
+		p.alarmLeak(p.goroutineTrend, growth, fmt.Sprintf("goroutines=%d", snapshot.goroutines))
+	}
+	if !snapshot.hasResource {
+		return
+	}
+	if growth, leak := p.fdTrend.add(float64(snapshot.resource.fds)); leak {
+		p.alarmLeak(p.fdTrend, growth, fmt.Sprintf("fds=%d/%d", snapshot.resource.fds, snapshot.resource.fdLimit))
+	}
+}
+
+func (p *ProcessMeasurement) alarmLeak(trend *leakTrend, growth float64, current string) {
+	message := fmt.Sprintf("%s leak suspected. increased %.0f during last %d measures (%s)",
+		trend.name, growth, trend.window, current)
+	log.Warn("%s", message)
+	if p.notifyHandler != nil {
+		p.notifyHandler.SendAlarm(monitor.AlarmLevelWarn, monitor.ActionResourceLeak, message)
+	}
+}
+
+// summarizeLatency percentiles of observations since last counts. upper bound of bucket is used
+func summarizeLatency(histogram *metrics.Float64Histogram, last []uint64) latencySummary {
+	summary := latencySummary{}
+	counts := make([]uint64, len(histogram.Counts))
+	var total uint64
+	for i, count := range histogram.Counts {
+		if i < len(last) && last[i] <= count {
+			count -= last[i]
+		}
+		counts[i] = count
+		total += count
+	}
+	if total == 0 {
+		return summary
+	}
+
+	bound := func(i int) float64 {
+		upper := histogram.Buckets[i+1]
+		if upper > histogram.Buckets[i] && !math.IsInf(upper, 1) {
+			return upper
+		}
+		return histogram.Buckets[i]
+	}
+	p50, p99 := (total+1)/2, total-total/100
+	var cumulative uint64
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		if cumulative < p50 && cumulative+count >= p50 {
+			summary.p50 = bound(i)
+		}
+		if cumulative < p99 && cumulative+count >= p99 {
+			summary.p99 = bound(i)
+		}
+		cumulative += count
+		summary.max = bound(i)
+	}
+	return summary
+}
+
+// expressSeconds e.g) 0.00123 -> 1.23ms
+func expressSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Microsecond).String()
+}
+
+func newLeakTrend(name string, window int, minGrowth float64) *leakTrend {
+	return &leakTrend{name: name, window: window, minGrowth: minGrowth}
+}
+
+// leakTrend detect sustained growth. it is leak when every value in last quarter of window is greater
+// than every value in first quarter by minGrowth at least. alarm is raised once until trend is broken
+type leakTrend struct {
+	name      string
+	window    int
+	minGrowth float64
+	values    []float64
+	alarmed   bool
+}
+
+// add return growth and true when leak is newly detected
+func (t *leakTrend) add(value float64) (float64, bool) {
+	t.values = append(t.values, value)
+	if len(t.values) > t.window {
+		t.values = t.values[len(t.values)-t.window:]
+	}
+	if len(t.values) < t.window {
+		return 0, false
+	}
+
+	quarter := t.window / 4
+	if quarter == 0 {
+		quarter = 1
+	}
+	firstMax := t.values[0]
+	for _, v := range t.values[:quarter] {
+		firstMax = max(firstMax, v)
+	}
+	lastMin := t.values[len(t.values)-1]
+	for _, v := range t.values[len(t.values)-quarter:] {
+		lastMin = min(lastMin, v)
+	}
+
+	growth := lastMin - firstMax
+	if growth < t.minGrowth {
+		t.alarmed = false
+		return growth, false
+	}
+	if t.alarmed {
+		return growth, false
+	}
+	t.alarmed = true
+	return growth, true
 }
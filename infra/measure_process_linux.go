@@ -0,0 +1,86 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package infra
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// readProcessResource read rss, threads, fds from /proc/self and cpu time, fd limit from syscall
+func readProcessResource(resource *processResource) error {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return err
+	}
+	resource.fds = len(entries)
+
+	status, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return err
+	}
+	parseProcStatus(status, resource)
+
+	var limit syscall.Rlimit
+	if err = syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err == nil {
+		resource.fdLimit = limit.Cur
+	}
+
+	var usage syscall.Rusage
+	if err = syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err == nil {
+		resource.cpuUser = time.Duration(usage.Utime.Nano())
+		resource.cpuSys = time.Duration(usage.Stime.Nano())
+	}
+	return nil
+}
+
+// parseProcStatus read VmRSS and Threads. e.g) "VmRSS:	   10240 kB"
+func parseProcStatus(status []byte, resource *processResource) {
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		switch key {
+		case "VmRSS":
+			if kb, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+				resource.rss = kb * 1024
+			}
+		case "Threads":
+			if threads, err := strconv.Atoi(fields[0]); err == nil {
+				resource.threads = threads
+			}
+		}
+	}
+}
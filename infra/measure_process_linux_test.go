@@ -0,0 +1,48 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package infra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStatus(t *testing.T) {
+	status := "Name:\tfatima\nVmRSS:\t   10240 kB\nThreads:\t12\nSigQ:\t0/63446\n"
+	resource := processResource{}
+	parseProcStatus([]byte(status), &resource)
+	assert.Equal(t, uint64(10240*1024), resource.rss)
+	assert.Equal(t, 12, resource.threads)
+}
+
+func TestReadProcessResource(t *testing.T) {
+	resource := processResource{}
+	require.NoError(t, readProcessResource(&resource))
+	assert.Greater(t, resource.fds, 0)
+	assert.Greater(t, resource.threads, 0)
+	assert.Greater(t, resource.rss, uint64(0))
+	assert.GreaterOrEqual(t, resource.fdLimit, uint64(resource.fds))
+}
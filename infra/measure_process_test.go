@@ -0,0 +1,128 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package infra
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"strings"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeakTrend(t *testing.T) {
+	trend := newLeakTrend("goroutine", 8, 10)
+
+	// not enough samples
+	for i := 0; i < 7; i++ {
+		_, leak := trend.add(float64(100 + i*10))
+		assert.False(t, leak)
+	}
+	growth, leak := trend.add(170)
+	assert.True(t, leak)
+	assert.Equal(t, float64(160-110), growth)
+
+	// alarm once while growing
+	_, leak = trend.add(180)
+	assert.False(t, leak)
+
+	// trend is broken and grows again
+	for i := 0; i < 8; i++ {
+		_, leak = trend.add(100)
+		assert.False(t, leak)
+	}
+	detected := 0
+	for i := 0; i < 7; i++ {
+		if _, leak = trend.add(float64(200 + i*10)); leak {
+			detected++
+		}
+	}
+	assert.Equal(t, 1, detected)
+}
+
+func TestLeakTrendFluctuation(t *testing.T) {
+	trend := newLeakTrend("fd", 8, 10)
+	values := []float64{100, 150, 100, 150, 100, 150, 100, 150, 100, 150}
+	for _, v := range values {
+		_, leak := trend.add(v)
+		assert.False(t, leak)
+	}
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	histogram := &metrics.Float64Histogram{
+		Buckets: []float64{0, 0.001, 0.01, 0.1, 1},
+		Counts:  []uint64{50, 40, 9, 1},
+	}
+	summary := summarizeLatency(histogram, nil)
+	assert.Equal(t, latencySummary{p50: 0.001, p99: 0.1, max: 1}, summary)
+
+	// observations since last counts
+	last := []uint64{50, 40, 9, 1}
+	histogram.Counts = []uint64{50, 42, 9, 1}
+	assert.Equal(t, latencySummary{p50: 0.01, p99: 0.01, max: 0.01}, summarizeLatency(histogram, last))
+
+	// nothing observed
+	assert.Equal(t, latencySummary{}, summarizeLatency(histogram, histogram.Counts))
+}
+
+func TestProcessMeasurementFdLeak(t *testing.T) {
+	alarm := &testAlarmHandler{}
+	measure := newProcessMeasurement(alarm)
+	measure.fdTrend = newLeakTrend("fd", 4, 10)
+	fds := 10
+	measure.readResource = func(resource *processResource) error {
+		resource.fds = fds
+		resource.fdLimit = 1024
+		resource.threads = 7
+		resource.rss = 4096
+		return nil
+	}
+
+	for i := 0; i < 4; i++ {
+		text := measure.GetMeasure()
+		assert.Contains(t, text, fmt.Sprintf("FD=%d/1024", fds))
+		fds += 20
+	}
+	assert.Equal(t, []monitor.AlarmLevel{monitor.AlarmLevelWarn}, alarm.get())
+
+	names := make(map[string]float64)
+	for _, family := range measure.CollectMetrics() {
+		require.NotEmpty(t, family.Metrics)
+		names[family.Name] = family.Metrics[0].Value
+	}
+	assert.Equal(t, float64(70), names["fatima_process_open_fds"])
+	assert.Equal(t, float64(1024), names["fatima_process_max_fds"])
+	assert.Equal(t, float64(7), names["fatima_process_threads"])
+	assert.Equal(t, float64(4096), names["fatima_process_resident_memory_bytes"])
+	assert.Greater(t, names["fatima_process_goroutines"], float64(0))
+}
+
+func TestProcessMeasurementText(t *testing.T) {
+	measure := newProcessMeasurement(nil)
+	text := measure.GetMeasure()
+	for _, key := range []string{"Goroutines=", "Heap=", "TotalGC=", "GCPause(p50/p99/max)=", "SchedLatency(p50/p99/max)="} {
+		assert.True(t, strings.Contains(text, key), "%s not in %s", key, text)
+	}
+}
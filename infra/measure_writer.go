@@ -22,13 +22,18 @@ package infra
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/monitor"
 	"github.com/fatima-go/fatima-log"
 )
 
@@ -39,36 +44,94 @@ const (
 	MaxFileSize   = 30 << (10 * 2) // 30 MB
 )
 
+const (
+	MonitorFormatText = "text"
+	MonitorFormatJson = "json" // json lines in '.jsonl' file
+	MonitorFormatBoth = "both"
+)
+
+const suffixJsonLines = ".jsonl"
+
 type MeasurementWriter interface {
 	write(measurement)
 }
 
-func newMeasureFileWriter(env fatima.FatimaEnv) *MeasureFileWriter {
-	instance := new(MeasureFileWriter)
+// measureFileConfig rotation, retention and format of monitor file
+type measureFileConfig struct {
+	rotateSize     int64
+	rotateInterval time.Duration // 0 means size only
+	rotateCount    int           // generations. e.g) x.monitor.1, x.monitor.2
+	compress       bool          // gzip rotated file. e.g) x.monitor.1.gz
+	retention      time.Duration // history
+	format         string
+}
+
+func newMeasureFileConfig(config fatima.Config) measureFileConfig {
+	c := measureFileConfig{
+		rotateSize:  MaxFileSize,
+		rotateCount: 1,
+		retention:   24 * time.Hour,
+		format:      MonitorFormatText,
+	}
+	if config == nil {
+		return c
+	}
+	c.rotateSize = config.GetBytesOrDefault(builder.GofatimaMonitorRotateSize, c.rotateSize)
+	c.rotateInterval = config.GetDurationOrDefault(builder.GofatimaMonitorRotateInterval, 0)
+	c.rotateCount = max(config.GetIntOrDefault(builder.GofatimaMonitorRotateCount, c.rotateCount), 1)
+	c.compress = config.GetBoolOrDefault(builder.GofatimaMonitorCompress, false)
+	days := max(config.GetIntOrDefault(builder.GofatimaMonitorRetentionDays, 1), 1)
+	c.retention = time.Duration(days) * 24 * time.Hour
+	switch format := strings.ToLower(config.GetStringOrDefault(builder.GofatimaMonitorFormat, c.format)); format {
+	case MonitorFormatText, MonitorFormatJson, MonitorFormatBoth:
+		c.format = format
+	default:
+		log.Warn("invalid monitor format [%s]. use %s", format, c.format)
+	}
+	return c
+}
+
+func newMeasureFileWriter(env fatima.FatimaEnv, config fatima.Config) *MeasureFileWriter {
 	fileName := fmt.Sprintf("%s.%d.%s",
 		env.GetSystemProc().GetProgramName(),
 		env.GetSystemProc().GetPid(),
 		SuffixMonitor)
 	baseDir := filepath.Join(env.GetFolderGuide().GetAppProcFolder(), FolderMonitor)
-	instance.filePath = filepath.Join(baseDir, fileName)
-	instance.historyPath = filepath.Join(baseDir, FolderHistory)
+	historyPath := filepath.Join(baseDir, FolderHistory)
 	ensureDirectory(baseDir, true)
-	ensureDirectory(instance.historyPath, true)
+	ensureDirectory(historyPath, true)
 
 	except := fmt.Sprintf("%s.%d", env.GetSystemProc().GetProgramName(), env.GetSystemProc().GetPid())
-	moveOldToHistory(env.GetSystemProc().GetProgramName(), baseDir, instance.historyPath, except)
+	moveOldToHistory(env.GetSystemProc().GetProgramName(), baseDir, historyPath, except)
+
+	instance := newMeasureFileWriterWith(filepath.Join(baseDir, fileName), historyPath, newMeasureFileConfig(config))
+	instance.process = env.GetSystemProc().GetProgramName()
+	instance.pid = env.GetSystemProc().GetPid()
 
 	go func() {
-		clearOldHistory(instance.historyPath)
+		clearOldHistory(instance.historyPath, instance.config.retention)
 	}()
 
 	return instance
 }
 
-// clearOldHistory delete old(before 24 hour) monitor files from history folder
-func clearOldHistory(path string) {
-	diff := 24 * time.Hour
-	deadline := time.Now().Add(-diff)
+func newMeasureFileWriterWith(filePath, historyPath string, config measureFileConfig) *MeasureFileWriter {
+	instance := new(MeasureFileWriter)
+	instance.filePath = filePath
+	instance.historyPath = historyPath
+	instance.config = config
+	if config.format != MonitorFormatJson {
+		instance.text = &rotateFile{path: filePath}
+	}
+	if config.format != MonitorFormatText {
+		instance.json = &rotateFile{path: filePath + suffixJsonLines}
+	}
+	return instance
+}
+
+// clearOldHistory delete old(before retention) monitor files from history folder
+func clearOldHistory(path string, retention time.Duration) {
+	deadline := time.Now().Add(-retention)
 
 	// find files in log path
 	entries, err := os.ReadDir(path)
@@ -107,46 +170,175 @@ func moveOldToHistory(procName string, folder string, history string, except str
 type MeasureFileWriter struct {
 	filePath    string
 	historyPath string
+	process     string
+	pid         int
+	config      measureFileConfig
+	text        *rotateFile // nil when format is json
+	json        *rotateFile // nil when format is text
+	lastClear   time.Time
 }
 
 func (this *MeasureFileWriter) write(msr measurement) {
-	checkSwitch(this.filePath)
+	if this.text != nil {
+		this.writeFile(this.text, this.textBlock(msr))
+	}
+	if this.json != nil {
+		b, err := this.jsonLine(msr)
+		if err != nil {
+			log.Warn("fail to marshal measurement : %s", err.Error())
+		} else {
+			this.writeFile(this.json, b)
+		}
+	}
+}
 
-	filePtr, err := os.OpenFile(this.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return
+func (this *MeasureFileWriter) writeFile(file *rotateFile, data []byte) {
+	if file.needRotate(this.config, time.Now()) {
+		log.Trace("switch monitor file %s", file.path)
+		file.rotate(this.config)
+		// history is cleared at most once a hour
+		if time.Since(this.lastClear) > time.Hour {
+			this.lastClear = time.Now()
+			clearOldHistory(this.historyPath, this.config.retention)
+		}
 	}
-	defer filePtr.Close()
+	file.append(data)
+}
 
+func (this *MeasureFileWriter) textBlock(msr measurement) []byte {
 	var buffer bytes.Buffer
 	// [2017/02/11 00:33:23]
 	buffer.WriteString("-------------------------------------------------------------------")
 	buffer.WriteByte('\n')
 	buffer.WriteByte('\n')
 	buffer.WriteString(fmt.Sprintf("[%s]\n", msr.eventTime.Format("2006-01-02 15:04:05")))
+	if len(msr.families) > 0 {
+		// metrics in text exposition format. every line starts with '#' or metric name
+		buffer.WriteString("[metrics]\n")
+		writeMetricText(&buffer, msr.families)
+	}
 	for _, v := range msr.items {
 		buffer.WriteString(fmt.Sprintf("[%s]\n", v.keyName))
 		buffer.WriteString(v.value)
 		buffer.WriteByte('\n')
 	}
-	filePtr.WriteString(buffer.String())
+	return buffer.Bytes()
 }
 
-func checkSwitch(active string) {
-	stat, err := os.Stat(active)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return
+// measureRecord json line of measurement
+type measureRecord struct {
+	Time     time.Time              `json:"time"`
+	Process  string                 `json:"process,omitempty"`
+	Pid      int                    `json:"pid,omitempty"`
+	Metrics  []monitor.MetricFamily `json:"metrics,omitempty"`
+	Measures map[string]string      `json:"measures,omitempty"`
+}
+
+func (this *MeasureFileWriter) jsonLine(msr measurement) ([]byte, error) {
+	record := measureRecord{Time: msr.eventTime, Process: this.process, Pid: this.pid, Metrics: msr.families}
+	if len(msr.items) > 0 {
+		record.Measures = make(map[string]string, len(msr.items))
+		for _, v := range msr.items {
+			record.Measures[v.keyName] = strings.TrimSpace(v.value)
 		}
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// rotateFile active monitor file. rotated to path.1 (path.1.gz), path.2, ...
+type rotateFile struct {
+	path     string
+	openedAt time.Time // first write time of active file
+}
+
+func (f *rotateFile) needRotate(config measureFileConfig, now time.Time) bool {
+	stat, err := os.Stat(f.path)
+	if err != nil {
+		f.openedAt = time.Time{}
+		return false
+	}
+	if f.openedAt.IsZero() {
+		f.openedAt = now
+	}
+	if config.rotateSize > 0 && stat.Size() > config.rotateSize {
+		return true
+	}
+	return config.rotateInterval > 0 && now.Sub(f.openedAt) >= config.rotateInterval
+}
+
+func (f *rotateFile) append(data []byte) {
+	filePtr, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
 		return
 	}
+	defer filePtr.Close()
+	filePtr.Write(data)
+}
 
-	if stat.Size() > MaxFileSize {
-		log.Trace("switch monitor file...")
-		backup := fmt.Sprintf("%s.backup", active)
-		os.Rename(active, backup)
+// rotate shift generations and move active file to generation 1. oldest generation is removed
+func (f *rotateFile) rotate(config measureFileConfig) {
+	f.openedAt = time.Time{}
+	removeGeneration(f.path, config.rotateCount)
+	for i := config.rotateCount - 1; i >= 1; i-- {
+		for _, suffix := range []string{"", ".gz"} {
+			from := generationPath(f.path, i) + suffix
+			if _, err := os.Stat(from); err == nil {
+				os.Rename(from, generationPath(f.path, i+1)+suffix)
+			}
+		}
+	}
+
+	first := generationPath(f.path, 1)
+	if err := os.Rename(f.path, first); err != nil {
+		log.Warn("fail to rotate monitor file : %s", err.Error())
 		return
 	}
+	if !config.compress {
+		return
+	}
+	if err := gzipFile(first); err != nil {
+		log.Warn("fail to compress monitor file : %s", err.Error())
+	}
+}
+
+func generationPath(path string, generation int) string {
+	return fmt.Sprintf("%s.%d", path, generation)
+}
+
+func removeGeneration(path string, generation int) {
+	_ = os.Remove(generationPath(path, generation))
+	_ = os.Remove(generationPath(path, generation) + ".gz")
+}
+
+// gzipFile compress file to file.gz and remove file
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(dst)
+	_, err = io.Copy(writer, src)
+	if e := writer.Close(); err == nil {
+		err = e
+	}
+	if e := dst.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
 }
 
 /*
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package infra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMeasurement() measurement {
+	return measurement{
+		eventTime: time.Date(2026, 10, 31, 10, 0, 0, 0, time.Local),
+		items:     []measureItem{{"fatima process", " :: Goroutines=10"}},
+		families: []monitor.MetricFamily{{Name: "fatima_process_goroutines", Type: monitor.MetricGauge,
+			Metrics: []monitor.Metric{{Value: 10}}}},
+	}
+}
+
+func TestMeasureFileRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.100.monitor")
+	config := measureFileConfig{rotateSize: 100, rotateCount: 2, retention: time.Hour, format: MonitorFormatText}
+	writer := newMeasureFileWriterWith(path, filepath.Join(dir, FolderHistory), config)
+
+	for i := 0; i < 10; i++ {
+		writer.write(testMeasurement())
+	}
+
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	// oldest generation is removed
+	assert.NoFileExists(t, path+".3")
+	assert.NoFileExists(t, path+suffixJsonLines)
+
+	b, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "[fatima process]\n :: Goroutines=10")
+}
+
+func TestMeasureFileRotateCompress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.100.monitor")
+	config := measureFileConfig{rotateSize: 100, rotateCount: 3, compress: true, retention: time.Hour, format: MonitorFormatText}
+	writer := newMeasureFileWriterWith(path, filepath.Join(dir, FolderHistory), config)
+
+	for i := 0; i < 3; i++ {
+		writer.write(testMeasurement())
+	}
+
+	assert.NoFileExists(t, path+".1")
+	assert.FileExists(t, path+".1.gz")
+	assert.FileExists(t, path+".2.gz")
+
+	file, err := os.Open(path + ".1.gz")
+	require.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "[2026-10-31 10:00:00]")
+}
+
+func TestMeasureFileRotateInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.100.monitor")
+	config := measureFileConfig{rotateInterval: time.Hour, rotateCount: 1, retention: time.Hour, format: MonitorFormatText}
+	writer := newMeasureFileWriterWith(path, filepath.Join(dir, FolderHistory), config)
+
+	writer.write(testMeasurement())
+	writer.write(testMeasurement())
+	assert.NoFileExists(t, path+".1")
+
+	writer.text.openedAt = time.Now().Add(-2 * time.Hour)
+	writer.write(testMeasurement())
+	assert.FileExists(t, path+".1")
+}
+
+func TestMeasureFileJsonLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.100.monitor")
+	config := measureFileConfig{rotateSize: MaxFileSize, rotateCount: 1, retention: time.Hour, format: MonitorFormatBoth}
+	writer := newMeasureFileWriterWith(path, filepath.Join(dir, FolderHistory), config)
+	writer.process = "app"
+	writer.pid = 100
+
+	writer.write(testMeasurement())
+	writer.write(testMeasurement())
+
+	assert.FileExists(t, path)
+	file, err := os.Open(path + suffixJsonLines)
+	require.NoError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+		record := measureRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "app", record.Process)
+		assert.Equal(t, 100, record.Pid)
+		assert.Equal(t, ":: Goroutines=10", record.Measures["fatima process"])
+		require.Len(t, record.Metrics, 1)
+		assert.Equal(t, monitor.MetricGauge, record.Metrics[0].Type)
+		assert.Equal(t, float64(10), record.Metrics[0].Metrics[0].Value)
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestClearOldHistory(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app.1.monitor")
+	recent := filepath.Join(dir, "app.2.monitor.1.gz")
+	require.NoError(t, os.WriteFile(old, []byte("x"), 0600))
+	require.NoError(t, os.WriteFile(recent, []byte("x"), 0600))
+	past := time.Now().Add(-50 * time.Hour)
+	require.NoError(t, os.Chtimes(old, past, past))
+
+	clearOldHistory(dir, 48*time.Hour)
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, recent)
+}
+
+func TestNewMeasureFileConfig(t *testing.T) {
+	config := newMeasureFileConfig(nil)
+	assert.Equal(t, int64(MaxFileSize), config.rotateSize)
+	assert.Equal(t, 1, config.rotateCount)
+	assert.Equal(t, 24*time.Hour, config.retention)
+	assert.Equal(t, MonitorFormatText, config.format)
+}
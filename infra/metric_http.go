@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오후 3:00
+ */
+
+package infra
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/ipc"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	metricsPath               = "/metrics"
+	contentTypeOpenMetrics    = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	contentTypePrometheusText = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// metricsService start metrics (prometheus, openmetrics) service if relative property exists
+func (i *DefaultProcessInteractor) metricsService() {
+	addr, ok := i.runtimeProcess.GetConfig().GetValue(builder.GofatimaPropMetricsAddress)
+	if !ok || len(addr) == 0 {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, newMetricsHandler(i.measurement.gather))
+	i.metricsServer = &http.Server{Addr: addr, Handler: mux}
+	go func(server *http.Server) {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Warn("fail to start metrics service : %s", err.Error())
+		}
+	}(i.metricsServer)
+	log.Info("starting metrics service. address=%s%s", addr, metricsPath)
+}
+
+func (i *DefaultProcessInteractor) stopMetricsService() {
+	if i.metricsServer != nil {
+		_ = i.metricsServer.Close()
+	}
+}
+
+// newMetricsHandler serve OpenMetrics when scraper accepts it, otherwise prometheus text format
+func newMetricsHandler(gather func() []monitor.MetricFamily) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var buffer bytes.Buffer
+		if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+			w.Header().Set("Content-Type", contentTypeOpenMetrics)
+			writeOpenMetricsText(&buffer, gather())
+		} else {
+			w.Header().Set("Content-Type", contentTypePrometheusText)
+			writeMetricText(&buffer, gather())
+		}
+		_, _ = w.Write(buffer.Bytes())
+	})
+}
+
+// runtimeMetricCollector metrics of fatima runtime facilities (ipc, notify)
+type runtimeMetricCollector struct {
+	notifyHandler monitor.SystemNotifyHandler
+}
+
+func newRuntimeMetricCollector(notifyHandler monitor.SystemNotifyHandler) *runtimeMetricCollector {
+	return &runtimeMetricCollector{notifyHandler: notifyHandler}
+}
+
+// notifyQueue notify handler which has sending queue. e.g) builder.GrpcSystemNotifyHandler
+type notifyQueue interface {
+	GetQueueSize() int
+	GetDroppedCount() uint64
+}
+
+func (c *runtimeMetricCollector) CollectMetrics() []monitor.MetricFamily {
+	families := []monitor.MetricFamily{
+		{Name: "fatima_ipc_sessions", Help: "number of connected ipc sessions", Type: monitor.MetricGauge,
+			Metrics: []monitor.Metric{{Value: float64(ipc.GetSessionCount())}}},
+	}
+	if queue, ok := c.notifyHandler.(notifyQueue); ok {
+		families = append(families,
+			monitor.MetricFamily{Name: "fatima_notify_queue_size", Help: "number of notify messages waiting to be sent", Type: monitor.MetricGauge,
+				Metrics: []monitor.Metric{{Value: float64(queue.GetQueueSize())}}},
+			monitor.MetricFamily{Name: "fatima_notify_dropped_total", Help: "number of notify messages dropped by full queue", Type: monitor.MetricCounter,
+				Metrics: []monitor.Metric{{Value: float64(queue.GetDroppedCount())}}})
+	}
+	return families
+}
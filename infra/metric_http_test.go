@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오후 3:00
+ */
+
+package infra
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNotifyQueue notify handler which has queue
+type testNotifyQueue struct {
+	monitor.SystemNotifyHandler
+}
+
+func (testNotifyQueue) GetQueueSize() int       { return 7 }
+func (testNotifyQueue) GetDroppedCount() uint64 { return 2 }
+
+func TestMetricsHandler(t *testing.T) {
+	mgmt := &SystemMeasureManagement{registry: newMetricRegistry()}
+	mgmt.registerUnit(&testMeasurable{})
+	mgmt.registry.RegisterCollector(newRuntimeMetricCollector(testNotifyQueue{}))
+	mgmt.registry.Counter("fatima_cron_job_runs_total", "number of cron job runs", monitor.Labels{"job": "daily", "result": "success"}).Inc()
+	mgmt.sample()
+
+	server := httptest.NewServer(newMetricsHandler(mgmt.gather))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		expected    []string
+	}{
+		{
+			name:        "prometheus",
+			contentType: contentTypePrometheusText,
+			expected: []string{
+				"# TYPE fatima_cron_job_runs_total counter\nfatima_cron_job_runs_total{job=\"daily\",result=\"success\"} 1\n",
+				"fatima_ipc_sessions 0\n",
+				"fatima_notify_queue_size 7\n",
+				"fatima_notify_dropped_total 2\n",
+				"legacy_unit_count 1\n",
+			},
+		},
+		{
+			name:        "openmetrics",
+			accept:      "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5",
+			contentType: contentTypeOpenMetrics,
+			expected: []string{
+				"# HELP fatima_cron_job_runs number of cron job runs\n# TYPE fatima_cron_job_runs counter\nfatima_cron_job_runs_total{job=\"daily\",result=\"success\"} 1\n",
+				"# TYPE fatima_notify_dropped counter\n",
+				"fatima_notify_dropped_total 2\n",
+				"legacy_unit_count 1\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, server.URL+metricsPath, nil)
+			require.NoError(t, err)
+			if len(tc.accept) > 0 {
+				request.Header.Set("Accept", tc.accept)
+			}
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, tc.contentType, response.Header.Get("Content-Type"))
+			for _, expected := range tc.expected {
+				assert.Contains(t, string(body), expected)
+			}
+			assert.Equal(t, tc.contentType == contentTypeOpenMetrics, strings.HasSuffix(string(body), "# EOF\n"))
+		})
+	}
+
+	response, err := http.Post(server.URL+metricsPath, "text/plain", nil)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+
+	// legacy unit is not measured by scrape
+	assert.Equal(t, 1, mgmt.units[0].unit.(*testMeasurable).count)
+}
@@ -0,0 +1,338 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오전 10:00
+ */
+
+package infra
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+// DefaultHistogramBuckets seconds. e.g) response time
+var DefaultHistogramBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewMetricRegistry return empty registry
+func NewMetricRegistry() monitor.MetricRegistry {
+	return newMetricRegistry()
+}
+
+func newMetricRegistry() *metricRegistry {
+	registry := new(metricRegistry)
+	registry.families = make(map[string]*metricFamily)
+	registry.collectors = make([]monitor.MetricCollector, 0)
+	return registry
+}
+
+type metricRegistry struct {
+	mutex      sync.Mutex
+	families   map[string]*metricFamily
+	collectors []monitor.MetricCollector
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	kind    monitor.MetricType
+	buckets []float64
+	metrics map[string]*metricValue // key : labels
+}
+
+type metricValue struct {
+	mutex  sync.Mutex
+	labels monitor.Labels
+	value  float64
+	fn     func() float64
+	counts []uint64 // histogram. last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func (r *metricRegistry) Counter(name, help string, labels monitor.Labels) monitor.Counter {
+	_, v := r.get(name, help, monitor.MetricCounter, nil, labels)
+	if v == nil {
+		return noopMetric{}
+	}
+	return counterMetric{v}
+}
+
+func (r *metricRegistry) Gauge(name, help string, labels monitor.Labels) monitor.Gauge {
+	_, v := r.get(name, help, monitor.MetricGauge, nil, labels)
+	if v == nil {
+		return noopMetric{}
+	}
+	return gaugeMetric{v}
+}
+
+func (r *metricRegistry) GaugeFunc(name, help string, labels monitor.Labels, fn func() float64) {
+	_, v := r.get(name, help, monitor.MetricGauge, nil, labels)
+	if v == nil || fn == nil {
+		return
+	}
+	v.mutex.Lock()
+	v.fn = fn
+	v.mutex.Unlock()
+}
+
+func (r *metricRegistry) Histogram(name, help string, buckets []float64, labels monitor.Labels) monitor.Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultHistogramBuckets
+	}
+	family, v := r.get(name, help, monitor.MetricHistogram, buckets, labels)
+	if v == nil {
+		return noopMetric{}
+	}
+	// buckets of family are not changed after creation
+	return histogramMetric{value: v, buckets: family.buckets}
+}
+
+func (r *metricRegistry) RegisterCollector(collector monitor.MetricCollector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.collectors = append(r.collectors, collector)
+}
+
+// get find or create metric. nil is returned when name is registered as other type
+func (r *metricRegistry) get(name, help string, kind monitor.MetricType, buckets []float64, labels monitor.Labels) (*metricFamily, *metricValue) {
+	name = sanitizeMetricName(name)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	family, ok := r.families[name]
+	if !ok {
+		family = &metricFamily{name: name, help: help, kind: kind, metrics: make(map[string]*metricValue)}
+		if kind == monitor.MetricHistogram {
+			family.buckets = append([]float64(nil), buckets...)
+			sort.Float64s(family.buckets)
+		}
+		r.families[name] = family
+	} else if family.kind != kind {
+		log.Warn("metric %s is already registered as %s. ignore %s", name, family.kind, kind)
+		return nil, nil
+	}
+
+	key := labelsKey(labels)
+	value, ok := family.metrics[key]
+	if !ok {
+		value = &metricValue{labels: copyLabels(labels)}
+		if kind == monitor.MetricHistogram {
+			value.counts = make([]uint64, len(family.buckets)+1)
+		}
+		family.metrics[key] = value
+	}
+	return family, value
+}
+
+func (r *metricRegistry) Gather() []monitor.MetricFamily {
+	r.mutex.Lock()
+	families := make([]monitor.MetricFamily, 0, len(r.families))
+	for _, family := range r.families {
+		families = append(families, family.snapshot())
+	}
+	collectors := append([]monitor.MetricCollector(nil), r.collectors...)
+	r.mutex.Unlock()
+
+	for _, collector := range collectors {
+		families = append(families, collector.CollectMetrics()...)
+	}
+	return mergeMetricFamilies(families)
+}
+
+// mergeMetricFamilies merge families of same name into first one and sort by name. e.g) families of several collectors.
+// family of other type and duplicated labels are dropped because exposition format allows single TYPE per name
+func mergeMetricFamilies(groups ...[]monitor.MetricFamily) []monitor.MetricFamily {
+	merged := make([]monitor.MetricFamily, 0)
+	index := make(map[string]int)
+	seen := make(map[string]map[string]bool)
+	for _, families := range groups {
+		for _, family := range families {
+			family.Name = sanitizeMetricName(family.Name)
+			i, ok := index[family.Name]
+			if !ok {
+				i = len(merged)
+				index[family.Name] = i
+				seen[family.Name] = make(map[string]bool, len(family.Metrics))
+				merged = append(merged, monitor.MetricFamily{Name: family.Name, Help: family.Help, Type: family.Type})
+			} else if merged[i].Type != family.Type {
+				log.Warn("metric %s is already gathered as %s. drop %s", family.Name, merged[i].Type, family.Type)
+				continue
+			}
+			if len(merged[i].Help) == 0 {
+				merged[i].Help = family.Help
+			}
+			for _, metric := range family.Metrics {
+				key := labelsKey(metric.Labels)
+				if seen[family.Name][key] {
+					log.Warn("metric %s%v is duplicated. drop", family.Name, metric.Labels)
+					continue
+				}
+				seen[family.Name][key] = true
+				merged[i].Metrics = append(merged[i].Metrics, metric)
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Name < merged[j].Name
+	})
+	return merged
+}
+
+func (f *metricFamily) snapshot() monitor.MetricFamily {
+	family := monitor.MetricFamily{Name: f.name, Help: f.help, Type: f.kind}
+	keys := make([]string, 0, len(f.metrics))
+	for key := range f.metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	family.Metrics = make([]monitor.Metric, 0, len(keys))
+	for _, key := range keys {
+		v := f.metrics[key]
+		metric := monitor.Metric{Labels: copyLabels(v.labels)}
+		v.mutex.Lock()
+		fn := v.fn
+		switch f.kind {
+		case monitor.MetricHistogram:
+			h := &monitor.HistogramSnapshot{Count: v.count, Sum: v.sum}
+			h.Buckets = make([]monitor.HistogramBucket, len(f.buckets))
+			var cumulative uint64
+			for i, bound := range f.buckets {
+				cumulative += v.counts[i]
+				h.Buckets[i] = monitor.HistogramBucket{UpperBound: bound, Count: cumulative}
+			}
+			metric.Histogram = h
+		default:
+			metric.Value = v.value
+		}
+		v.mutex.Unlock()
+		if fn != nil {
+			metric.Value = fn()
+		}
+		family.Metrics = append(family.Metrics, metric)
+	}
+	return family
+}
+
+type counterMetric struct {
+	*metricValue
+}
+
+func (c counterMetric) Inc() {
+	c.Add(1)
+}
+
+func (c counterMetric) Add(delta float64) {
+	if delta < 0 || math.IsNaN(delta) {
+		return
+	}
+	c.mutex.Lock()
+	c.value += delta
+	c.mutex.Unlock()
+}
+
+type gaugeMetric struct {
+	*metricValue
+}
+
+func (g gaugeMetric) Set(value float64) {
+	g.mutex.Lock()
+	g.value = value
+	g.mutex.Unlock()
+}
+
+func (g gaugeMetric) Add(delta float64) {
+	g.mutex.Lock()
+	g.value += delta
+	g.mutex.Unlock()
+}
+
+type histogramMetric struct {
+	value   *metricValue
+	buckets []float64
+}
+
+func (h histogramMetric) Observe(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.value.mutex.Lock()
+	h.value.counts[i]++
+	h.value.count++
+	h.value.sum += value
+	h.value.mutex.Unlock()
+}
+
+// noopMetric returned when metric cannot be registered
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Add(float64)     {}
+func (noopMetric) Set(float64)     {}
+func (noopMetric) Observe(float64) {}
+
+// sanitizeMetricName replace invalid characters with '_'. e.g) "fatima process" -> "fatima_process"
+func sanitizeMetricName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
+
+func labelsKey(labels monitor.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(0)
+		sb.WriteString(labels[k])
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+func copyLabels(labels monitor.Labels) monitor.Labels {
+	if len(labels) == 0 {
+		return nil
+	}
+	copied := make(monitor.Labels, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
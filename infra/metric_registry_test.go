@@ -0,0 +1,153 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오전 10:00
+ */
+
+package infra
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricRegistry(t *testing.T) {
+	registry := newMetricRegistry()
+
+	// same name and labels return same metric
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				registry.Counter("requests_total", "processed requests", monitor.Labels{"api": "order"}).Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	registry.Counter("requests_total", "", monitor.Labels{"api": "order"}).Add(-5)
+	registry.Counter("requests_total", "", nil).Add(2)
+
+	queue := registry.Gauge("queue size", "", nil)
+	queue.Set(10)
+	queue.Add(-3)
+	registry.GaugeFunc("pool_active", "", monitor.Labels{"pool": "db"}, func() float64 { return 4 })
+
+	// type conflict is ignored
+	registry.Gauge("requests_total", "", nil).Set(100)
+
+	latency := registry.Histogram("latency_seconds", "", []float64{1, 0.1, 0.5}, nil)
+	for _, v := range []float64{0.05, 0.1, 0.3, 2} {
+		latency.Observe(v)
+	}
+
+	families := registry.Gather()
+	require.Len(t, families, 4)
+	assert.Equal(t, []string{"latency_seconds", "pool_active", "queue_size", "requests_total"},
+		[]string{families[0].Name, families[1].Name, families[2].Name, families[3].Name})
+
+	h := families[0].Metrics[0].Histogram
+	require.NotNil(t, h)
+	assert.Equal(t, uint64(4), h.Count)
+	assert.InDelta(t, 2.45, h.Sum, 1e-9)
+	assert.Equal(t, []monitor.HistogramBucket{{UpperBound: 0.1, Count: 2}, {UpperBound: 0.5, Count: 3}, {UpperBound: 1, Count: 3}}, h.Buckets)
+
+	assert.Equal(t, monitor.Metric{Labels: monitor.Labels{"pool": "db"}, Value: 4}, families[1].Metrics[0])
+	assert.Equal(t, float64(7), families[2].Metrics[0].Value)
+
+	requests := families[3]
+	assert.Equal(t, monitor.MetricCounter, requests.Type)
+	assert.Equal(t, "processed requests", requests.Help)
+	require.Len(t, requests.Metrics, 2)
+	assert.Equal(t, float64(2), requests.Metrics[0].Value)
+	assert.Equal(t, float64(1000), requests.Metrics[1].Value)
+
+	// type is encoded as name
+	b, err := json.Marshal(requests)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"type":"counter"`)
+	decoded := monitor.MetricFamily{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, requests, decoded)
+}
+
+// testCollector provides fixed family
+type testCollector struct{}
+
+func (testCollector) CollectMetrics() []monitor.MetricFamily {
+	return []monitor.MetricFamily{{Name: "a_collected", Type: monitor.MetricGauge, Metrics: []monitor.Metric{{Value: 1}}}}
+}
+
+func TestMetricRegistryCollector(t *testing.T) {
+	registry := newMetricRegistry()
+	registry.Counter("b_total", "", nil).Inc()
+	registry.RegisterCollector(testCollector{})
+
+	families := registry.Gather()
+	require.Len(t, families, 2)
+	assert.Equal(t, "a_collected", families[0].Name)
+	assert.Equal(t, "b_total", families[1].Name)
+}
+
+// testNamedCollector return families of name. type and labels are given by test
+type testNamedCollector struct {
+	kind   monitor.MetricType
+	labels monitor.Labels
+}
+
+func (c testNamedCollector) CollectMetrics() []monitor.MetricFamily {
+	return []monitor.MetricFamily{{Name: "fatima_latency_count", Help: "latency count", Type: c.kind,
+		Metrics: []monitor.Metric{{Labels: c.labels, Value: 1}}}}
+}
+
+func TestMetricRegistryMergeFamilies(t *testing.T) {
+	registry := newMetricRegistry()
+	registry.Gauge("fatima_latency_count", "", monitor.Labels{"marker": "user"}).Set(3)
+	registry.RegisterCollector(testNamedCollector{kind: monitor.MetricGauge, labels: monitor.Labels{"marker": "a"}})
+	registry.RegisterCollector(testNamedCollector{kind: monitor.MetricGauge, labels: monitor.Labels{"marker": "b"}})
+	// duplicated labels and other type are dropped
+	registry.RegisterCollector(testNamedCollector{kind: monitor.MetricGauge, labels: monitor.Labels{"marker": "b"}})
+	registry.RegisterCollector(testNamedCollector{kind: monitor.MetricCounter, labels: monitor.Labels{"marker": "c"}})
+
+	families := registry.Gather()
+	require.Len(t, families, 1)
+	assert.Equal(t, "latency count", families[0].Help)
+	assert.Equal(t, monitor.MetricGauge, families[0].Type)
+	require.Len(t, families[0].Metrics, 3)
+	assert.Equal(t, "user", families[0].Metrics[0].Labels["marker"])
+	assert.Equal(t, "a", families[0].Metrics[1].Labels["marker"])
+	assert.Equal(t, "b", families[0].Metrics[2].Labels["marker"])
+
+	var buffer bytes.Buffer
+	writeMetricFamilies(&buffer, families, false)
+	assert.Equal(t, 1, strings.Count(buffer.String(), "# TYPE fatima_latency_count"))
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	assert.Equal(t, "fatima_process", sanitizeMetricName("fatima process"))
+	assert.Equal(t, "_lives:x", sanitizeMetricName("9lives:x"))
+	assert.Equal(t, "a_b_c", sanitizeMetricName("a.b-c"))
+	assert.Equal(t, "_", sanitizeMetricName(""))
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오전 10:00
+ */
+
+package infra
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fatima-go/fatima-core/monitor"
+)
+
+// writeMetricText write families in text exposition format. e.g)
+//
+//	# TYPE fatima_process_heap_alloc_bytes gauge
+//	fatima_process_heap_alloc_bytes 16809760
+//	request_seconds_bucket{api="order",le="0.1"} 3
+func writeMetricText(buffer *bytes.Buffer, families []monitor.MetricFamily) {
+	writeMetricFamilies(buffer, families, false)
+}
+
+// writeOpenMetricsText write families in OpenMetrics text format.
+// counter family name has no '_total' suffix and sample has. text ends with '# EOF'
+func writeOpenMetricsText(buffer *bytes.Buffer, families []monitor.MetricFamily) {
+	writeMetricFamilies(buffer, families, true)
+	buffer.WriteString("# EOF\n")
+}
+
+func writeMetricFamilies(buffer *bytes.Buffer, families []monitor.MetricFamily, openMetrics bool) {
+	for _, family := range families {
+		name, sampleName := family.Name, family.Name
+		if openMetrics && family.Type == monitor.MetricCounter {
+			name = strings.TrimSuffix(family.Name, "_total")
+			sampleName = name + "_total"
+		}
+		if len(family.Help) > 0 {
+			buffer.WriteString("# HELP ")
+			buffer.WriteString(name)
+			buffer.WriteByte(' ')
+			buffer.WriteString(escapeMetricHelp(family.Help))
+			buffer.WriteByte('\n')
+		}
+		buffer.WriteString("# TYPE ")
+		buffer.WriteString(name)
+		buffer.WriteByte(' ')
+		buffer.WriteString(family.Type.String())
+		buffer.WriteByte('\n')
+
+		for _, metric := range family.Metrics {
+			if metric.Histogram == nil {
+				writeMetricSample(buffer, sampleName, metric.Labels, "", "", metric.Value)
+				continue
+			}
+			for _, bucket := range metric.Histogram.Buckets {
+				writeMetricSample(buffer, family.Name+"_bucket", metric.Labels, "le", formatMetricValue(bucket.UpperBound), float64(bucket.Count))
+			}
+			writeMetricSample(buffer, family.Name+"_bucket", metric.Labels, "le", "+Inf", float64(metric.Histogram.Count))
+			writeMetricSample(buffer, family.Name+"_sum", metric.Labels, "", "", metric.Histogram.Sum)
+			writeMetricSample(buffer, family.Name+"_count", metric.Labels, "", "", float64(metric.Histogram.Count))
+		}
+	}
+}
+
+func writeMetricSample(buffer *bytes.Buffer, name string, labels monitor.Labels, extraKey, extraValue string, value float64) {
+	buffer.WriteString(name)
+	if len(labels) > 0 || len(extraKey) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buffer.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeMetricLabel(buffer, k, labels[k])
+		}
+		if len(extraKey) > 0 {
+			if len(keys) > 0 {
+				buffer.WriteByte(',')
+			}
+			writeMetricLabel(buffer, extraKey, extraValue)
+		}
+		buffer.WriteByte('}')
+	}
+	buffer.WriteByte(' ')
+	buffer.WriteString(formatMetricValue(value))
+	buffer.WriteByte('\n')
+}
+
+func writeMetricLabel(buffer *bytes.Buffer, key, value string) {
+	buffer.WriteString(sanitizeMetricName(key))
+	buffer.WriteString(`="`)
+	buffer.WriteString(escapeMetricLabel(value))
+	buffer.WriteByte('"')
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	// integer (e.g. counter, bytes) without exponent
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var metricHelpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeMetricLabel(value string) string {
+	return metricLabelEscaper.Replace(value)
+}
+
+func escapeMetricHelp(value string) string {
+	return metricHelpEscaper.Replace(value)
+}
+
+// measurableFamilies adapt text of SystemMeasurable to gauges. numeric key=value pairs are used.
+// e.g) key 'RestClient', text ":: lease=3, pending=0" -> restclient_lease 3, restclient_pending 0
+func measurableFamilies(keyName, text string) []monitor.MetricFamily {
+	families := make([]monitor.MetricFamily, 0)
+	seen := make(map[string]bool)
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '|' || unicode.IsSpace(r)
+	})
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || len(key) == 0 {
+			continue
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		name := sanitizeMetricName(strings.ToLower(keyName) + "_" + key)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		families = append(families, monitor.MetricFamily{
+			Name:    name,
+			Type:    monitor.MetricGauge,
+			Metrics: []monitor.Metric{{Value: number}},
+		})
+	}
+	return families
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오전 10:00
+ */
+
+package infra
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetricText(t *testing.T) {
+	registry := newMetricRegistry()
+	registry.Counter("requests_total", "processed\nrequests", monitor.Labels{"api": `a"b`, "method": "get"}).Add(3)
+	registry.Gauge("heap_bytes", "", nil).Set(16809760)
+	registry.Histogram("latency_seconds", "", []float64{0.1, 1}, monitor.Labels{"api": "x"}).Observe(0.5)
+
+	var buffer bytes.Buffer
+	writeMetricText(&buffer, registry.Gather())
+	expected := `# TYPE heap_bytes gauge
+heap_bytes 16809760
+# TYPE latency_seconds histogram
+latency_seconds_bucket{api="x",le="0.1"} 0
+latency_seconds_bucket{api="x",le="1"} 1
+latency_seconds_bucket{api="x",le="+Inf"} 1
+latency_seconds_sum{api="x"} 0.5
+latency_seconds_count{api="x"} 1
+# HELP requests_total processed\nrequests
+# TYPE requests_total counter
+requests_total{api="a\"b",method="get"} 3
+`
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestMeasurableFamilies(t *testing.T) {
+	families := measurableFamilies("RestClient", ":: lease=3, pending=0, available=x, max=010 [total]\n:: lease=1")
+	require.Len(t, families, 3)
+	assert.Equal(t, "restclient_lease", families[0].Name)
+	assert.Equal(t, float64(3), families[0].Metrics[0].Value)
+	assert.Equal(t, "restclient_pending", families[1].Name)
+	assert.Equal(t, "restclient_max", families[2].Name)
+	assert.Equal(t, float64(10), families[2].Metrics[0].Value)
+
+	// table style text has no metric
+	assert.Empty(t, measurableFamilies("marker", "  100|  200|TOTAL|\n    1|    2|    3|"))
+}
+
+// testMeasurable legacy unit which resets value when measured
+type testMeasurable struct {
+	count int
+}
+
+func (m *testMeasurable) GetKeyName() string {
+	return "legacy unit"
+}
+
+func (m *testMeasurable) GetMeasure() string {
+	m.count++
+	return ":: count=" + strconv.Itoa(m.count)
+}
+
+func TestSystemMeasureSample(t *testing.T) {
+	mgmt := &SystemMeasureManagement{registry: newMetricRegistry()}
+	unit := &testMeasurable{}
+	mgmt.registerUnit(unit)
+	process := newProcessMeasurement(nil)
+	process.readResource = nil
+	mgmt.registerUnit(process)
+	mgmt.registry.Counter("app_events_total", "", nil).Inc()
+
+	msr := mgmt.sample()
+	// text of legacy unit is read once
+	assert.Equal(t, 1, unit.count)
+	require.Len(t, msr.items, 2)
+	assert.Equal(t, measureItem{"legacy unit", ":: count=1"}, msr.items[0])
+
+	names := make([]string, 0)
+	for _, family := range msr.families {
+		names = append(names, family.Name)
+	}
+	assert.Equal(t, []string{"app_events_total", "fatima_process_gc_pause_seconds", "fatima_process_gc_total",
+		"fatima_process_goroutines", "fatima_process_heap_alloc_bytes", "fatima_process_sched_latency_seconds",
+		"fatima_process_sys_bytes", "legacy_unit_count"}, names)
+}
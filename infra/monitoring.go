@@ -21,16 +21,48 @@
 package infra
 
 import (
+	"path/filepath"
+	"sync"
+	"time"
+
 	"github.com/fatima-go/fatima-core/builder"
 	"github.com/fatima-go/fatima-core/monitor"
 	"github.com/fatima-go/fatima-log"
 )
 
+const (
+	// systemStatusDebounce wait time after last change event. bursts of file events are checked once
+	systemStatusDebounce = 20 * time.Millisecond
+	deliveryQueueSize    = 64
+)
+
 type SystemAwareManagement struct {
 	runtimeProcess *builder.FatimaRuntimeProcess
 	monitor        monitor.SystemStatusMonitor
+	watcher        monitor.SystemStatusWatcher
 	awareHA        []monitor.FatimaSystemHAAware
 	awarePS        []monitor.FatimaSystemPSAware
+	// HA transition participants and status
+	awareTransition   []monitor.FatimaSystemHATransitionAware
+	transitionTimeout time.Duration
+	haFolder          string
+	notifyHandler     monitor.SystemNotifyHandler
+	haMutex           sync.Mutex
+	abortedHA         monitor.HAStatus
+	haAborted         bool
+	pendingHA         monitor.HAStatus
+	haPending         bool
+	checkMutex        sync.Mutex
+	debounceMutex     sync.Mutex
+	debounce          time.Duration
+	debounceTimer     *time.Timer
+	watchFailed       bool
+	closed            bool
+	deliveryMutex     sync.Mutex
+	deliveries        []func()
+	deliveryWake      chan struct{}
+	deliveryStop      chan struct{}
+	deliveryDone      chan struct{}
 }
 
 func newSystemAwareManagement(runtimeProcess *builder.FatimaRuntimeProcess, mon monitor.SystemStatusMonitor) *SystemAwareManagement {
@@ -41,7 +73,13 @@ func newSystemAwareManagement(runtimeProcess *builder.FatimaRuntimeProcess, mon
 	instance.awareHA = make([]monitor.FatimaSystemHAAware, 0)
 	// awarePS observers
 	instance.awarePS = make([]monitor.FatimaSystemPSAware, 0)
+	instance.awareTransition = make([]monitor.FatimaSystemHATransitionAware, 0)
+	instance.transitionTimeout = defaultHATransitionTimeout
+	if env := runtimeProcess.GetEnv(); env != nil && env.GetFolderGuide() != nil {
+		instance.haFolder = filepath.Join(env.GetFolderGuide().GetFatimaHome(), "package", "cfm", "ha")
+	}
 	instance.monitor = mon
+	instance.debounce = systemStatusDebounce
 	currentStatus := runtimeProcess.GetSystemStatus().(*builder.FatimaPackageSystemStatus)
 
 	ps, _ := mon.GetPSStatus()
@@ -49,6 +87,18 @@ func newSystemAwareManagement(runtimeProcess *builder.FatimaRuntimeProcess, mon
 	ha, _ := mon.GetHAStatus()
 	currentStatus.SetHAStatus(ha)
 
+	// status changes are delivered by single goroutine in order of detection
+	instance.deliveries = make([]func(), 0, deliveryQueueSize)
+	instance.deliveryWake = make(chan struct{}, 1)
+	instance.deliveryStop = make(chan struct{})
+	instance.deliveryDone = make(chan struct{})
+	go instance.deliver()
+
+	if watcher, ok := mon.(monitor.SystemStatusWatcher); ok {
+		instance.watcher = watcher
+		instance.watch()
+	}
+
 	return instance
 }
 
@@ -56,6 +106,17 @@ func (s *SystemAwareManagement) RegisterSystemHAAware(aware monitor.FatimaSystem
 	s.awareHA = append(s.awareHA, aware)
 }
 
+func (s *SystemAwareManagement) RegisterSystemHATransitionAware(aware monitor.FatimaSystemHATransitionAware) {
+	s.awareTransition = append(s.awareTransition, aware)
+}
+
+// SetHATransitionTimeout timeout of prepare and commit phases
+func (s *SystemAwareManagement) SetHATransitionTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.transitionTimeout = timeout
+	}
+}
+
 func (s *SystemAwareManagement) RegisterSystemPSAware(aware monitor.FatimaSystemPSAware) {
 	s.awarePS = append(s.awarePS, aware)
 }
@@ -74,7 +135,55 @@ func (s *SystemAwareManagement) SystemPSStatusChanged(newPSStatus monitor.PSStat
 	}
 }
 
+// watch start event driven status check. status is polled when watch fails
+func (s *SystemAwareManagement) watch() {
+	err := s.watcher.Watch(s.changed)
+	if err != nil {
+		if !s.watchFailed {
+			log.Warn("fail to watch system status. polling status : %s", err.Error())
+		}
+		s.watchFailed = true
+		return
+	}
+	s.watchFailed = false
+	log.Info("watching system status")
+	// changes between initial read and watch start
+	s.changed()
+}
+
+// changed debounce change events
+func (s *SystemAwareManagement) changed() {
+	s.debounceMutex.Lock()
+	defer s.debounceMutex.Unlock()
+	if s.closed {
+		return
+	}
+	if s.debounceTimer == nil {
+		s.debounceTimer = time.AfterFunc(s.debounce, s.check)
+		return
+	}
+	s.debounceTimer.Reset(s.debounce)
+}
+
+// Process poll status every second while status is not watched
 func (s *SystemAwareManagement) Process() {
+	if s.watcher != nil {
+		if s.watcher.Watching() {
+			return
+		}
+		// try to watch again (e.g. cfm folder is recreated)
+		s.watch()
+		if s.watcher.Watching() {
+			return
+		}
+	}
+	s.check()
+}
+
+func (s *SystemAwareManagement) check() {
+	s.checkMutex.Lock()
+	defer s.checkMutex.Unlock()
+
 	currentStatus := s.runtimeProcess.GetSystemStatus().(*builder.FatimaPackageSystemStatus)
 
 	// check and deliver PS change
@@ -82,29 +191,105 @@ func (s *SystemAwareManagement) Process() {
 		oldps := currentStatus.GetPSStatus()
 		if oldps != ps {
 			currentStatus.SetPSStatus(ps)
-			go func() {
+			s.enqueue(func() {
 				s.SystemPSStatusChanged(ps)
-			}()
+			})
 		}
 	}
 
-	// check and deliver HA change
+	// check and deliver HA change. aborted transition is not retried until status is changed again.
+	// runtime status is changed by transitHA after commit. pending status prevents duplicated delivery
 	if ha, ok := s.monitor.GetHAStatus(); ok {
-		oldha := currentStatus.GetHAStatus()
-		if oldha != ha {
-			currentStatus.SetHAStatus(ha)
-			go func() {
-				s.SystemHAStatusChanged(ha)
-			}()
+		s.haMutex.Lock()
+		if s.haAborted && s.abortedHA != ha {
+			s.haAborted = false
+		}
+		target := currentStatus.GetHAStatus()
+		if s.haPending {
+			target = s.pendingHA
+		}
+		changed := target != ha && !s.haAborted
+		if changed {
+			s.pendingHA, s.haPending = ha, true
+		}
+		s.haMutex.Unlock()
+		if changed {
+			s.enqueue(func() {
+				s.transitHA(ha)
+			})
 		}
 	}
 
 	// check and deliver loglevel change
 	if logLevel, ok := s.monitor.GetLogLevel(); ok {
-		if s.runtimeProcess.GetLogLevel() != logLevel {
-			log.SetLevel(logLevel)
-			log.Warn("fatima proc log level : %s", logLevel)
+		// compare with base level. temporary level (ipc, api) should not be reverted by cfm polling
+		if s.runtimeProcess.GetBaseLogLevel() != logLevel {
 			s.runtimeProcess.SetLogLevel(logLevel)
 		}
 	}
 }
+
+// enqueue add delivery to queue. it never blocks so that check is not blocked by slow transition
+func (s *SystemAwareManagement) enqueue(deliver func()) {
+	s.deliveryMutex.Lock()
+	s.deliveries = append(s.deliveries, deliver)
+	s.deliveryMutex.Unlock()
+
+	select {
+	case s.deliveryWake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver run queued deliveries in order until close
+func (s *SystemAwareManagement) deliver() {
+	defer close(s.deliveryDone)
+	for {
+		select {
+		case <-s.deliveryStop:
+			return
+		case <-s.deliveryWake:
+		}
+
+		for {
+			s.deliveryMutex.Lock()
+			if len(s.deliveries) == 0 {
+				s.deliveryMutex.Unlock()
+				break
+			}
+			deliver := s.deliveries[0]
+			s.deliveries[0] = nil
+			s.deliveries = s.deliveries[1:]
+			s.deliveryMutex.Unlock()
+
+			select {
+			case <-s.deliveryStop:
+				return
+			default:
+			}
+			deliver()
+		}
+	}
+}
+
+// close stop watching, wait running check and running delivery. queued deliveries are discarded
+func (s *SystemAwareManagement) close() {
+	if s.watcher != nil {
+		_ = s.watcher.Close()
+	}
+	s.debounceMutex.Lock()
+	alreadyClosed := s.closed
+	s.closed = true
+	if s.debounceTimer != nil {
+		s.debounceTimer.Stop()
+	}
+	s.debounceMutex.Unlock()
+
+	s.checkMutex.Lock()
+	if !alreadyClosed {
+		close(s.deliveryStop)
+	}
+	s.checkMutex.Unlock()
+
+	<-s.deliveryDone
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 27. 오전 11:00
+ */
+
+package infra
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCfmEnv env which has only fatima home
+type testCfmEnv struct {
+	fatima.FatimaEnv
+	home string
+}
+
+func (e testCfmEnv) GetFolderGuide() fatima.FolderGuide {
+	return testCfmFolderGuide{home: e.home}
+}
+
+type testCfmFolderGuide struct {
+	fatima.FolderGuide
+	home string
+}
+
+func (g testCfmFolderGuide) GetFatimaHome() string {
+	return g.home
+}
+
+// testStatusWatcher SystemStatusWatcher whose status is changed by test
+type testStatusWatcher struct {
+	mutex    sync.Mutex
+	ha       monitor.HAStatus
+	ps       monitor.PSStatus
+	reads    int
+	changed  func()
+	watching bool
+	watchErr error
+}
+
+func (w *testStatusWatcher) GetPSStatus() (monitor.PSStatus, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.ps, true
+}
+
+func (w *testStatusWatcher) GetHAStatus() (monitor.HAStatus, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.reads++
+	return w.ha, true
+}
+
+func (w *testStatusWatcher) GetLogLevel() (log.LogLevel, bool) {
+	return log.LOG_NONE, false
+}
+
+func (w *testStatusWatcher) Watch(changed func()) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.watchErr != nil {
+		return w.watchErr
+	}
+	w.changed, w.watching = changed, true
+	return nil
+}
+
+func (w *testStatusWatcher) Watching() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.watching
+}
+
+func (w *testStatusWatcher) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.watching = false
+	return nil
+}
+
+func (w *testStatusWatcher) setHA(ha monitor.HAStatus) {
+	w.mutex.Lock()
+	w.ha = ha
+	w.mutex.Unlock()
+}
+
+func (w *testStatusWatcher) getReads() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.reads
+}
+
+// slowHAAware record delivered status. first delivery is slow
+type slowHAAware struct {
+	mutex     sync.Mutex
+	delivered []monitor.HAStatus
+}
+
+func (a *slowHAAware) SystemHAStatusChanged(newHAStatus monitor.HAStatus) {
+	if len(a.get()) == 0 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.delivered = append(a.delivered, newHAStatus)
+}
+
+func (a *slowHAAware) get() []monitor.HAStatus {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]monitor.HAStatus(nil), a.delivered...)
+}
+
+func TestSystemAwareOrderedDelivery(t *testing.T) {
+	watcher := &testStatusWatcher{ha: monitor.HA_STATUS_STANDBY, ps: monitor.PS_STATUS_SECONDARY}
+	s := newSystemAwareManagement(builder.NewFatimaRuntime(), watcher)
+	defer s.close()
+	aware := &slowHAAware{}
+	s.RegisterSystemHAAware(aware)
+	require.True(t, watcher.Watching())
+
+	// flips are delivered in order even though first delivery is slow
+	flips := []monitor.HAStatus{monitor.HA_STATUS_ACTIVE, monitor.HA_STATUS_STANDBY, monitor.HA_STATUS_ACTIVE}
+	for _, ha := range flips {
+		watcher.setHA(ha)
+		s.check()
+	}
+	require.Eventually(t, func() bool { return len(aware.get()) == len(flips) }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, flips, aware.get())
+
+	// polling is skipped while watching
+	reads := watcher.getReads()
+	s.Process()
+	assert.Equal(t, reads, watcher.getReads())
+}
+
+func TestSystemAwareDebounce(t *testing.T) {
+	watcher := &testStatusWatcher{ha: monitor.HA_STATUS_STANDBY, ps: monitor.PS_STATUS_SECONDARY}
+	s := newSystemAwareManagement(builder.NewFatimaRuntime(), watcher)
+	defer s.close()
+	aware := &slowHAAware{}
+	s.RegisterSystemHAAware(aware)
+	time.Sleep(3 * systemStatusDebounce) // initial check after watch
+	reads := watcher.getReads()
+
+	// burst of events : flip and flip back is checked once and nothing is delivered
+	watcher.setHA(monitor.HA_STATUS_ACTIVE)
+	watcher.changed()
+	watcher.changed()
+	watcher.setHA(monitor.HA_STATUS_STANDBY)
+	watcher.changed()
+	time.Sleep(3 * systemStatusDebounce)
+	assert.Equal(t, reads+1, watcher.getReads())
+	assert.Empty(t, aware.get())
+
+	watcher.setHA(monitor.HA_STATUS_ACTIVE)
+	watcher.changed()
+	require.Eventually(t, func() bool { return len(aware.get()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_ACTIVE), aware.get()[0])
+}
+
+func TestSystemAwarePollingFallback(t *testing.T) {
+	watcher := &testStatusWatcher{ha: monitor.HA_STATUS_STANDBY, ps: monitor.PS_STATUS_SECONDARY, watchErr: errors.New("not supported")}
+	s := newSystemAwareManagement(builder.NewFatimaRuntime(), watcher)
+	defer s.close()
+	aware := &slowHAAware{}
+	s.RegisterSystemHAAware(aware)
+	require.False(t, watcher.Watching())
+
+	watcher.setHA(monitor.HA_STATUS_ACTIVE)
+	s.Process()
+	require.Eventually(t, func() bool { return len(aware.get()) == 1 }, time.Second, 5*time.Millisecond)
+
+	// watch is retried by polling
+	watcher.mutex.Lock()
+	watcher.watchErr = nil
+	watcher.mutex.Unlock()
+	s.Process()
+	assert.True(t, watcher.Watching())
+}
@@ -27,6 +27,7 @@ import (
 	"os"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
 	log "github.com/fatima-go/fatima-log"
 )
 
@@ -48,6 +49,14 @@ func StartIPCService(fr fatima.FatimaRuntime,
 	registerGoAwaySessionListener()
 	// register cron listener
 	registerCronListener()
+	// register loglevel listener
+	if controller, ok := fr.(fatima.FatimaLogLevelController); ok {
+		registerLogLevelListener(controller)
+	}
+	// register system status (cfm) listener
+	if provider, ok := fr.(monitor.SystemStatusManagerProvider); ok {
+		registerSystemStatusListener(provider)
+	}
 
 	// start server
 	startIPCServer()
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 26. 오전 11:30
+ */
+
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	log "github.com/fatima-go/fatima-log"
+)
+
+func registerLogLevelListener(controller fatima.FatimaLogLevelController) {
+	RegisterIPCSessionListener(newLogLevelListener(controller))
+}
+
+func newLogLevelListener(controller fatima.FatimaLogLevelController) FatimaIPCSessionListener {
+	return &LogLevelListener{controller: controller}
+}
+
+// LogLevelListener change log level by LOGLEVEL command and reply LOGLEVEL_DONE with current status
+type LogLevelListener struct {
+	controller fatima.FatimaLogLevelController
+}
+
+func (g *LogLevelListener) StartSession(ctx SessionContext) {
+	log.Trace("[%s] start session", ctx)
+}
+
+func (g *LogLevelListener) OnClose(ctx SessionContext) {
+	log.Trace("[%s] on close", ctx)
+}
+
+func (g *LogLevelListener) OnReceiveCommand(ctx SessionContext, message Message) {
+	log.Trace("IPC command incoming : %s", message)
+
+	if !message.Is(CommandLogLevel) {
+		return
+	}
+
+	defer ctx.Close()
+
+	log.Warn("IPC process LogLevel : %s", message)
+	err := g.execute(message)
+	if err != nil {
+		log.Warn("[%s] fail to process loglevel : %s", ctx, err.Error())
+	}
+
+	err = ctx.SendCommand(NewMessageLogLevelDone(g.controller.GetLogLevelStatus(), err))
+	if err != nil {
+		log.Warn("[%s] fail to send loglevel done : %s", ctx, err.Error())
+	}
+}
+
+func (g *LogLevelListener) execute(message Message) error {
+	logger := AsString(message.Data.GetValue(DataKeyLogger))
+	action := AsString(message.Data.GetValue(DataKeyAction))
+	switch action {
+	case LogLevelActionStatus:
+		return nil
+	case LogLevelActionRevert:
+		if len(logger) > 0 {
+			g.controller.RevertLoggerLevel(logger, fatima.LogLevelSourceIPC)
+		} else {
+			g.controller.RevertLogLevel(fatima.LogLevelSourceIPC)
+		}
+		return nil
+	case LogLevelActionSet:
+	default:
+		return fmt.Errorf("unknown action [%s]", action)
+	}
+
+	levelName := AsString(message.Data.GetValue(DataKeyLogLevel))
+	level := log.ConvertStringToLogLevel(levelName)
+	if level == log.LOG_NONE {
+		// cfm style hexa value (e.g. 0x2F)
+		level, _ = log.ConvertHexaToLogLevel(levelName)
+	}
+	if level == log.LOG_NONE {
+		return fmt.Errorf("invalid log level [%s]", levelName)
+	}
+
+	var ttl time.Duration
+	if v := AsString(message.Data.GetValue(DataKeyTTL)); len(v) > 0 {
+		var err error
+		if ttl, err = time.ParseDuration(v); err != nil {
+			// seconds
+			sec, e1 := strconv.Atoi(v)
+			if e1 != nil {
+				return fmt.Errorf("invalid ttl [%s] : %s", v, err.Error())
+			}
+			ttl = time.Duration(sec) * time.Second
+		}
+	}
+
+	if len(logger) > 0 {
+		return g.controller.ChangeLoggerLevel(logger, level, ttl, fatima.LogLevelSourceIPC)
+	}
+	return g.controller.ChangeLogLevel(level, ttl, fatima.LogLevelSourceIPC)
+}
+
+// RequestLogLevel send LOGLEVEL command to process and return log level status of process
+func RequestLogLevel(proc string, message Message, timeout time.Duration) (fatima.LogLevelStatus, error) {
+	status := fatima.LogLevelStatus{}
+	client, err := NewFatimaIPCClientSession(proc)
+	if err != nil {
+		return status, fmt.Errorf("cannot make connection to %s : %s", proc, err.Error())
+	}
+	defer client.Disconnect()
+
+	err = client.SendCommand(message)
+	if err != nil {
+		return status, fmt.Errorf("fail to send loglevel : %s", err.Error())
+	}
+
+	c1 := make(chan Message, 1)
+	go func() {
+		response, e1 := client.ReadCommand()
+		if e1 != nil {
+			log.Warn("fail to read command : %s", e1.Error())
+			return
+		}
+		c1 <- response
+	}()
+
+	select {
+	case response := <-c1:
+		if !response.Is(CommandLogLevelDone) {
+			return status, fmt.Errorf("unexpected response from %s : %s", proc, response)
+		}
+		b, err := json.Marshal(response.Data.GetValue(DataKeyResult))
+		if err == nil {
+			err = json.Unmarshal(b, &status)
+		}
+		if err != nil {
+			return status, fmt.Errorf("invalid loglevel result : %s", err.Error())
+		}
+		if e := AsString(response.Data.GetValue(DataKeyError)); len(e) > 0 {
+			return status, errors.New(e)
+		}
+		return status, nil
+	case <-time.After(timeout):
+		return status, fmt.Errorf("timeout to receive loglevel done from %s", proc)
+	}
+}
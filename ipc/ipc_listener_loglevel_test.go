@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 26. 오후 1:30
+ */
+
+package ipc
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dummyLogLevelController record last call
+type dummyLogLevelController struct {
+	call   string
+	logger string
+	level  log.LogLevel
+	ttl    time.Duration
+	source string
+}
+
+func (d *dummyLogLevelController) ChangeLogLevel(level log.LogLevel, ttl time.Duration, source string) error {
+	d.call, d.level, d.ttl, d.source = "change", level, ttl, source
+	return nil
+}
+
+func (d *dummyLogLevelController) RevertLogLevel(source string) {
+	d.call, d.source = "revert", source
+}
+
+func (d *dummyLogLevelController) ChangeLoggerLevel(name string, level log.LogLevel, ttl time.Duration, source string) error {
+	if name == "denied" {
+		return errors.New("denied")
+	}
+	d.call, d.logger, d.level, d.ttl, d.source = "changeLogger", name, level, ttl, source
+	return nil
+}
+
+func (d *dummyLogLevelController) RevertLoggerLevel(name string, source string) {
+	d.call, d.logger, d.source = "revertLogger", name, source
+}
+
+func (d *dummyLogLevelController) GetLogLevelStatus() fatima.LogLevelStatus {
+	return fatima.LogLevelStatus{Level: d.level.String(), BaseLevel: "INFO"}
+}
+
+// replySessionContext keep replied message
+type replySessionContext struct {
+	replied []Message
+	closed  bool
+}
+
+func (r *replySessionContext) String() string          { return "replySessionContext" }
+func (r *replySessionContext) Close()                  { r.closed = true }
+func (r *replySessionContext) GetConnection() net.Conn { return nil }
+func (r *replySessionContext) SendCommand(message Message) error {
+	r.replied = append(r.replied, message)
+	return nil
+}
+
+func beforeTestProviderForLogLevelListener() {
+	envProvideHelper.getPid = mockGetPid
+	envProvideHelper.getSockDir = mockGetSockDir
+	envProvideHelper.getProgramName = mockGetProgramName
+	envProvideHelper.buildAddress = mockBuildAddress
+}
+
+func TestLogLevelListener(t *testing.T) {
+	beforeTestProviderForLogLevelListener()
+
+	cases := []struct {
+		name    string
+		message Message
+		call    string
+		logger  string
+		level   log.LogLevel
+		ttl     time.Duration
+		err     string
+	}{
+		{"set", NewMessageLogLevel(LogLevelActionSet, "", "debug", 10*time.Minute), "change", "", log.LOG_DEBUG, 10 * time.Minute, ""},
+		{"hexa level", NewMessageLogLevel(LogLevelActionSet, "", "0xFF", 0), "change", "", log.LOG_TRACE, 0, ""},
+		{"logger", NewMessageLogLevel(LogLevelActionSet, "github.com/x/y", "warn", time.Minute), "changeLogger", "github.com/x/y", log.LOG_WARN, time.Minute, ""},
+		{"revert", NewMessageLogLevel(LogLevelActionRevert, "", "", 0), "revert", "", 0, 0, ""},
+		{"revert logger", NewMessageLogLevel(LogLevelActionRevert, "a.b", "", 0), "revertLogger", "a.b", 0, 0, ""},
+		{"status", NewMessageLogLevel(LogLevelActionStatus, "", "", 0), "", "", 0, 0, ""},
+		{"invalid level", NewMessageLogLevel(LogLevelActionSet, "", "verbose", 0), "", "", 0, 0, "invalid log level [verbose]"},
+		{"invalid action", NewMessageLogLevel("reset", "", "", 0), "", "", 0, 0, "unknown action [reset]"},
+		{"denied", NewMessageLogLevel(LogLevelActionSet, "denied", "info", 0), "", "", 0, 0, "denied"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := &dummyLogLevelController{}
+			ctx := &replySessionContext{}
+			newLogLevelListener(controller).OnReceiveCommand(ctx, c.message)
+
+			assert.Equal(t, c.call, controller.call)
+			assert.Equal(t, c.logger, controller.logger)
+			assert.Equal(t, log.LogLevel(c.level), controller.level)
+			assert.Equal(t, c.ttl, controller.ttl)
+			if len(c.call) > 0 {
+				assert.Equal(t, fatima.LogLevelSourceIPC, controller.source)
+			}
+
+			assert.True(t, ctx.closed)
+			require.Len(t, ctx.replied, 1)
+			assert.True(t, ctx.replied[0].Is(CommandLogLevelDone))
+			assert.Equal(t, c.err, AsString(ctx.replied[0].Data.GetValue(DataKeyError)))
+		})
+	}
+}
+
+func TestLogLevelTTLSeconds(t *testing.T) {
+	beforeTestProviderForLogLevelListener()
+	controller := &dummyLogLevelController{}
+	message := NewMessageLogLevel(LogLevelActionSet, "", "info", 0)
+	message.Data[DataKeyTTL] = float64(30) // json number
+	require.NoError(t, newLogLevelListener(controller).(*LogLevelListener).execute(message))
+	assert.Equal(t, 30*time.Second, controller.ttl)
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오후 2:00
+ */
+
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+)
+
+func registerSystemStatusListener(provider monitor.SystemStatusManagerProvider) {
+	RegisterIPCSessionListener(newSystemStatusListener(provider))
+}
+
+func newSystemStatusListener(provider monitor.SystemStatusManagerProvider) FatimaIPCSessionListener {
+	return &SystemStatusListener{provider: provider}
+}
+
+// SystemStatusListener change HA, PS status and log levels in cfm by SYSTEM_STATUS command.
+// reply SYSTEM_STATUS_DONE with current status
+type SystemStatusListener struct {
+	provider monitor.SystemStatusManagerProvider
+}
+
+func (g *SystemStatusListener) StartSession(ctx SessionContext) {
+	log.Trace("[%s] start session", ctx)
+}
+
+func (g *SystemStatusListener) OnClose(ctx SessionContext) {
+	log.Trace("[%s] on close", ctx)
+}
+
+func (g *SystemStatusListener) OnReceiveCommand(ctx SessionContext, message Message) {
+	log.Trace("IPC command incoming : %s", message)
+
+	if !message.Is(CommandSystemStatus) {
+		return
+	}
+
+	defer ctx.Close()
+
+	log.Warn("IPC process SystemStatus : %s", message)
+	manager := g.provider.GetSystemStatusManager()
+	if manager == nil {
+		g.reply(ctx, monitor.SystemStatusSnapshot{}, nil, errors.New("system status manager is not ready"))
+		return
+	}
+
+	err := g.execute(manager, message)
+	if err != nil {
+		log.Warn("[%s] fail to process system status : %s", ctx, err.Error())
+	}
+
+	snapshot, e1 := manager.GetSystemStatusSnapshot()
+	if err == nil {
+		err = e1
+	}
+	var records []monitor.StatusAuditRecord
+	if limit, _ := strconv.Atoi(AsString(message.Data.GetValue(DataKeyAudit))); limit > 0 {
+		records, e1 = manager.GetAuditRecords(limit)
+		if err == nil {
+			err = e1
+		}
+	}
+	g.reply(ctx, snapshot, records, err)
+}
+
+func (g *SystemStatusListener) reply(ctx SessionContext, snapshot monitor.SystemStatusSnapshot, records []monitor.StatusAuditRecord, err error) {
+	err = ctx.SendCommand(NewMessageSystemStatusDone(snapshot, records, err))
+	if err != nil {
+		log.Warn("[%s] fail to send system status done : %s", ctx, err.Error())
+	}
+}
+
+func (g *SystemStatusListener) execute(manager monitor.SystemStatusManager, message Message) error {
+	action := AsString(message.Data.GetValue(DataKeyAction))
+	switch action {
+	case SystemStatusActionStatus:
+		return nil
+	case SystemStatusActionSet:
+	default:
+		return fmt.Errorf("unknown action [%s]", action)
+	}
+
+	actor := AsString(message.Data.GetValue(DataKeyActor))
+	if len(actor) == 0 {
+		actor = "ipc:" + message.Initiator.Process
+	}
+
+	// validate every value before changing anything
+	var ha monitor.HAStatus
+	var ps monitor.PSStatus
+	var err error
+	haValue := AsString(message.Data.GetValue(DataKeyHA))
+	if len(haValue) > 0 {
+		if ha, err = monitor.ParseHAStatus(haValue); err != nil {
+			return err
+		}
+	}
+	psValue := AsString(message.Data.GetValue(DataKeyPS))
+	if len(psValue) > 0 {
+		if ps, err = monitor.ParsePSStatus(psValue); err != nil {
+			return err
+		}
+	}
+	process := AsString(message.Data.GetValue(DataKeyProcess))
+	levelName := AsString(message.Data.GetValue(DataKeyLogLevel))
+	level := log.LogLevel(log.LOG_NONE)
+	if len(levelName) > 0 {
+		if len(process) == 0 {
+			return errors.New("process is required to set log level")
+		}
+		if !strings.EqualFold(levelName, "none") {
+			level = log.ConvertStringToLogLevel(levelName)
+			if level == log.LOG_NONE {
+				level, _ = log.ConvertHexaToLogLevel(levelName)
+			}
+			if level == log.LOG_NONE {
+				return fmt.Errorf("invalid log level [%s]", levelName)
+			}
+		}
+	}
+	if len(haValue) == 0 && len(psValue) == 0 && len(levelName) == 0 {
+		return errors.New("nothing to set")
+	}
+
+	change := monitor.SystemStatusChange{}
+	fields := 0
+	if len(haValue) > 0 {
+		change.HA = &ha
+		fields++
+	}
+	if len(psValue) > 0 {
+		change.PS = &ps
+		fields++
+	}
+	if len(levelName) > 0 {
+		change.Process, change.LogLevel = process, &level
+		fields++
+	}
+
+	// several values are changed atomically or not at all
+	if batch, ok := manager.(monitor.SystemStatusBatchManager); ok {
+		return batch.SetSystemStatus(change, actor)
+	}
+	switch {
+	case fields > 1:
+		return errors.New("system status manager cannot set several values at once")
+	case change.HA != nil:
+		return manager.SetHAStatus(ha, actor)
+	case change.PS != nil:
+		return manager.SetPSStatus(ps, actor)
+	default:
+		return manager.SetLogLevel(process, level, actor)
+	}
+}
+
+// SystemStatusResponse result of SYSTEM_STATUS command
+type SystemStatusResponse struct {
+	Status monitor.SystemStatusSnapshot
+	Audit  []monitor.StatusAuditRecord
+}
+
+// RequestSystemStatus send SYSTEM_STATUS command to process and return status (and audit records) after command
+func RequestSystemStatus(proc string, message Message, timeout time.Duration) (SystemStatusResponse, error) {
+	response := SystemStatusResponse{}
+	client, err := NewFatimaIPCClientSession(proc)
+	if err != nil {
+		return response, fmt.Errorf("cannot make connection to %s : %s", proc, err.Error())
+	}
+	defer client.Disconnect()
+
+	err = client.SendCommand(message)
+	if err != nil {
+		return response, fmt.Errorf("fail to send system status : %s", err.Error())
+	}
+
+	c1 := make(chan Message, 1)
+	go func() {
+		reply, e1 := client.ReadCommand()
+		if e1 != nil {
+			log.Warn("fail to read command : %s", e1.Error())
+			return
+		}
+		c1 <- reply
+	}()
+
+	select {
+	case reply := <-c1:
+		if !reply.Is(CommandSystemStatusDone) {
+			return response, fmt.Errorf("unexpected response from %s : %s", proc, reply)
+		}
+		if err = convertJsonValue(reply.Data.GetValue(DataKeyResult), &response.Status); err != nil {
+			return response, fmt.Errorf("invalid system status result : %s", err.Error())
+		}
+		if v := reply.Data.GetValue(DataKeyAudit); v != nil {
+			if err = convertJsonValue(v, &response.Audit); err != nil {
+				return response, fmt.Errorf("invalid audit result : %s", err.Error())
+			}
+		}
+		if e := AsString(reply.Data.GetValue(DataKeyError)); len(e) > 0 {
+			return response, errors.New(e)
+		}
+		return response, nil
+	case <-time.After(timeout):
+		return response, fmt.Errorf("timeout to receive system status done from %s", proc)
+	}
+}
+
+// convertJsonValue convert decoded json value (map, slice) to typed value
+func convertJsonValue(value interface{}, target interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오후 2:00
+ */
+
+package ipc
+
+import (
+	"testing"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	log "github.com/fatima-go/fatima-log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dummyStatusManager record changes
+type dummyStatusManager struct {
+	calls []string
+	actor string
+	ha    monitor.HAStatus
+	ps    monitor.PSStatus
+	level log.LogLevel
+}
+
+func (d *dummyStatusManager) GetSystemStatusManager() monitor.SystemStatusManager {
+	return d
+}
+
+func (d *dummyStatusManager) SetHAStatus(status monitor.HAStatus, actor string) error {
+	d.calls, d.ha, d.actor = append(d.calls, "ha"), status, actor
+	return nil
+}
+
+func (d *dummyStatusManager) SetPSStatus(status monitor.PSStatus, actor string) error {
+	d.calls, d.ps, d.actor = append(d.calls, "ps"), status, actor
+	return nil
+}
+
+func (d *dummyStatusManager) SetLogLevel(process string, level log.LogLevel, actor string) error {
+	d.calls, d.level, d.actor = append(d.calls, "loglevel:"+process), level, actor
+	return nil
+}
+
+func (d *dummyStatusManager) GetSystemStatusSnapshot() (monitor.SystemStatusSnapshot, error) {
+	return monitor.SystemStatusSnapshot{HA: d.ha.String(), PS: d.ps.String()}, nil
+}
+
+func (d *dummyStatusManager) GetAuditRecords(limit int) ([]monitor.StatusAuditRecord, error) {
+	return []monitor.StatusAuditRecord{{Actor: d.actor, Target: "system.ha"}}, nil
+}
+
+func TestSystemStatusListener(t *testing.T) {
+	beforeTestProviderForLogLevelListener()
+
+	cases := []struct {
+		name    string
+		message Message
+		calls   []string
+		actor   string
+		level   log.LogLevel
+		err     string
+	}{
+		{"set ha", NewMessageSystemStatusSet("active", "", "", "", "operator"), []string{"ha"}, "operator", log.LOG_NONE, ""},
+		{"set ps", NewMessageSystemStatusSet("", "2", "", "", "operator"), []string{"ps"}, "operator", log.LOG_NONE, ""},
+		{"set ha ps without batch", NewMessageSystemStatusSet("active", "2", "", "", "operator"), nil, "", log.LOG_NONE, "system status manager cannot set several values at once"},
+		{"set loglevel", NewMessageSystemStatusSet("", "", "mypgm", "debug", ""), []string{"loglevel:mypgm"}, "ipc:" + mockGetProgramName(), log.LOG_DEBUG, ""},
+		{"remove loglevel", NewMessageSystemStatusSet("", "", "mypgm", "none", "operator"), []string{"loglevel:mypgm"}, "operator", log.LOG_NONE, ""},
+		{"status", NewMessageSystemStatus(10), nil, "", log.LOG_NONE, ""},
+		{"invalid ha", NewMessageSystemStatusSet("master", "primary", "", "", "operator"), nil, "", log.LOG_NONE, "invalid HA status [master]"},
+		{"loglevel without process", NewMessageSystemStatusSet("", "", "", "info", "operator"), nil, "", log.LOG_NONE, "process is required to set log level"},
+		{"nothing", NewMessageSystemStatusSet("", "", "", "", "operator"), nil, "", log.LOG_NONE, "nothing to set"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			manager := &dummyStatusManager{}
+			ctx := &replySessionContext{}
+			newSystemStatusListener(manager).OnReceiveCommand(ctx, c.message)
+
+			assert.Equal(t, c.calls, manager.calls)
+			assert.Equal(t, c.actor, manager.actor)
+			assert.Equal(t, log.LogLevel(c.level), manager.level)
+
+			assert.True(t, ctx.closed)
+			require.Len(t, ctx.replied, 1)
+			reply := ctx.replied[0]
+			assert.True(t, reply.Is(CommandSystemStatusDone))
+			assert.Equal(t, c.err, AsString(reply.Data.GetValue(DataKeyError)))
+			assert.Equal(t, manager.ha.String(), reply.Data[DataKeyResult].(monitor.SystemStatusSnapshot).HA)
+			if c.name == "status" {
+				assert.Len(t, reply.Data[DataKeyAudit], 1)
+			}
+		})
+	}
+}
+
+// dummyBatchStatusManager change several values at once
+type dummyBatchStatusManager struct {
+	dummyStatusManager
+	change monitor.SystemStatusChange
+}
+
+func (d *dummyBatchStatusManager) GetSystemStatusManager() monitor.SystemStatusManager {
+	return d
+}
+
+func (d *dummyBatchStatusManager) SetSystemStatus(change monitor.SystemStatusChange, actor string) error {
+	d.calls, d.change, d.actor = append(d.calls, "batch"), change, actor
+	return nil
+}
+
+func TestSystemStatusListenerBatch(t *testing.T) {
+	beforeTestProviderForLogLevelListener()
+
+	manager := &dummyBatchStatusManager{}
+	ctx := &replySessionContext{}
+	newSystemStatusListener(manager).OnReceiveCommand(ctx, NewMessageSystemStatusSet("active", "2", "mypgm", "debug", "operator"))
+
+	assert.Equal(t, []string{"batch"}, manager.calls)
+	assert.Equal(t, "operator", manager.actor)
+	require.NotNil(t, manager.change.HA)
+	assert.Equal(t, monitor.HAStatus(monitor.HA_STATUS_ACTIVE), *manager.change.HA)
+	require.NotNil(t, manager.change.PS)
+	assert.Equal(t, monitor.PSStatus(monitor.PS_STATUS_SECONDARY), *manager.change.PS)
+	assert.Equal(t, "mypgm", manager.change.Process)
+	require.NotNil(t, manager.change.LogLevel)
+	assert.Equal(t, log.LogLevel(log.LOG_DEBUG), *manager.change.LogLevel)
+	require.Len(t, ctx.replied, 1)
+	assert.Empty(t, AsString(ctx.replied[0].Data.GetValue(DataKeyError)))
+}
@@ -27,7 +27,10 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
 	log "github.com/fatima-go/fatima-log"
 )
 
@@ -55,10 +58,38 @@ const (
 	CommandGoawayStart           = "GOAWAY_START"
 	CommandGoawayDone            = "GOAWAY_DONE"
 	CommandCronExecute           = "CRON_EXECUTE"
+	CommandLogLevel              = "LOGLEVEL"
+	CommandLogLevelDone          = "LOGLEVEL_DONE"
+	CommandSystemStatus          = "SYSTEM_STATUS"
+	CommandSystemStatusDone      = "SYSTEM_STATUS_DONE"
 	DataKeyTransaction           = "transaction"
 	DataKeyVerify                = "verify"
 	DataKeyJobName               = "job"
 	DataKeyJobSample             = "sample"
+	DataKeyAction                = "action"
+	DataKeyLogger                = "logger"
+	DataKeyLogLevel              = "level"
+	DataKeyTTL                   = "ttl"
+	DataKeyResult                = "result"
+	DataKeyError                 = "error"
+	DataKeyHA                    = "ha"
+	DataKeyPS                    = "ps"
+	DataKeyProcess               = "process"
+	DataKeyActor                 = "actor"
+	DataKeyAudit                 = "audit"
+)
+
+// LOGLEVEL actions
+const (
+	LogLevelActionSet    = "set"
+	LogLevelActionRevert = "revert"
+	LogLevelActionStatus = "status"
+)
+
+// SYSTEM_STATUS actions
+const (
+	SystemStatusActionSet    = "set"
+	SystemStatusActionStatus = "status"
 )
 
 func newMessage(command string) Message {
@@ -105,6 +136,63 @@ func NewMessageCronExecute(jobName, sample string) Message {
 	return m
 }
 
+// NewMessageLogLevel build LOGLEVEL command. empty logger means process log level. ttl 0 means no auto revert
+func NewMessageLogLevel(action, logger, level string, ttl time.Duration) Message {
+	m := newMessage(CommandLogLevel)
+	m.Data = JsonBody{DataKeyAction: action}
+	if len(logger) > 0 {
+		m.Data[DataKeyLogger] = logger
+	}
+	if len(level) > 0 {
+		m.Data[DataKeyLogLevel] = level
+	}
+	if ttl > 0 {
+		m.Data[DataKeyTTL] = ttl.String()
+	}
+	return m
+}
+
+func NewMessageLogLevelDone(status fatima.LogLevelStatus, err error) Message {
+	m := newMessage(CommandLogLevelDone)
+	m.Data = JsonBody{DataKeyResult: status}
+	if err != nil {
+		m.Data[DataKeyError] = err.Error()
+	}
+	return m
+}
+
+// NewMessageSystemStatusSet build SYSTEM_STATUS command which changes cfm. empty value is not changed.
+// level is changed for process. level 'none' removes process from loglevels
+func NewMessageSystemStatusSet(ha, ps, process, level, actor string) Message {
+	m := newMessage(CommandSystemStatus)
+	m.Data = JsonBody{DataKeyAction: SystemStatusActionSet, DataKeyActor: actor}
+	for key, value := range map[string]string{DataKeyHA: ha, DataKeyPS: ps, DataKeyProcess: process, DataKeyLogLevel: level} {
+		if len(value) > 0 {
+			m.Data[key] = value
+		}
+	}
+	return m
+}
+
+// NewMessageSystemStatus build SYSTEM_STATUS command which returns status and last audit records
+func NewMessageSystemStatus(audit int) Message {
+	m := newMessage(CommandSystemStatus)
+	m.Data = JsonBody{DataKeyAction: SystemStatusActionStatus, DataKeyAudit: audit}
+	return m
+}
+
+func NewMessageSystemStatusDone(snapshot monitor.SystemStatusSnapshot, records []monitor.StatusAuditRecord, err error) Message {
+	m := newMessage(CommandSystemStatusDone)
+	m.Data = JsonBody{DataKeyResult: snapshot}
+	if len(records) > 0 {
+		m.Data[DataKeyAudit] = records
+	}
+	if err != nil {
+		m.Data[DataKeyError] = err.Error()
+	}
+	return m
+}
+
 type Message struct {
 	Initiator Initiator `json:"initiator"`
 	Data      JsonBody  `json:"data,omitempty"`
@@ -30,6 +30,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	log "github.com/fatima-go/fatima-log"
 )
@@ -42,6 +43,14 @@ const (
 
 var ipcServerSocket net.Listener
 
+// activeSessions number of connected ipc sessions
+var activeSessions atomic.Int64
+
+// GetSessionCount number of connected ipc sessions
+func GetSessionCount() int {
+	return int(activeSessions.Load())
+}
+
 func isServerRunning() bool {
 	return ipcServerSocket != nil
 }
@@ -84,6 +93,8 @@ func serverReceiveLoop(address string) {
 
 func startSession(ctx SessionContext) {
 	log.Debug("[%s] new ipc session started", ctx)
+	activeSessions.Add(1)
+	defer activeSessions.Add(-1)
 
 	propagateSessionStarted(ctx)
 
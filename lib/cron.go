@@ -55,6 +55,7 @@ import (
 	"time"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
 	"github.com/fatima-go/fatima-log"
 	robfig_cron "github.com/robfig/cron/v3"
 )
@@ -81,6 +82,16 @@ var (
 	runningCronJobs       = make(map[string]struct{})
 
 	errInvalidConfig = errors.New("invalid fatima config")
+
+	// cronDurationBuckets seconds
+	cronDurationBuckets = []float64{1, 5, 10, 30, 60, 300, 600, 1800, 3600}
+)
+
+// result label of cron job run metric
+const (
+	cronResultSuccess = "success"
+	cronResultPanic   = "panic"
+	cronResultSkipped = "skipped"
 )
 
 type CronJob struct {
@@ -97,27 +108,58 @@ type CronJob struct {
 
 func (c CronJob) Run() {
 	if !c.canRunnable() {
+		recordCronMetrics(c.name, cronResultSkipped, 0)
 		return
 	}
 
 	log.Info("start job [%s]", c.name)
+	start := time.Now()
+	result := cronResultSuccess
 	defer func() {
 		if r := recover(); r != nil {
 			log.Error("panic to execute : %s", r)
 			log.Error("%s", string(debug.Stack()))
+			result = cronResultPanic
 		}
+		recordCronMetrics(c.name, result, time.Since(start))
 		jobRunningMutex.Lock()
 		defer jobRunningMutex.Unlock()
 		delete(runningCronJobs, c.name)
 	}()
 
 	startMillis := CurrentTimeMillis()
-	c.runnable(c.desc, fatimaRuntime, c.args...)
+	c.runnable(c.desc, fatimaRuntime, c.resolveArgs()...)
 	endMillis := CurrentTimeMillis()
 
 	log.Info("cron job [%s] elapsed %d milli seconds", c.name, endMillis-startMillis)
 }
 
+// recordCronMetrics count job runs by result and observe elapsed time of executed job
+func recordCronMetrics(job, result string, elapsed time.Duration) {
+	registry := getMetricRegistry(fatimaRuntime)
+	if registry == nil {
+		return
+	}
+	registry.Counter("fatima_cron_job_runs_total", "number of cron job runs", monitor.Labels{"job": job, "result": result}).Inc()
+	if result != cronResultSkipped {
+		registry.Histogram("fatima_cron_job_duration_seconds", "elapsed seconds of cron job", cronDurationBuckets,
+			monitor.Labels{"job": job}).Observe(elapsed.Seconds())
+	}
+}
+
+// resolveArgs resolve predefine expressions (e.g. ${var.builtin.date.yyyymmdd}) in args at execution time
+func (c CronJob) resolveArgs() []string {
+	predefines, ok := fatimaRuntime.GetConfig().(fatima.Predefines)
+	if len(c.args) == 0 || !ok {
+		return c.args
+	}
+	args := make([]string, len(c.args))
+	for i, v := range c.args {
+		args[i] = predefines.ResolvePredefine(v)
+	}
+	return args
+}
+
 func (c CronJob) canRunnable() bool {
 	if !fatimaRuntime.IsRunning() {
 		return false
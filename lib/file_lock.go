@@ -0,0 +1,129 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 24. 오전 10:00
+ */
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var ErrFileLocked = errors.New("file is locked by another process")
+
+const fileLockPollInterval = 50 * time.Millisecond
+
+// FileLock exclusive lock on file (flock on unix, LockFileEx on windows). lock is released when process exits
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile acquire exclusive lock on path (created if not exist).
+// it waits until timeout. timeout <= 0 means trying only once. ErrFileLocked is returned when lock is not acquired
+func LockFile(path string, timeout time.Duration) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(file)
+		if locked {
+			return &FileLock{file: file}, nil
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if !time.Now().Before(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w : %s", ErrFileLocked, filepath.Base(path))
+		}
+		time.Sleep(fileLockPollInterval)
+	}
+}
+
+// Unlock release lock
+func (l *FileLock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := unlockFile(l.file)
+	closeErr := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// WriteFileAtomic write data to temp file in same folder and rename it to path.
+// mode of existing file is preserved. perm is used for new file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// BackupFile copy path to path + suffix (e.g. '.bak') with same mode. missing path is not an error
+func BackupFile(path string, suffix string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	backup := path + suffix
+	if err = WriteFileAtomic(backup, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chmod(backup, info.Mode().Perm())
+}
@@ -0,0 +1,412 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오후 2:00
+ */
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+// LatencyMarker latency histogram with log-linear (HDR like) buckets. statistics are reported over sliding
+// windows and nothing is reset by reading. e.g)
+//
+//	marker := lib.NewLatencyMarker(fatimaRuntime, "order")
+//	marker.MarkLatency(elapsed, monitor.Labels{"endpoint": "/order", "status": "200"})
+type LatencyMarker interface {
+	ResponseMarker
+	// MarkLatency mark elapsed with labels. nil labels is allowed
+	MarkLatency(elapsed time.Duration, labels monitor.Labels)
+	// GetLatencyStats statistics of every labels over window
+	GetLatencyStats(window time.Duration) []LatencyStat
+}
+
+// LatencyStat statistics of labels over window
+type LatencyStat struct {
+	Labels monitor.Labels
+	Window time.Duration
+	Count  uint64
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	P999   time.Duration
+}
+
+const (
+	latencySubBits   = 4 // 16 sub buckets for each power of 2. relative error is less than 1/16
+	latencySubCount  = 1 << latencySubBits
+	latencySlot      = 10 * time.Second
+	latencyMaxSeries = 200 // labels over max are marked as overflow
+)
+
+// DefaultLatencyWindows sliding windows of LatencyMarker
+var DefaultLatencyWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+var latencyOverflowLabels = monitor.Labels{"overflow": "true"}
+
+// latencyReservedLabels labels added by CollectMetrics. user labels of same name are renamed with "user_" prefix
+var latencyReservedLabels = []string{"marker", "window", "quantile"}
+
+// NewLatencyMarker marker which reports over windows. DefaultLatencyWindows is used when windows is empty
+func NewLatencyMarker(fatimaRuntime fatima.FatimaRuntime, name string, windows ...time.Duration) LatencyMarker {
+	m := newLatencyMarker(name, windows)
+	fatimaRuntime.RegisterMeasureUnit(m)
+	return m
+}
+
+func newLatencyMarker(name string, windows []time.Duration) *latencyMarker {
+	m := &latencyMarker{name: name, now: time.Now}
+	for _, w := range windows {
+		if w >= latencySlot {
+			m.windows = append(m.windows, w)
+		}
+	}
+	if len(m.windows) == 0 {
+		m.windows = append(m.windows, DefaultLatencyWindows...)
+	}
+	sort.Slice(m.windows, func(i, j int) bool { return m.windows[i] < m.windows[j] })
+	m.slots = int(m.windows[len(m.windows)-1]/latencySlot) + 1
+	m.series = make(map[string]*latencySeries)
+	return m
+}
+
+type latencyMarker struct {
+	mutex    sync.Mutex
+	name     string
+	windows  []time.Duration
+	slots    int // ring size of each series
+	series   map[string]*latencySeries
+	overflow bool
+	reserved bool // reserved label is warned
+	now      func() time.Time
+}
+
+type latencySeries struct {
+	key    string
+	labels monitor.Labels
+	ring   []latencyBucketSlot
+}
+
+// latencyBucketSlot observations (microseconds) during latencySlot
+type latencyBucketSlot struct {
+	epoch  int64
+	counts map[int]uint64
+	count  uint64
+	sum    int64
+	min    int64
+	max    int64
+}
+
+// Mark score is milliseconds
+func (m *latencyMarker) Mark(score int) {
+	m.MarkLatency(time.Duration(score)*time.Millisecond, nil)
+}
+
+func (m *latencyMarker) MarkLatency(elapsed time.Duration, labels monitor.Labels) {
+	value := max(elapsed.Microseconds(), 0)
+	epoch := m.now().UnixNano() / int64(latencySlot)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	labels = m.renameReservedLabels(labels)
+	key := latencyLabelsKey(labels)
+	series, ok := m.series[key]
+	if !ok {
+		if len(m.series) >= latencyMaxSeries {
+			if !m.overflow {
+				m.overflow = true
+				log.Warn("latency marker [%s] has too many labels. over %d are marked as overflow", m.name, latencyMaxSeries)
+			}
+			labels = latencyOverflowLabels
+			key = latencyLabelsKey(labels)
+			series, ok = m.series[key]
+		}
+		if !ok {
+			series = &latencySeries{key: key, labels: copyLatencyLabels(labels), ring: make([]latencyBucketSlot, m.slots)}
+			m.series[key] = series
+		}
+	}
+
+	slot := &series.ring[epoch%int64(m.slots)]
+	if slot.epoch != epoch || slot.counts == nil {
+		*slot = latencyBucketSlot{epoch: epoch, counts: make(map[int]uint64), min: math.MaxInt64}
+	}
+	slot.counts[latencyBucketIndex(value)]++
+	slot.count++
+	slot.sum += value
+	slot.min = min(slot.min, value)
+	slot.max = max(slot.max, value)
+}
+
+// renameReservedLabels rename user labels which collide with labels of CollectMetrics. e.g) window -> user_window
+func (m *latencyMarker) renameReservedLabels(labels monitor.Labels) monitor.Labels {
+	var renamed monitor.Labels
+	for _, name := range latencyReservedLabels {
+		value, ok := labels[name]
+		if !ok {
+			continue
+		}
+		if renamed == nil {
+			renamed = copyLatencyLabels(labels)
+		}
+		delete(renamed, name)
+		renamed["user_"+name] = value
+		if !m.reserved {
+			m.reserved = true
+			log.Warn("latency marker [%s] label %s is reserved. renamed to user_%s", m.name, name, name)
+		}
+	}
+	if renamed == nil {
+		return labels
+	}
+	return renamed
+}
+
+func (m *latencyMarker) GetLatencyStats(window time.Duration) []LatencyStat {
+	epoch := m.now().UnixNano() / int64(latencySlot)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	stats := make([]LatencyStat, 0, len(keys))
+	for _, key := range keys {
+		stat, ok := m.series[key].stat(epoch, window)
+		if ok {
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
+
+// stat merge slots in window. current slot is included
+func (s *latencySeries) stat(epoch int64, window time.Duration) (LatencyStat, bool) {
+	stat := LatencyStat{Labels: copyLatencyLabels(s.labels), Window: window}
+	oldest := epoch - int64(window/latencySlot) + 1
+	counts := make(map[int]uint64)
+	var sum int64
+	minValue, maxValue := int64(math.MaxInt64), int64(0)
+	for i := range s.ring {
+		slot := &s.ring[i]
+		if slot.count == 0 || slot.epoch < oldest || slot.epoch > epoch {
+			continue
+		}
+		for idx, count := range slot.counts {
+			counts[idx] += count
+		}
+		stat.Count += slot.count
+		sum += slot.sum
+		minValue = min(minValue, slot.min)
+		maxValue = max(maxValue, slot.max)
+	}
+	if stat.Count == 0 {
+		return stat, false
+	}
+
+	indexes := make([]int, 0, len(counts))
+	for idx := range counts {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	percentile := func(q float64) time.Duration {
+		rank := uint64(math.Ceil(q * float64(stat.Count)))
+		var cumulative uint64
+		for _, idx := range indexes {
+			cumulative += counts[idx]
+			if cumulative >= rank {
+				// highest value of bucket. exact min, max are known
+				value := min(max(latencyBucketUpper(idx), minValue), maxValue)
+				return time.Duration(value) * time.Microsecond
+			}
+		}
+		return time.Duration(maxValue) * time.Microsecond
+	}
+
+	stat.Min = time.Duration(minValue) * time.Microsecond
+	stat.Max = time.Duration(maxValue) * time.Microsecond
+	stat.Mean = time.Duration(sum/int64(stat.Count)) * time.Microsecond
+	stat.P50 = percentile(0.5)
+	stat.P90 = percentile(0.9)
+	stat.P99 = percentile(0.99)
+	stat.P999 = percentile(0.999)
+	return stat, true
+}
+
+// idle series has no observation in any window
+func (s *latencySeries) idle(epoch int64, slots int) bool {
+	for i := range s.ring {
+		if s.ring[i].count > 0 && s.ring[i].epoch > epoch-int64(slots) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *latencyMarker) GetKeyName() string {
+	return m.name
+}
+
+// GetMeasure statistics of each window. e.g)
+//
+//	:: 1m0s count=120 min=1ms mean=12.3ms max=350ms p50=10ms p90=20ms p99=300ms p999=350ms {endpoint="/order",status="200"}
+func (m *latencyMarker) GetMeasure() string {
+	m.removeIdle()
+
+	buff := bytes.Buffer{}
+	for _, window := range m.windows {
+		for _, stat := range m.GetLatencyStats(window) {
+			if buff.Len() > 0 {
+				buff.WriteString("\n")
+			}
+			buff.WriteString(fmt.Sprintf(":: %s count=%d min=%s mean=%s max=%s p50=%s p90=%s p99=%s p999=%s",
+				window, stat.Count, stat.Min, stat.Mean, stat.Max, stat.P50, stat.P90, stat.P99, stat.P999))
+			if len(stat.Labels) > 0 {
+				buff.WriteString(" {")
+				buff.WriteString(latencyLabelsKey(stat.Labels))
+				buff.WriteString("}")
+			}
+		}
+	}
+	if buff.Len() == 0 {
+		return ":: count=0"
+	}
+	return buff.String()
+}
+
+func (m *latencyMarker) removeIdle() {
+	epoch := m.now().UnixNano() / int64(latencySlot)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for key, series := range m.series {
+		if series.idle(epoch, m.slots) {
+			delete(m.series, key)
+		}
+	}
+}
+
+// CollectMetrics statistics of each window as gauges. e.g) /metrics, monitor file, notify activity
+func (m *latencyMarker) CollectMetrics() []monitor.MetricFamily {
+	count := monitor.MetricFamily{Name: "fatima_latency_count", Help: "number of latency marks in window", Type: monitor.MetricGauge}
+	minimum := monitor.MetricFamily{Name: "fatima_latency_min_seconds", Help: "minimum latency in window", Type: monitor.MetricGauge}
+	maximum := monitor.MetricFamily{Name: "fatima_latency_max_seconds", Help: "maximum latency in window", Type: monitor.MetricGauge}
+	mean := monitor.MetricFamily{Name: "fatima_latency_mean_seconds", Help: "mean latency in window", Type: monitor.MetricGauge}
+	quantile := monitor.MetricFamily{Name: "fatima_latency_seconds", Help: "latency percentiles in window", Type: monitor.MetricGauge}
+
+	for _, window := range m.windows {
+		for _, stat := range m.GetLatencyStats(window) {
+			labels := func(extra ...string) monitor.Labels {
+				l := copyLatencyLabels(stat.Labels)
+				if l == nil {
+					l = make(monitor.Labels)
+				}
+				l["marker"] = m.name
+				l["window"] = window.String()
+				for i := 0; i+1 < len(extra); i += 2 {
+					l[extra[i]] = extra[i+1]
+				}
+				return l
+			}
+			count.Metrics = append(count.Metrics, monitor.Metric{Labels: labels(), Value: float64(stat.Count)})
+			minimum.Metrics = append(minimum.Metrics, monitor.Metric{Labels: labels(), Value: stat.Min.Seconds()})
+			maximum.Metrics = append(maximum.Metrics, monitor.Metric{Labels: labels(), Value: stat.Max.Seconds()})
+			mean.Metrics = append(mean.Metrics, monitor.Metric{Labels: labels(), Value: stat.Mean.Seconds()})
+			quantile.Metrics = append(quantile.Metrics,
+				monitor.Metric{Labels: labels("quantile", "0.5"), Value: stat.P50.Seconds()},
+				monitor.Metric{Labels: labels("quantile", "0.9"), Value: stat.P90.Seconds()},
+				monitor.Metric{Labels: labels("quantile", "0.99"), Value: stat.P99.Seconds()},
+				monitor.Metric{Labels: labels("quantile", "0.999"), Value: stat.P999.Seconds()})
+		}
+	}
+	if len(count.Metrics) == 0 {
+		return nil
+	}
+	return []monitor.MetricFamily{count, minimum, maximum, mean, quantile}
+}
+
+// latencyBucketIndex values under latencySubCount have own bucket. others are in one of
+// latencySubCount buckets of their power of 2
+func latencyBucketIndex(value int64) int {
+	if value < latencySubCount {
+		return int(value)
+	}
+	exp := bits.Len64(uint64(value)) - 1
+	shift := exp - latencySubBits
+	sub := int(value>>shift) - latencySubCount
+	return latencySubCount + shift*latencySubCount + sub
+}
+
+// latencyBucketUpper highest value of bucket
+func latencyBucketUpper(idx int) int64 {
+	if idx < latencySubCount {
+		return int64(idx)
+	}
+	shift := (idx - latencySubCount) / latencySubCount
+	sub := (idx - latencySubCount) % latencySubCount
+	lower := int64(latencySubCount+sub) << shift
+	return lower + (int64(1) << shift) - 1
+}
+
+// latencyLabelsKey e.g) endpoint="/order",status="200". values are quoted so that ',' and '=' in value are not ambiguous
+func latencyLabelsKey(labels monitor.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+strconv.Quote(labels[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func copyLatencyLabels(labels monitor.Labels) monitor.Labels {
+	if len(labels) == 0 {
+		return nil
+	}
+	copied := make(monitor.Labels, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 31. 오전 10:00
+ */
+
+package lib
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLatencyMarker() (*latencyMarker, *time.Time) {
+	now := time.Unix(1000000, 0)
+	m := newLatencyMarker("test", nil)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestLatencyBucket(t *testing.T) {
+	last := -1
+	for value := int64(0); value < 1000000; value += 7 {
+		idx := latencyBucketIndex(value)
+		require.GreaterOrEqual(t, idx, last, "value %d", value)
+		last = idx
+
+		upper := latencyBucketUpper(idx)
+		require.GreaterOrEqual(t, upper, value)
+		require.LessOrEqual(t, upper-value, value/latencySubCount, "value %d", value)
+	}
+	assert.Equal(t, 111, latencyBucketIndex(1000))
+	assert.Equal(t, int64(1023), latencyBucketUpper(111))
+}
+
+func TestLatencyMarkerWindow(t *testing.T) {
+	m, now := newTestLatencyMarker()
+	for i := 1; i <= 100; i++ {
+		m.Mark(i)
+	}
+
+	stats := m.GetLatencyStats(time.Minute)
+	require.Len(t, stats, 1)
+	stat := stats[0]
+	assert.Nil(t, stat.Labels)
+	assert.Equal(t, uint64(100), stat.Count)
+	assert.Equal(t, time.Millisecond, stat.Min)
+	assert.Equal(t, 100*time.Millisecond, stat.Max)
+	assert.Equal(t, 50500*time.Microsecond, stat.Mean)
+	assert.InDelta(t, float64(50*time.Millisecond), float64(stat.P50), float64(50*time.Millisecond/latencySubCount))
+	assert.InDelta(t, float64(90*time.Millisecond), float64(stat.P90), float64(90*time.Millisecond/latencySubCount))
+	assert.InDelta(t, float64(99*time.Millisecond), float64(stat.P99), float64(99*time.Millisecond/latencySubCount))
+	assert.Equal(t, 100*time.Millisecond, stat.P999)
+
+	// marks slide out of short window
+	*now = now.Add(2 * time.Minute)
+	assert.Empty(t, m.GetLatencyStats(time.Minute))
+	stats = m.GetLatencyStats(5 * time.Minute)
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(100), stats[0].Count)
+
+	// reading does not reset
+	assert.Len(t, m.GetLatencyStats(5*time.Minute), 1)
+	assert.NotEqual(t, ":: count=0", m.GetMeasure())
+
+	// idle series is removed after longest window
+	*now = now.Add(14 * time.Minute)
+	assert.Equal(t, ":: count=0", m.GetMeasure())
+	assert.Empty(t, m.series)
+}
+
+func TestLatencyMarkerLabels(t *testing.T) {
+	m, _ := newTestLatencyMarker()
+	m.MarkLatency(time.Millisecond, monitor.Labels{"a": "b,c=d"})
+	m.MarkLatency(2*time.Millisecond, monitor.Labels{"a": "b", "c": "d"})
+	m.MarkLatency(3*time.Millisecond, monitor.Labels{"a": "b", "c": "d"})
+
+	stats := m.GetLatencyStats(time.Minute)
+	require.Len(t, stats, 2)
+	assert.Equal(t, monitor.Labels{"a": "b", "c": "d"}, stats[0].Labels)
+	assert.Equal(t, uint64(2), stats[0].Count)
+	assert.Equal(t, monitor.Labels{"a": "b,c=d"}, stats[1].Labels)
+	assert.Equal(t, uint64(1), stats[1].Count)
+	assert.Contains(t, m.GetMeasure(), `{a="b,c=d"}`)
+}
+
+func TestLatencyMarkerCollectMetrics(t *testing.T) {
+	m, _ := newTestLatencyMarker()
+	assert.Nil(t, m.CollectMetrics())
+
+	m.MarkLatency(10*time.Millisecond, monitor.Labels{"endpoint": "/order", "window": "x", "marker": "y"})
+
+	families := m.CollectMetrics()
+	require.Len(t, families, 5)
+	count := families[0]
+	assert.Equal(t, "fatima_latency_count", count.Name)
+	require.Len(t, count.Metrics, len(DefaultLatencyWindows))
+	for i, window := range DefaultLatencyWindows {
+		labels := count.Metrics[i].Labels
+		assert.Equal(t, window.String(), labels["window"])
+		assert.Equal(t, "test", labels["marker"])
+		assert.Equal(t, "x", labels["user_window"])
+		assert.Equal(t, "y", labels["user_marker"])
+		assert.Equal(t, "/order", labels["endpoint"])
+		assert.Equal(t, float64(1), count.Metrics[i].Value)
+	}
+
+	quantile := families[4]
+	assert.Equal(t, "fatima_latency_seconds", quantile.Name)
+	require.Len(t, quantile.Metrics, 4*len(DefaultLatencyWindows))
+	assert.Equal(t, "0.5", quantile.Metrics[0].Labels["quantile"])
+	assert.Equal(t, 0.01, quantile.Metrics[0].Value)
+}
+
+func TestLatencyMarkerOverflow(t *testing.T) {
+	m, _ := newTestLatencyMarker()
+	for i := 0; i < latencyMaxSeries+5; i++ {
+		m.MarkLatency(time.Millisecond, monitor.Labels{"id": fmt.Sprintf("%d", i)})
+	}
+
+	stats := m.GetLatencyStats(time.Minute)
+	require.Len(t, stats, latencyMaxSeries+1)
+	var overflow *LatencyStat
+	for i := range stats {
+		if stats[i].Labels["overflow"] == "true" {
+			overflow = &stats[i]
+		}
+	}
+	require.NotNil(t, overflow)
+	assert.Equal(t, uint64(5), overflow.Count)
+}
@@ -26,8 +26,10 @@ import (
 	"sync"
 
 	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/monitor"
 )
 
+// ResponseMarker count scores in linear buckets. counts are reset by every measure. see LatencyMarker for percentiles
 type ResponseMarker interface {
 	Mark(score int)
 }
@@ -55,17 +57,40 @@ func NewCustomResponseMarker(fatimaRuntime fatima.FatimaRuntime, name string, ma
 	m.part = part
 	m.build()
 	fatimaRuntime.RegisterMeasureUnit(m)
+	if registry := getMetricRegistry(fatimaRuntime); registry != nil {
+		// cumulative histogram with same boundaries. e.g) /metrics
+		m.histogram = registry.Histogram("fatima_response_score", "score marked by response marker",
+			m.bounds(), monitor.Labels{"marker": name})
+	}
 	return m
 }
 
+// getMetricRegistry nil when runtime does not provide metric registry
+func getMetricRegistry(fatimaRuntime fatima.FatimaRuntime) monitor.MetricRegistry {
+	if provider, ok := fatimaRuntime.(monitor.MetricRegistryProvider); ok {
+		return provider.GetMetricRegistry()
+	}
+	return nil
+}
+
 type basicResponseMarker struct {
-	mutex  sync.Mutex
-	name   string
-	max    int
-	part   int
-	bunch  int
-	list   []int
-	labels []string
+	mutex     sync.Mutex
+	name      string
+	max       int
+	part      int
+	bunch     int
+	list      []int
+	labels    []string
+	histogram monitor.Histogram
+}
+
+// bounds upper bound of each part. e.g) 1000, 2000, ... 10000
+func (b *basicResponseMarker) bounds() []float64 {
+	bounds := make([]float64, b.part)
+	for i := 0; i < b.part; i++ {
+		bounds[i] = float64((i * b.bunch) + b.bunch)
+	}
+	return bounds
 }
 
 func (b *basicResponseMarker) build() {
@@ -86,6 +111,10 @@ func (b *basicResponseMarker) reset() {
 }
 
 func (b *basicResponseMarker) Mark(score int) {
+	if b.histogram != nil {
+		b.histogram.Observe(float64(score))
+	}
+
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 26. 오전 10:00
+ */
+
+package fatima
+
+import (
+	"time"
+
+	log "github.com/fatima-go/fatima-log"
+)
+
+const (
+	LogLevelSourceConfig = "config" // fatima-package.yaml
+	LogLevelSourceCfm    = "cfm"    // package/cfm/loglevels
+	LogLevelSourceIPC    = "ipc"
+	LogLevelSourceAPI    = "api"
+	LogLevelSourceRevert = "revert" // auto revert after ttl
+)
+
+// FatimaLogLevelController change log level of process at runtime.
+// logger name is logger name or package path (e.g. github.com/fatima-go/fatima-core/ipc)
+// and override is applied to the name and its children ('/' or '.' separated)
+type FatimaLogLevelController interface {
+	// ChangeLogLevel change process log level. level is reverted to base level (config, cfm) after ttl. ttl 0 means no revert
+	ChangeLogLevel(level log.LogLevel, ttl time.Duration, source string) error
+	// RevertLogLevel revert process log level to base level
+	RevertLogLevel(source string)
+	// ChangeLoggerLevel override log level of logger. ttl 0 means no revert
+	ChangeLoggerLevel(name string, level log.LogLevel, ttl time.Duration, source string) error
+	// RevertLoggerLevel remove override of logger
+	RevertLoggerLevel(name string, source string)
+	GetLogLevelStatus() LogLevelStatus
+}
+
+// LogLevelChange history of log level change
+type LogLevelChange struct {
+	Time   time.Time `json:"time"`
+	Logger string    `json:"logger,omitempty"` // empty for process level
+	Base   bool      `json:"base,omitempty"`   // change of base level (config, cfm)
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Source string    `json:"source"`
+	Expire time.Time `json:"expire,omitzero"`
+}
+
+// LoggerLevel log level override of logger
+type LoggerLevel struct {
+	Logger string    `json:"logger"`
+	Level  string    `json:"level"`
+	Expire time.Time `json:"expire,omitzero"`
+}
+
+// LogLevelStatus current log level status
+type LogLevelStatus struct {
+	Level     string           `json:"level"`
+	BaseLevel string           `json:"base_level"`
+	Expire    time.Time        `json:"expire,omitzero"`
+	Overrides []LoggerLevel    `json:"overrides,omitempty"`
+	History   []LogLevelChange `json:"history,omitempty"`
+}
@@ -20,6 +20,12 @@
 
 package monitor
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 const (
 	HA_STATUS_UNKNOWN = 0
 	HA_STATUS_ACTIVE  = 1
@@ -47,3 +53,17 @@ func ToHAStatus(value int) HAStatus {
 	}
 	return HA_STATUS_UNKNOWN
 }
+
+// ParseHAStatus parse name (active, standby) or cfm value (1, 2)
+func ParseHAStatus(value string) (HAStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "active":
+		return HA_STATUS_ACTIVE, nil
+	case "standby":
+		return HA_STATUS_STANDBY, nil
+	}
+	if v, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && ToHAStatus(v) != HA_STATUS_UNKNOWN {
+		return ToHAStatus(v), nil
+	}
+	return HA_STATUS_UNKNOWN, fmt.Errorf("invalid HA status [%s]", value)
+}
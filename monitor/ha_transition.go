@@ -0,0 +1,62 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 29. 오전 10:00
+ */
+
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// FatimaSystemHATransitionAware component which takes part in HA transition (e.g. standby -> active).
+// PrepareHATransition is called on every participant first. CommitHATransition is called when all participants are prepared.
+// when any prepare fails or timeout, AbortHATransition is called on every participant and process stays in previous status.
+// ctx is cancelled at transition timeout. FatimaSystemHAAware components are notified after commit
+type FatimaSystemHATransitionAware interface {
+	PrepareHATransition(ctx context.Context, from, to HAStatus) error
+	CommitHATransition(ctx context.Context, from, to HAStatus) error
+	AbortHATransition(from, to HAStatus, cause error)
+}
+
+const (
+	HATransitionCommitted = "committed" // every participant committed
+	HATransitionAborted   = "aborted"   // prepare failed. process stays in previous status
+	HATransitionFailed    = "failed"    // commit failed on some participants. process is in new status
+)
+
+// HATransitionParticipant result of participant
+type HATransitionParticipant struct {
+	Name    string        `json:"name"`
+	Phase   string        `json:"phase"` // last phase : prepare, commit, abort
+	Error   string        `json:"error,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// HATransitionResult outcome of HA transition. written to cfm ha folder as <process>.transition
+type HATransitionResult struct {
+	Process      string                    `json:"process"`
+	From         string                    `json:"from"`
+	To           string                    `json:"to"`
+	Outcome      string                    `json:"outcome"`
+	Error        string                    `json:"error,omitempty"`
+	Start        time.Time                 `json:"start"`
+	End          time.Time                 `json:"end"`
+	Participants []HATransitionParticipant `json:"participants,omitempty"`
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 30. 오전 10:00
+ */
+
+package monitor
+
+type MetricType uint8
+
+const (
+	MetricUnknown   MetricType = 0
+	MetricCounter   MetricType = 1
+	MetricGauge     MetricType = 2
+	MetricHistogram MetricType = 3
+)
+
+func (t MetricType) String() string {
+	switch t {
+	case MetricCounter:
+		return "counter"
+	case MetricGauge:
+		return "gauge"
+	case MetricHistogram:
+		return "histogram"
+	}
+	return "unknown"
+}
+
+func (t MetricType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *MetricType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "counter":
+		*t = MetricCounter
+	case "gauge":
+		*t = MetricGauge
+	case "histogram":
+		*t = MetricHistogram
+	default:
+		*t = MetricUnknown
+	}
+	return nil
+}
+
+// Labels metric labels. e.g) {"queue": "order"}
+type Labels map[string]string
+
+// Counter value which only increases. e.g) processed requests
+type Counter interface {
+	Inc()
+	// Add add delta. negative delta is ignored
+	Add(delta float64)
+}
+
+// Gauge value which goes up and down. e.g) queue size
+type Gauge interface {
+	Set(value float64)
+	Add(delta float64)
+}
+
+// Histogram observations counted in buckets. e.g) response time
+type Histogram interface {
+	Observe(value float64)
+}
+
+// MetricCollector provides metric families at sampling time
+type MetricCollector interface {
+	CollectMetrics() []MetricFamily
+}
+
+// MetricRegistryProvider runtime (and interactor) which provides registry of typed metrics (counter, gauge, histogram)
+// sampled with measure units. e.g)
+//
+//	if provider, ok := fatimaRuntime.(monitor.MetricRegistryProvider); ok {
+//		provider.GetMetricRegistry().Counter("order_total", "number of orders", nil).Inc()
+//	}
+type MetricRegistryProvider interface {
+	GetMetricRegistry() MetricRegistry
+}
+
+// MetricRegistry typed metrics of process. metric is created at first call and same metric is returned
+// for same name and labels. name should match [a-zA-Z_:][a-zA-Z0-9_:]* (invalid characters are replaced with '_')
+type MetricRegistry interface {
+	Counter(name, help string, labels Labels) Counter
+	Gauge(name, help string, labels Labels) Gauge
+	// GaugeFunc gauge whose value is fn() at sampling time
+	GaugeFunc(name, help string, labels Labels, fn func() float64)
+	// Histogram buckets are upper bounds in ascending order. nil means default buckets
+	Histogram(name, help string, buckets []float64, labels Labels) Histogram
+	RegisterCollector(collector MetricCollector)
+	// Gather snapshot of every metric sorted by name
+	Gather() []MetricFamily
+}
+
+// MetricFamily snapshot of metrics which have same name
+type MetricFamily struct {
+	Name    string     `json:"name"`
+	Help    string     `json:"help,omitempty"`
+	Type    MetricType `json:"type"`
+	Metrics []Metric   `json:"metrics"`
+}
+
+// Metric snapshot of metric. Histogram is set for histogram type instead of Value
+type Metric struct {
+	Labels    Labels             `json:"labels,omitempty"`
+	Value     float64            `json:"value"`
+	Histogram *HistogramSnapshot `json:"histogram,omitempty"`
+}
+
+type HistogramSnapshot struct {
+	Count   uint64            `json:"count"`
+	Sum     float64           `json:"sum"`
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+// HistogramBucket cumulative count of observations <= UpperBound
+type HistogramBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
+}
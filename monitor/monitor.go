@@ -21,6 +21,8 @@
 package monitor
 
 import (
+	"time"
+
 	"github.com/fatima-go/fatima-log"
 )
 
@@ -30,7 +32,80 @@ type SystemStatusMonitor interface {
 	GetLogLevel() (log.LogLevel, bool)
 }
 
+// SystemStatusWatcher SystemStatusMonitor which notifies changes instead of being polled.
+// changed is called when status might be changed and caller reads status again
+type SystemStatusWatcher interface {
+	SystemStatusMonitor
+	// Watch start watching. calling Watch while watching does nothing
+	Watch(changed func()) error
+	// Watching return false if watch is not started or stopped by failure. caller should poll status then
+	Watching() bool
+	Close() error
+}
+
+// FencingTokenSource provides fencing token of PS primary. token increases whenever primary is changed.
+// components pass token to shared resources so that requests from stale primary can be rejected
+type FencingTokenSource interface {
+	// GetFencingToken return token and true while this process is primary
+	GetFencingToken() (uint64, bool)
+}
+
+// SystemStatusManager change HA, PS status and process log levels (cfm) instead of editing files by hand.
+// every change is written atomically and recorded to audit with actor (who changed)
+type SystemStatusManager interface {
+	SetHAStatus(status HAStatus, actor string) error
+	SetPSStatus(status PSStatus, actor string) error
+	// SetLogLevel set log level of process. LOG_NONE removes process from loglevels
+	SetLogLevel(process string, level log.LogLevel, actor string) error
+	GetSystemStatusSnapshot() (SystemStatusSnapshot, error)
+	// GetAuditRecords return last records (oldest first). limit <= 0 means all
+	GetAuditRecords(limit int) ([]StatusAuditRecord, error)
+}
+
+// SystemStatusChange values changed at once by SystemStatusBatchManager. nil value is not changed
+type SystemStatusChange struct {
+	HA       *HAStatus
+	PS       *PSStatus
+	Process  string // process of LogLevel
+	LogLevel *log.LogLevel
+}
+
+// SystemStatusBatchManager SystemStatusManager which changes several values atomically.
+// nothing is changed (nor audited) when any value cannot be changed
+type SystemStatusBatchManager interface {
+	SetSystemStatus(change SystemStatusChange, actor string) error
+}
+
+// SystemStatusManagerProvider runtime which provides SystemStatusManager
+type SystemStatusManagerProvider interface {
+	GetSystemStatusManager() SystemStatusManager
+}
+
+// SystemStatusSnapshot current HA, PS status and process log levels
+type SystemStatusSnapshot struct {
+	HA        string            `json:"ha"`
+	PS        string            `json:"ps"`
+	LogLevels map[string]string `json:"loglevels,omitempty"`
+}
+
+// StatusAuditRecord who changed what and when
+type StatusAuditRecord struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Target  string    `json:"target"` // system.ha, system.ps, loglevels
+	Process string    `json:"process,omitempty"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+}
+
 type SystemMeasurable interface {
 	GetKeyName() string
 	GetMeasure() string
 }
+
+// SystemMeasureScheduled measurable which has own interval. e.g) expensive measurement.
+// it is measured in background and last text is used until next measure
+type SystemMeasureScheduled interface {
+	SystemMeasurable
+	GetMeasureInterval() time.Duration
+}
@@ -31,6 +31,12 @@ type SystemNotifyHandler interface {
 	SendEvent(message string, v ...interface{})
 }
 
+// MetricNotifyHandler SystemNotifyHandler which sends metric families as separate measure message.
+// activity keeps map of measure unit texts so that saturn which does not know metrics is not affected
+type MetricNotifyHandler interface {
+	SendMetrics(families []MetricFamily)
+}
+
 const (
 	NotifyAlarm = iota
 	NotifyEvent
@@ -72,6 +78,8 @@ const (
 	ActionUnknown         = 0
 	ActionProcessShutdown = 1
 	ActionProcessStartup  = 2
+	ActionHATransition    = 3
+	ActionResourceLeak    = 4
 )
 
 type ActionType uint8
@@ -82,13 +90,17 @@ func (n ActionType) String() string {
 		return "PROCESS_SHUTDOWN"
 	case ActionProcessStartup:
 		return "PROCESS_STARTUP"
+	case ActionHATransition:
+		return "HA_TRANSITION"
+	case ActionResourceLeak:
+		return "RESOURCE_LEAK"
 	}
 	return fmt.Sprintf("Unknown action value : %d", n)
 }
 
 func (n ActionType) IsNil() bool {
 	switch n {
-	case ActionProcessShutdown, ActionProcessStartup:
+	case ActionProcessShutdown, ActionProcessStartup, ActionHATransition, ActionResourceLeak:
 		return false
 	}
 	return true
@@ -20,6 +20,12 @@
 
 package monitor
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 const (
 	PS_STATUS_UNKNOWN   = 0
 	PS_STATUS_PRIMARY   = 1
@@ -47,3 +53,17 @@ func ToPSStatus(value int) PSStatus {
 	}
 	return PS_STATUS_UNKNOWN
 }
+
+// ParsePSStatus parse name (primary, secondary) or cfm value (1, 2)
+func ParsePSStatus(value string) (PSStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "primary":
+		return PS_STATUS_PRIMARY, nil
+	case "secondary":
+		return PS_STATUS_SECONDARY, nil
+	}
+	if v, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && ToPSStatus(v) != PS_STATUS_UNKNOWN {
+		return ToPSStatus(v), nil
+	}
+	return PS_STATUS_UNKNOWN, fmt.Errorf("invalid PS status [%s]", value)
+}
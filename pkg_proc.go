@@ -21,6 +21,9 @@
 package fatima
 
 import (
+	"math"
+	"time"
+
 	"github.com/fatima-go/fatima-log"
 )
 
@@ -44,9 +47,79 @@ type FatimaPkgProc interface {
 	GetStartMode() ProcessStartMode
 	GetWeight() int
 	GetStartSec() int
+
+	// GetEnv return additional environment variables for process launching
+	GetEnv() map[string]string
+	// GetArgs return command line arguments for process launching
+	GetArgs() []string
+	// GetWorkDir return working directory (absolute or relative to FATIMA_HOME). empty means default
+	GetWorkDir() string
+	GetResourceLimit() ProcessResourceLimit
+	GetRestartPolicy() ProcessRestartPolicy
+	// GetDependencies return process names which should be started before this process
+	GetDependencies() []string
+	GetHealthCheck() ProcessHealthCheck
+}
+
+// RlimitUnlimited rlimit value meaning infinity
+const RlimitUnlimited = ^uint64(0)
+
+// ProcessResourceLimit resource limits of process. zero value means not specified
+type ProcessResourceLimit struct {
+	Rlimits    map[string]uint64 // rlimit resource name (nofile, nproc, core, ...) to limit
+	GoMaxProcs int               // GOMAXPROCS
+	GoMemLimit int64             // GOMEMLIMIT (bytes)
+}
+
+// restart policies
+const (
+	RestartAlways    = "always"
+	RestartOnFailure = "on-failure"
+	RestartNever     = "never"
+)
+
+// ProcessRestartPolicy restart policy of process. zero value means juno default
+type ProcessRestartPolicy struct {
+	Policy      string        // always, on-failure, never
+	MaxRestarts int           // 0 means unlimited
+	Backoff     time.Duration // delay before first restart. doubled for each restart
+	MaxBackoff  time.Duration // upper bound of backoff
+}
+
+// NextBackoff return delay before restart (attempt starts from 1)
+func (r ProcessRestartPolicy) NextBackoff(attempt int) time.Duration {
+	if r.Backoff <= 0 || attempt < 1 {
+		return r.Backoff
+	}
+	delay := r.Backoff
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		return r.MaxBackoff
+	}
+	return delay
+}
+
+// health check types
+const (
+	HealthCheckHeartbeat = "heartbeat"
+	HealthCheckHttp      = "http"
+	HealthCheckTcp       = "tcp"
+	HealthCheckExec      = "exec"
+)
+
+// ProcessHealthCheck health check settings of process. empty Type means no health check
+type ProcessHealthCheck struct {
+	Type     string        // heartbeat, http, tcp, exec
+	Target   string        // url (http), address (tcp) or command (exec)
+	Interval time.Duration // check interval
+	Timeout  time.Duration // timeout of each check
+	Retries  int           // consecutive failures before process is considered unhealthy
 }
 
 type FatimaPkgProcConfig interface {
-	Reload()
+	// Reload re-read package process configuration. current configuration is kept on error
+	Reload() error
 	GetProcByName(name string) FatimaPkgProc
 }
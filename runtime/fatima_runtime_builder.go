@@ -59,10 +59,25 @@ func (this *DefaultProcessBuilder) GetSystemAware() monitor.FatimaSystemAware {
 	return this.systemAware
 }
 
+var customSystemStatusMonitor monitor.SystemStatusMonitor
+
+// SetSystemStatusMonitor replace HA/PS status monitor (default : cfm files). should be called before getting fatima runtime.
+// monitor which implements monitor.SystemStatusWatcher notifies changes instead of being polled
+func SetSystemStatusMonitor(mon monitor.SystemStatusMonitor) {
+	customSystemStatusMonitor = mon
+}
+
+// GetFencingTokenSource return fencing token source when status monitor provides it (e.g. infra.LeaderElectionMonitor)
+func GetFencingTokenSource() (monitor.FencingTokenSource, bool) {
+	source, ok := customSystemStatusMonitor.(monitor.FencingTokenSource)
+	return source, ok
+}
+
 // getRuntimeBuilder return fatima runtime builder
 func getRuntimeBuilder(env fatima.FatimaEnv, processType fatima.FatimaProcessType) builder.FatimaRuntimeBuilder {
 	processBuilder := new(DefaultProcessBuilder)
 	processBuilder.processType = processType
+	processBuilder.monitor = customSystemStatusMonitor
 	if processType == fatima.PROCESS_TYPE_GENERAL {
 		processBuilder.pkgProcConfig = builder.NewYamlFatimaPackageConfig(env)
 	} else {
@@ -25,29 +25,31 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/fatima-go/fatima-core"
 	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/infra"
 	"github.com/fatima-go/fatima-core/monitor"
 )
 
 type MockFatimaRuntime struct {
-	env           fatima.FatimaEnv
-	config        fatima.Config
-	packaging     fatima.Packaging
-	systemStatus  monitor.FatimaSystemStatus
-	notifyHandler monitor.SystemNotifyHandler
+	env            fatima.FatimaEnv
+	config         fatima.Config
+	packaging      fatima.Packaging
+	systemStatus   monitor.FatimaSystemStatus
+	notifyHandler  monitor.SystemNotifyHandler
+	metricRegistry monitor.MetricRegistry
 }
 
 func NewMockFatimaRuntime() *MockFatimaRuntime {
 	return &MockFatimaRuntime{
-		env:           NewMockFatimaEnv(""),
-		config:        NewMockConfig(),
-		packaging:     NewMockPackaging(),
-		systemStatus:  NewMockSystemStatus(),
-		notifyHandler: NewMockSystemNotifyHandler(),
+		env:            NewMockFatimaEnv(""),
+		config:         NewMockConfig(),
+		packaging:      NewMockPackaging(),
+		systemStatus:   NewMockSystemStatus(),
+		notifyHandler:  NewMockSystemNotifyHandler(),
+		metricRegistry: infra.NewMetricRegistry(),
 	}
 }
 
@@ -112,6 +114,10 @@ func (m *MockFatimaRuntime) RegisterSystemPSAware(aware monitor.FatimaSystemPSAw
 func (m *MockFatimaRuntime) RegisterMeasureUnit(unit monitor.SystemMeasurable) {
 }
 
+func (m *MockFatimaRuntime) GetMetricRegistry() monitor.MetricRegistry {
+	return m.metricRegistry
+}
+
 func (m *MockFatimaRuntime) Run() {
 }
 
@@ -177,7 +183,7 @@ func (c *MockConfig) GetInt(key string) (int, error) {
 	if !ok {
 		return 0, fmt.Errorf("not found key : %s", key)
 	}
-	i, err := strconv.Atoi(v)
+	i, err := builder.ParseConfigInt(v)
 	if err != nil {
 		return 0, fmt.Errorf("not numeric value for key %s : %s", key, err.Error())
 	}
@@ -189,11 +195,95 @@ func (c *MockConfig) GetBool(key string) (bool, error) {
 	if !ok {
 		return false, fmt.Errorf("not found key : %s", key)
 	}
-	switch strings.ToUpper(v) {
-	case "TRUE":
-		return true, nil
+	b, err := builder.ParseConfigBool(v)
+	if err != nil {
+		return false, fmt.Errorf("not bool value for key %s : %s", key, err.Error())
 	}
-	return false, nil
+	return b, nil
+}
+
+func (c *MockConfig) GetInt64(key string) (int64, error) {
+	v, ok := c.m[key]
+	if !ok {
+		return 0, fmt.Errorf("not found key : %s", key)
+	}
+	i, err := builder.ParseConfigInt64(v)
+	if err != nil {
+		return 0, fmt.Errorf("not numeric value for key %s : %s", key, err.Error())
+	}
+	return i, nil
+}
+
+func (c *MockConfig) GetFloat(key string) (float64, error) {
+	v, ok := c.m[key]
+	if !ok {
+		return 0, fmt.Errorf("not found key : %s", key)
+	}
+	f, err := builder.ParseConfigFloat(v)
+	if err != nil {
+		return 0, fmt.Errorf("not numeric value for key %s : %s", key, err.Error())
+	}
+	return f, nil
+}
+
+func (c *MockConfig) GetDuration(key string) (time.Duration, error) {
+	v, ok := c.m[key]
+	if !ok {
+		return 0, fmt.Errorf("not found key : %s", key)
+	}
+	d, err := builder.ParseConfigDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("not duration value for key %s : %s", key, err.Error())
+	}
+	return d, nil
+}
+
+func (c *MockConfig) GetBytes(key string) (int64, error) {
+	v, ok := c.m[key]
+	if !ok {
+		return 0, fmt.Errorf("not found key : %s", key)
+	}
+	b, err := builder.ParseConfigBytes(v)
+	if err != nil {
+		return 0, fmt.Errorf("not byte size value for key %s : %s", key, err.Error())
+	}
+	return b, nil
+}
+
+func (c *MockConfig) GetStringMap(prefix string) map[string]string {
+	return builder.FilterStringMap(c.m, prefix)
+}
+
+func (c *MockConfig) GetStringOrDefault(key string, defaultValue string) string {
+	v, ok := c.m[key]
+	if !ok {
+		return defaultValue
+	}
+	return v
+}
+
+func (c *MockConfig) GetIntOrDefault(key string, defaultValue int) int {
+	return builder.ConfigValueOrDefault(c.GetInt, key, defaultValue)
+}
+
+func (c *MockConfig) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	return builder.ConfigValueOrDefault(c.GetInt64, key, defaultValue)
+}
+
+func (c *MockConfig) GetFloatOrDefault(key string, defaultValue float64) float64 {
+	return builder.ConfigValueOrDefault(c.GetFloat, key, defaultValue)
+}
+
+func (c *MockConfig) GetBoolOrDefault(key string, defaultValue bool) bool {
+	return builder.ConfigValueOrDefault(c.GetBool, key, defaultValue)
+}
+
+func (c *MockConfig) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return builder.ConfigValueOrDefault(c.GetDuration, key, defaultValue)
+}
+
+func (c *MockConfig) GetBytesOrDefault(key string, defaultValue int64) int64 {
+	return builder.ConfigValueOrDefault(c.GetBytes, key, defaultValue)
 }
 
 func (c *MockConfig) GetList(key string) ([]string, error) {
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 2:30
+ */
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-log"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+	defaultHealthRetries  = 3
+)
+
+// watchHealth start health check of running process (pid) if process has health settings.
+// heartbeat is reported to juno and is not checked by supervisor. caller should hold mutex
+func (s *Supervisor) watchHealth(p *supervisedProcess, pid int) {
+	if s.option.Detach {
+		return
+	}
+	check := p.proc.GetHealthCheck()
+	switch check.Type {
+	case "":
+		return
+	case fatima.HealthCheckHeartbeat:
+		log.Warn("heartbeat health check of %s is not supported by supervisor. only liveness is checked", p.proc.GetName())
+		return
+	}
+
+	if check.Interval <= 0 {
+		check.Interval = defaultHealthInterval
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = min(defaultHealthTimeout, check.Interval)
+	}
+	if check.Retries <= 0 {
+		check.Retries = defaultHealthRetries
+	}
+	go s.runHealthCheck(p, p.proc, pid, check)
+}
+
+// runHealthCheck probe process until it is stopped or replaced.
+// process is stopped (SIGTERM, SIGKILL after StopTimeout) after Retries consecutive failures
+// and restarted by its restart policy
+func (s *Supervisor) runHealthCheck(p *supervisedProcess, proc fatima.FatimaPkgProc, pid int, check fatima.ProcessHealthCheck) {
+	ticker := time.NewTicker(check.Interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for range ticker.C {
+		if !s.isWatched(p, pid) {
+			return
+		}
+		err := s.probe(proc, check)
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		log.Warn("health check of %s failed (%d/%d) : %s", proc.GetName(), failures, check.Retries, err.Error())
+		if failures < check.Retries {
+			continue
+		}
+
+		s.mutex.Lock()
+		watched := p.pid == pid && p.state == StateRunning && !p.stopping && !s.closed
+		done := p.done
+		if watched {
+			p.unhealthy = fmt.Errorf("unhealthy : %w", err)
+		}
+		s.mutex.Unlock()
+		if !watched {
+			return
+		}
+
+		log.Error("%s is unhealthy. stopping pid=%d", proc.GetName(), pid)
+		_ = signalProcess(pid, syscall.SIGTERM)
+		if !s.waitExit(context.Background(), proc, pid, done, s.option.StopTimeout) {
+			_ = signalProcess(pid, syscall.SIGKILL)
+		}
+		return
+	}
+}
+
+func (s *Supervisor) isWatched(p *supervisedProcess, pid int) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return p.pid == pid && p.state == StateRunning && !p.stopping && !s.closed
+}
+
+// probe check health once
+func (s *Supervisor) probe(proc fatima.FatimaPkgProc, check fatima.ProcessHealthCheck) error {
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	switch check.Type {
+	case fatima.HealthCheckHttp:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.Target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("http status %d", resp.StatusCode)
+		}
+	case fatima.HealthCheckTcp:
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", check.Target)
+		if err != nil {
+			return err
+		}
+		_ = conn.Close()
+	case fatima.HealthCheckExec:
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", check.Target)
+		cmd.Dir = s.procFolder(proc)
+		cmd.Env = builder.BuildProcessEnviron(proc, os.Environ())
+		cmd.WaitDelay = check.Timeout
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); len(msg) > 0 {
+				return fmt.Errorf("%w : %s", err, msg)
+			}
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported health type %s", check.Type)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 2:10
+ */
+
+package supervisor
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/sys/unix"
+)
+
+var rlimitResources = map[string]int{
+	"as":      unix.RLIMIT_AS,
+	"core":    unix.RLIMIT_CORE,
+	"cpu":     unix.RLIMIT_CPU,
+	"data":    unix.RLIMIT_DATA,
+	"fsize":   unix.RLIMIT_FSIZE,
+	"memlock": unix.RLIMIT_MEMLOCK,
+	"nofile":  unix.RLIMIT_NOFILE,
+	"nproc":   unix.RLIMIT_NPROC,
+	"stack":   unix.RLIMIT_STACK,
+}
+
+// applyRlimits set soft and hard limits of started process with prlimit.
+// raising hard limit above supervisor's own requires CAP_SYS_RESOURCE
+func applyRlimits(pid int, rlimits map[string]uint64) error {
+	names := make([]string, 0, len(rlimits))
+	for name := range rlimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource, ok := rlimitResources[name]
+		if !ok {
+			return fmt.Errorf("unsupported rlimit %s", name)
+		}
+		limit := unix.Rlimit{Cur: rlimits[name], Max: rlimits[name]}
+		if err := unix.Prlimit(pid, resource, &limit, nil); err != nil {
+			return fmt.Errorf("fail to set rlimit %s : %w", name, err)
+		}
+	}
+	return nil
+}
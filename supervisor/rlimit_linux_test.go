@@ -0,0 +1,58 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 2:50
+ */
+
+package supervisor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSupervisorRlimit(t *testing.T) {
+	s := prepareSupervisor(t,
+		map[string]string{"limited": "exec sleep 30"},
+		builder.ProcessItem{Gid: 2, Name: "limited",
+			Limits: &builder.ProcessLimitItem{Rlimit: map[string]string{"nofile": "100", "core": "unlimited"}}},
+	)
+
+	require.NoError(t, s.Start(context.Background(), "limited"))
+	pid := statusOf(t, s, "limited").Pid
+
+	var nofile, core unix.Rlimit
+	require.NoError(t, unix.Prlimit(pid, unix.RLIMIT_NOFILE, nil, &nofile))
+	assert.Equal(t, unix.Rlimit{Cur: 100, Max: 100}, nofile)
+
+	// raising hard limit requires privilege
+	var own unix.Rlimit
+	require.NoError(t, unix.Getrlimit(unix.RLIMIT_CORE, &own))
+	if own.Max == fatima.RlimitUnlimited {
+		require.NoError(t, unix.Prlimit(pid, unix.RLIMIT_CORE, nil, &core))
+		assert.Equal(t, unix.Rlimit{Cur: fatima.RlimitUnlimited, Max: fatima.RlimitUnlimited}, core)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+// +build !linux
+
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 18. 오후 2:10
+ */
+
+package supervisor
+
+import (
+	"runtime"
+
+	"github.com/fatima-go/fatima-log"
+)
+
+// applyRlimits rlimit of other process can be set only on linux (prlimit). limits are ignored with warning
+func applyRlimits(pid int, rlimits map[string]uint64) error {
+	if len(rlimits) > 0 {
+		log.Warn("rlimit is not supported on %s. ignored for pid %d", runtime.GOOS, pid)
+	}
+	return nil
+}
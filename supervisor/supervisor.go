@@ -0,0 +1,677 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 25. 오전 10:00
+ */
+
+// Package supervisor runs processes of fatima package without juno.
+// it is intended for local development and small deployments.
+//
+// pid file ($FATIMA_HOME/app/{name}/proc/{name}.pid) is the desired state of process.
+// supervisor writes pid file when it starts process and removes it when it stops process.
+// process whose pid file exists but is not running is considered crashed and restarted.
+//
+// rlimits of descriptor are applied to started process with prlimit (linux only).
+// health checks (http, tcp, exec) run while supervisor is not detached and
+// process failing them is stopped and restarted by its restart policy
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/fatima-go/fatima-core/builder/platform"
+	"github.com/fatima-go/fatima-core/monitor"
+	"github.com/fatima-go/fatima-log"
+)
+
+// process states
+const (
+	StateStopped = "stopped"
+	StateRunning = "running"
+	StateBackoff = "backoff" // waiting for restart
+	StateFailed  = "failed"  // restart limit exceeded
+)
+
+// TargetAll target of every process
+const TargetAll = "all"
+
+const (
+	defaultCheckInterval     = 3 * time.Second
+	defaultStopTimeout       = 10 * time.Second
+	defaultRestartBackoff    = time.Second
+	defaultRestartMaxBackoff = time.Minute
+	// restart count is reset when process has been running longer than this
+	restartResetAfter = time.Minute
+	stopPollInterval  = 100 * time.Millisecond
+	// linux truncates process name (comm) to 15 bytes
+	linuxCommLength = 15
+)
+
+// Option supervisor option. zero value is usable
+type Option struct {
+	// Platform used for liveness check. default platform.OSPlatform
+	Platform fatima.PlatformSupport
+	// Status current system status for HA/PS processes. nil means HA/PS processes are not started by group
+	Status monitor.FatimaSystemStatus
+	// CheckInterval liveness check interval of Run
+	CheckInterval time.Duration
+	// StopTimeout time to wait after SIGTERM before SIGKILL
+	StopTimeout time.Duration
+	// Detach launched processes are released and not waited. used by command line which exits after start
+	Detach bool
+}
+
+// ProcessStatus status of supervised process
+type ProcessStatus struct {
+	Name      string    `json:"name"`
+	Group     string    `json:"group"`
+	Pid       int       `json:"pid,omitempty"`
+	State     string    `json:"state"`
+	Restarts  int       `json:"restarts,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type supervisedProcess struct {
+	proc      fatima.FatimaPkgProc
+	pid       int
+	state     string
+	restarts  int
+	since     time.Time
+	lastError string
+	stopping  bool
+	unhealthy error         // cause of stop by failed health check
+	done      chan struct{} // closed when child process exited. nil for adopted process
+	timer     *time.Timer
+}
+
+// Supervisor start, stop and restart processes of fatima package
+type Supervisor struct {
+	home   string
+	config *builder.YamlFatimaPackageConfig
+	option Option
+
+	mutex  sync.Mutex
+	procs  map[string]*supervisedProcess
+	closed bool
+}
+
+// New create supervisor for package at fatimaHome
+func New(fatimaHome string, config *builder.YamlFatimaPackageConfig, option Option) *Supervisor {
+	if option.Platform == nil {
+		option.Platform = new(platform.OSPlatform)
+	}
+	if option.CheckInterval <= 0 {
+		option.CheckInterval = defaultCheckInterval
+	}
+	if option.StopTimeout <= 0 {
+		option.StopTimeout = defaultStopTimeout
+	}
+	return &Supervisor{
+		home:   fatimaHome,
+		config: config,
+		option: option,
+		procs:  make(map[string]*supervisedProcess),
+	}
+}
+
+// Start start target (all, group name or process name) following start plan.
+// it waits startsec of each wave before starting next wave. running process is not started again
+func (s *Supervisor) Start(ctx context.Context, target string) error {
+	waves, err := s.startWaves(target)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	for i, wave := range waves {
+		started := false
+		for _, proc := range wave.procs {
+			launched, err := s.startProcess(proc)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s : %w", proc.GetName(), err))
+				continue
+			}
+			started = started || launched
+		}
+		if !started || wave.waitSec == 0 || i == len(waves)-1 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(wave.waitSec) * time.Second):
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stop stop target (all, group name or process name) in reverse order of start plan
+func (s *Supervisor) Stop(ctx context.Context, target string) error {
+	waves, err := s.stopWaves(target)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	for _, wave := range waves {
+		var wg sync.WaitGroup
+		var errLock sync.Mutex
+		for _, proc := range wave.procs {
+			wg.Add(1)
+			go func(proc fatima.FatimaPkgProc) {
+				defer wg.Done()
+				if err := s.stopProcess(ctx, proc); err != nil {
+					errLock.Lock()
+					errs = append(errs, fmt.Errorf("%s : %w", proc.GetName(), err))
+					errLock.Unlock()
+				}
+			}(proc)
+		}
+		wg.Wait()
+	}
+	return errors.Join(errs...)
+}
+
+// Status return status of target (all, group name or process name)
+func (s *Supervisor) Status(target string) ([]ProcessStatus, error) {
+	procs, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]ProcessStatus, 0, len(procs))
+	for _, proc := range procs {
+		status := ProcessStatus{Name: proc.GetName(), Group: s.groupName(proc.GetGid()), State: StateStopped}
+		s.mutex.Lock()
+		p, managed := s.procs[proc.GetName()]
+		if managed {
+			status.Pid, status.State, status.Restarts = p.pid, p.state, p.restarts
+			status.Since, status.LastError = p.since, p.lastError
+		}
+		s.mutex.Unlock()
+
+		if !managed || status.State == StateRunning {
+			pid, _ := s.readPid(proc)
+			if pid > 0 && s.isRunning(proc, pid) {
+				status.Pid, status.State = pid, StateRunning
+			} else if status.State == StateRunning {
+				status.State = StateStopped
+			}
+		}
+		list = append(list, status)
+	}
+	return list, nil
+}
+
+// Run supervise processes until ctx is done. processes having pid file are adopted.
+// crashed process is restarted according to restart policy
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.adopt()
+	ticker := time.NewTicker(s.option.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// Close stop pending restarts. running processes are not stopped
+func (s *Supervisor) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	for _, p := range s.procs {
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+	}
+}
+
+type processWave struct {
+	procs   []fatima.FatimaPkgProc
+	waitSec int
+}
+
+func (s *Supervisor) startWaves(target string) ([]processWave, error) {
+	if proc := s.singleProcess(target); proc != nil {
+		return []processWave{{procs: []fatima.FatimaPkgProc{proc}}}, nil
+	}
+	plan, err := s.config.PlanStart(s.planOption(target))
+	if err != nil {
+		return nil, err
+	}
+	for _, skip := range plan.Skipped {
+		log.Info("skip starting %s : %s", skip.Name, skip.Reason)
+	}
+	return toProcessWaves(plan), nil
+}
+
+func (s *Supervisor) stopWaves(target string) ([]processWave, error) {
+	if proc := s.singleProcess(target); proc != nil {
+		return []processWave{{procs: []fatima.FatimaPkgProc{proc}}}, nil
+	}
+	plan, err := s.config.PlanStop(s.planOption(target))
+	if err != nil {
+		return nil, err
+	}
+	return toProcessWaves(plan), nil
+}
+
+func toProcessWaves(plan builder.ProcessPlan) []processWave {
+	waves := make([]processWave, 0, len(plan.Waves))
+	for _, w := range plan.Waves {
+		wave := processWave{waitSec: w.WaitSec}
+		for _, e := range w.Processes {
+			wave.procs = append(wave.procs, e.Proc)
+		}
+		waves = append(waves, wave)
+	}
+	return waves
+}
+
+func (s *Supervisor) planOption(target string) builder.ProcessPlanOption {
+	option := builder.ProcessPlanOption{Status: s.option.Status}
+	if len(target) > 0 && target != TargetAll {
+		option.Group = target
+	}
+	return option
+}
+
+// singleProcess return process when target is process name (not group)
+func (s *Supervisor) singleProcess(target string) fatima.FatimaPkgProc {
+	if len(target) == 0 || target == TargetAll || s.config.GetGroupId(target) >= 0 {
+		return nil
+	}
+	return s.config.GetProcByName(target)
+}
+
+func (s *Supervisor) resolveTarget(target string) ([]fatima.FatimaPkgProc, error) {
+	if len(target) == 0 || target == TargetAll {
+		return s.config.GetAllProc(false), nil
+	}
+	if s.config.GetGroupId(target) >= 0 {
+		return s.config.GetProcByGroup(target), nil
+	}
+	if proc := s.config.GetProcByName(target); proc != nil {
+		return []fatima.FatimaPkgProc{proc}, nil
+	}
+	return nil, fmt.Errorf("unknown process or group [%s]", target)
+}
+
+func (s *Supervisor) groupName(gid int) string {
+	for _, g := range s.config.Groups {
+		if g.Id == gid {
+			return g.Name
+		}
+	}
+	return ""
+}
+
+// startProcess launch process unless it is running. launched is false when process is already running
+func (s *Supervisor) startProcess(proc fatima.FatimaPkgProc) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p := s.procs[proc.GetName()]
+	if p == nil {
+		p = &supervisedProcess{proc: proc, state: StateStopped}
+		s.procs[proc.GetName()] = p
+	}
+	p.proc = proc
+	p.stopping = false
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+
+	if p.state == StateRunning && p.done != nil {
+		return false, nil
+	}
+	if pid, _ := s.readPid(proc); pid > 0 && s.isRunning(proc, pid) {
+		// adopt process started by others (juno, previous supervisor, ...)
+		p.pid, p.state, p.done = pid, StateRunning, nil
+		if p.since.IsZero() {
+			p.since = time.Now()
+		}
+		s.watchHealth(p, pid)
+		return false, nil
+	}
+
+	p.restarts = 0
+	if err := s.launch(p); err != nil {
+		p.state, p.lastError = StateFailed, err.Error()
+		return false, err
+	}
+	return true, nil
+}
+
+// launch start process. caller should hold mutex
+func (s *Supervisor) launch(p *supervisedProcess) error {
+	proc := p.proc
+	procDir := s.procFolder(proc)
+	if err := os.MkdirAll(procDir, 0755); err != nil {
+		return err
+	}
+	workDir := procDir
+	if len(proc.GetWorkDir()) > 0 {
+		workDir = s.resolvePath(proc.GetWorkDir())
+	}
+
+	output, err := os.OpenFile(filepath.Join(procDir, proc.GetName()+".supervisor.output"),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	cmd := exec.Command(s.executable(proc), proc.GetArgs()...)
+	cmd.Dir = workDir
+	cmd.Env = builder.BuildProcessEnviron(proc, os.Environ())
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// own process group not to receive signals sent to supervisor
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	pid := cmd.Process.Pid
+	if err = applyRlimits(pid, proc.GetResourceLimit().Rlimits); err != nil {
+		_ = signalProcess(pid, syscall.SIGKILL)
+		_ = cmd.Wait()
+		return err
+	}
+	if err = os.WriteFile(s.pidFile(proc), []byte(strconv.Itoa(pid)), 0644); err != nil {
+		log.Warn("fail to write pid file of %s : %s", proc.GetName(), err.Error())
+	}
+	p.pid, p.state, p.since, p.lastError, p.unhealthy = pid, StateRunning, time.Now(), "", nil
+	log.Info("%s started. pid=%d", proc.GetName(), pid)
+	s.watchHealth(p, pid)
+
+	if s.option.Detach {
+		p.done = nil
+		return cmd.Process.Release()
+	}
+
+	done := make(chan struct{})
+	p.done = done
+	go func() {
+		err := cmd.Wait()
+		close(done)
+		s.exited(p, pid, err)
+	}()
+	return nil
+}
+
+// exited handle exit of child process
+func (s *Supervisor) exited(p *supervisedProcess, pid int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if p.pid != pid {
+		return
+	}
+	if err == nil {
+		s.handleExit(p, errors.New("exited normally"), true)
+		return
+	}
+	s.handleExit(p, err, false)
+}
+
+// handleExit decide restart of exited process. caller should hold mutex
+func (s *Supervisor) handleExit(p *supervisedProcess, cause error, succeeded bool) {
+	name := p.proc.GetName()
+	p.done = nil
+	if p.unhealthy != nil {
+		cause, succeeded, p.unhealthy = p.unhealthy, false, nil
+	}
+	p.lastError = cause.Error()
+	if p.stopping || s.closed || !fileExists(s.pidFile(p.proc)) {
+		// stopped intentionally
+		p.state, p.pid = StateStopped, 0
+		return
+	}
+
+	policy := p.proc.GetRestartPolicy()
+	if policy.Policy == fatima.RestartNever || (policy.Policy == fatima.RestartOnFailure && succeeded) {
+		log.Warn("%s exited (%s). not restarted by policy %s", name, cause.Error(), policy.Policy)
+		p.state, p.pid = StateStopped, 0
+		_ = os.Remove(s.pidFile(p.proc))
+		return
+	}
+	if !p.since.IsZero() && time.Since(p.since) > restartResetAfter {
+		p.restarts = 0
+	}
+	if policy.MaxRestarts > 0 && p.restarts >= policy.MaxRestarts {
+		log.Error("%s exited (%s). restart limit %d exceeded", name, cause.Error(), policy.MaxRestarts)
+		p.state, p.pid = StateFailed, 0
+		return
+	}
+
+	p.restarts++
+	if policy.Backoff <= 0 {
+		policy.Backoff = defaultRestartBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultRestartMaxBackoff
+	}
+	delay := policy.NextBackoff(p.restarts)
+	log.Warn("%s exited (%s). restart #%d after %s", name, cause.Error(), p.restarts, delay)
+	p.state, p.pid = StateBackoff, 0
+	p.timer = time.AfterFunc(delay, func() {
+		s.restart(p)
+	})
+}
+
+func (s *Supervisor) restart(p *supervisedProcess) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	p.timer = nil
+	if p.stopping || s.closed || p.state != StateBackoff {
+		return
+	}
+	if err := s.launch(p); err != nil {
+		s.handleExit(p, err, false)
+	}
+}
+
+// stopProcess send SIGTERM and wait. SIGKILL is sent after StopTimeout
+func (s *Supervisor) stopProcess(ctx context.Context, proc fatima.FatimaPkgProc) error {
+	s.mutex.Lock()
+	p := s.procs[proc.GetName()]
+	if p == nil {
+		p = &supervisedProcess{proc: proc, state: StateStopped}
+		s.procs[proc.GetName()] = p
+	}
+	p.stopping = true
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	pid, done := p.pid, p.done
+	if pid == 0 {
+		pid, _ = s.readPid(proc)
+	}
+	// remove pid file first. it tells other supervisors that stop is intended
+	_ = os.Remove(s.pidFile(proc))
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		p.state, p.pid = StateStopped, 0
+		s.mutex.Unlock()
+	}()
+
+	if pid <= 0 || (done == nil && !s.isRunning(proc, pid)) {
+		return nil
+	}
+
+	log.Info("stopping %s. pid=%d", proc.GetName(), pid)
+	if err := signalProcess(pid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	if s.waitExit(ctx, proc, pid, done, s.option.StopTimeout) {
+		return nil
+	}
+
+	log.Warn("%s is not stopped in %s. kill", proc.GetName(), s.option.StopTimeout)
+	if err := signalProcess(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	if !s.waitExit(ctx, proc, pid, done, s.option.StopTimeout) {
+		return fmt.Errorf("pid %d is still running", pid)
+	}
+	return nil
+}
+
+// signalProcess send signal to process group of pid (launched with Setpgid) so that children are stopped too.
+// process which is not group leader (e.g. started by juno) gets signal alone
+func signalProcess(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		return syscall.Kill(-pid, sig)
+	}
+	return syscall.Kill(pid, sig)
+}
+
+func (s *Supervisor) waitExit(ctx context.Context, proc fatima.FatimaPkgProc, pid int, done chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(stopPollInterval)
+	defer ticker.Stop()
+	for {
+		if done == nil && !s.isRunning(proc, pid) {
+			return true
+		}
+		select {
+		case <-done:
+			return true
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// adopt manage processes which have pid file
+func (s *Supervisor) adopt() {
+	for _, proc := range s.config.GetAllProc(false) {
+		pid, err := s.readPid(proc)
+		if err != nil {
+			continue
+		}
+		s.mutex.Lock()
+		p := s.procs[proc.GetName()]
+		if p == nil {
+			p = &supervisedProcess{proc: proc, pid: pid, state: StateRunning, since: time.Now()}
+			s.procs[proc.GetName()] = p
+			log.Info("%s adopted. pid=%d", proc.GetName(), pid)
+			s.watchHealth(p, pid)
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// check liveness of adopted processes. child processes are watched by Wait
+func (s *Supervisor) check() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, p := range s.procs {
+		if p.state != StateRunning || p.done != nil || p.stopping {
+			continue
+		}
+		if pid, err := s.readPid(p.proc); err == nil && pid != p.pid {
+			// restarted by others
+			p.pid = pid
+			s.watchHealth(p, pid)
+		}
+		if p.pid > 0 && s.isRunning(p.proc, p.pid) {
+			continue
+		}
+		s.handleExit(p, errors.New("process is not running"), false)
+	}
+}
+
+func (s *Supervisor) isRunning(proc fatima.FatimaPkgProc, pid int) bool {
+	name := filepath.Base(s.executable(proc))
+	if runtime.GOOS == "linux" && len(name) > linuxCommLength {
+		name = name[:linuxCommLength]
+	}
+	return s.option.Platform.CheckProcessRunningByPid(name, pid)
+}
+
+// executable return binary of process. path (directory or file) of process or $FATIMA_HOME/app/{name}/{name}
+func (s *Supervisor) executable(proc fatima.FatimaPkgProc) string {
+	if len(proc.GetPath()) == 0 {
+		return filepath.Join(s.home, builder.FatimaFolderApp, proc.GetName(), proc.GetName())
+	}
+	path := s.resolvePath(proc.GetPath())
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, proc.GetName())
+	}
+	return path
+}
+
+func (s *Supervisor) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.home, path)
+}
+
+func (s *Supervisor) procFolder(proc fatima.FatimaPkgProc) string {
+	return filepath.Join(s.home, builder.FatimaFolderApp, proc.GetName(), builder.FatimaFolderProc)
+}
+
+func (s *Supervisor) pidFile(proc fatima.FatimaPkgProc) string {
+	return filepath.Join(s.procFolder(proc), proc.GetName()+".pid")
+}
+
+func (s *Supervisor) readPid(proc fatima.FatimaPkgProc) (int, error) {
+	b, err := os.ReadFile(s.pidFile(proc))
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid pid content : [%s]", string(b))
+	}
+	return pid, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2026 github.com/fatima-go
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * @project fatima-core
+ * @author dave
+ * @date 26. 10. 25. 오후 2:00
+ */
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fatima-go/fatima-core"
+	"github.com/fatima-go/fatima-core/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPlatform check liveness with signal 0 (process name is not checked)
+type testPlatform struct {
+	fatima.PlatformSupport
+}
+
+func (p testPlatform) CheckProcessRunningByPid(procName string, pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+func prepareSupervisor(t *testing.T, scripts map[string]string, processes ...builder.ProcessItem) *Supervisor {
+	home := t.TempDir()
+	for name, script := range scripts {
+		dir := filepath.Join(home, builder.FatimaFolderApp, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	}
+
+	config := &builder.YamlFatimaPackageConfig{
+		Groups:    []builder.GroupItem{{Id: 1, Name: "OPM"}, {Id: 2, Name: "SVC"}},
+		Processes: processes,
+	}
+	s := New(home, config, Option{Platform: testPlatform{}, CheckInterval: 20 * time.Millisecond, StopTimeout: time.Second})
+	t.Cleanup(func() {
+		s.Close()
+		_ = s.Stop(context.Background(), TargetAll)
+	})
+	return s
+}
+
+func statusOf(t *testing.T, s *Supervisor, name string) ProcessStatus {
+	list, err := s.Status(name)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	return list[0]
+}
+
+func TestSupervisorStartStop(t *testing.T) {
+	s := prepareSupervisor(t,
+		map[string]string{"jupiter": "exec sleep 30", "api": "echo $API_MODE\nexec sleep 30"},
+		builder.ProcessItem{Gid: 1, Name: "jupiter"},
+		builder.ProcessItem{Gid: 2, Name: "api", Env: map[string]string{"API_MODE": "test"}},
+		builder.ProcessItem{Gid: 2, Name: "tool", Startmode: fatima.StartModeAlone},
+	)
+
+	require.NoError(t, s.Start(context.Background(), TargetAll))
+	jupiter := statusOf(t, s, "jupiter")
+	assert.Equal(t, StateRunning, jupiter.State)
+	assert.Equal(t, "OPM", jupiter.Group)
+	pid, err := s.readPid(s.config.GetProcByName("jupiter"))
+	require.NoError(t, err)
+	assert.Equal(t, jupiter.Pid, pid)
+	assert.Equal(t, StateStopped, statusOf(t, s, "tool").State, "alone process is not started")
+
+	// starting again does not launch new process
+	require.NoError(t, s.Start(context.Background(), "jupiter"))
+	assert.Equal(t, jupiter.Pid, statusOf(t, s, "jupiter").Pid)
+
+	require.Eventually(t, func() bool {
+		out, _ := os.ReadFile(filepath.Join(s.procFolder(s.config.GetProcByName("api")), "api.supervisor.output"))
+		return string(out) == "test\n"
+	}, 2*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, s.Stop(context.Background(), "svc"))
+	assert.Equal(t, StateStopped, statusOf(t, s, "api").State)
+	assert.Equal(t, StateRunning, statusOf(t, s, "jupiter").State)
+	_, err = s.readPid(s.config.GetProcByName("api"))
+	assert.True(t, os.IsNotExist(err), "pid file should be removed on stop")
+
+	require.NoError(t, s.Stop(context.Background(), TargetAll))
+	assert.Equal(t, StateStopped, statusOf(t, s, "jupiter").State)
+	assert.Error(t, syscall.Kill(jupiter.Pid, 0))
+
+	_, err = s.Status("nothing")
+	assert.Error(t, err)
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	s := prepareSupervisor(t,
+		map[string]string{"crash": "exit 1", "done": "exit 0"},
+		builder.ProcessItem{Gid: 2, Name: "crash", Restart: &builder.ProcessRestartItem{Max: 2, Backoff: "10ms"}},
+		builder.ProcessItem{Gid: 2, Name: "done", Restart: &builder.ProcessRestartItem{Policy: fatima.RestartOnFailure, Backoff: "10ms"}},
+	)
+
+	require.NoError(t, s.Start(context.Background(), TargetAll))
+	require.Eventually(t, func() bool {
+		return statusOf(t, s, "crash").State == StateFailed
+	}, 3*time.Second, 10*time.Millisecond)
+	crash := statusOf(t, s, "crash")
+	assert.Equal(t, 2, crash.Restarts)
+	assert.Equal(t, "exit status 1", crash.LastError)
+
+	require.Eventually(t, func() bool {
+		return statusOf(t, s, "done").State == StateStopped
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, statusOf(t, s, "done").Restarts, "normal exit is not restarted by on-failure policy")
+}
+
+func TestSupervisorAdopt(t *testing.T) {
+	s := prepareSupervisor(t,
+		map[string]string{"worker": "exec sleep 30"},
+		builder.ProcessItem{Gid: 2, Name: "worker", Restart: &builder.ProcessRestartItem{Backoff: "10ms"}},
+	)
+
+	// process started by detached supervisor (command line) is adopted by Run
+	detached := New(s.home, s.config, Option{Platform: testPlatform{}, Detach: true})
+	require.NoError(t, detached.Start(context.Background(), "worker"))
+	pid := statusOf(t, detached, "worker").Pid
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		return statusOf(t, s, "worker").Pid == pid
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// crashed (killed) process is restarted
+	require.NoError(t, syscall.Kill(pid, syscall.SIGKILL))
+	if p, err := os.FindProcess(pid); err == nil {
+		_, _ = p.Wait() // reap. detached process is still child of test
+	}
+	require.Eventually(t, func() bool {
+		st := statusOf(t, s, "worker")
+		return st.State == StateRunning && st.Pid != pid && st.Pid > 0
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestSupervisorHealthCheck(t *testing.T) {
+	s := prepareSupervisor(t,
+		map[string]string{"healthy": "exec sleep 30", "sick": "exec sleep 30"},
+		builder.ProcessItem{Gid: 2, Name: "healthy",
+			Health: &builder.ProcessHealthItem{Type: fatima.HealthCheckExec, Target: "true", Interval: "20ms"}},
+		builder.ProcessItem{Gid: 2, Name: "sick", Restart: &builder.ProcessRestartItem{Backoff: "10ms"},
+			Health: &builder.ProcessHealthItem{Type: fatima.HealthCheckExec, Target: "echo broken; exit 1", Interval: "20ms", Retries: 2}},
+	)
+
+	require.NoError(t, s.Start(context.Background(), TargetAll))
+	healthy := statusOf(t, s, "healthy").Pid
+	sick := statusOf(t, s, "sick").Pid
+
+	// unhealthy process is stopped and restarted by restart policy
+	require.Eventually(t, func() bool {
+		return statusOf(t, s, "sick").Restarts > 0
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Contains(t, statusOf(t, s, "sick").LastError, "unhealthy : exit status 1 : broken")
+	assert.Error(t, syscall.Kill(sick, 0))
+
+	st := statusOf(t, s, "healthy")
+	assert.Equal(t, StateRunning, st.State)
+	assert.Equal(t, healthy, st.Pid)
+}